@@ -0,0 +1,145 @@
+// Command catalog-export dumps the movie catalog of the metadata service to a CSV, JSON Lines or
+// Parquet file through the server-streaming ExportMetadata RPC, optionally joined with the
+// aggregated ratings of the rating service.
+//
+//	catalog-export -addr localhost:8081 -rating-addr localhost:8082 -o catalog.parquet
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"movieexample.com/gen"
+	"movieexample.com/metadata/pkg/model"
+	ratingmodel "movieexample.com/rating/pkg/model"
+)
+
+// ratingBatchSize is the number of movies whose ratings are read per round trip, the most the
+// rating service accepts.
+const ratingBatchSize = 1000
+
+func main() {
+	addr := flag.String("addr", "localhost:8081", "address of the metadata service")
+	ratingAddr := flag.String("rating-addr", "", "address of the rating service; aggregated ratings are exported when set")
+	output := flag.String("o", "", "output file; standard output when empty")
+	format := flag.String("format", "", "output format, csv, jsonl or parquet; guessed from the output file extension when empty")
+	batchSize := flag.Int("batch-size", 500, "number of records read per round trip")
+	flag.Parse()
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*output)), ".")
+	}
+	if *format == "" {
+		*format = "jsonl"
+	}
+
+	opts := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.NewClient(*addr, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	var ratings gen.RatingServiceClient
+	if *ratingAddr != "" {
+		ratingConn, err := grpc.NewClient(*ratingAddr, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer ratingConn.Close()
+		ratings = gen.NewRatingServiceClient(ratingConn)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w, err := newWriter(out, *format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	n, err := export(context.Background(), gen.NewMetadataServiceClient(conn), ratings, int32(*batchSize), w)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Exported %d movies", n)
+}
+
+// export streams the catalog into w a batch at a time, looking up the aggregated ratings of every
+// batch when ratings is not nil. It returns the number of exported movies.
+func export(ctx context.Context, metadata gen.MetadataServiceClient, ratings gen.RatingServiceClient, batchSize int32, w recordWriter) (int, error) {
+	stream, err := metadata.ExportMetadata(ctx, &gen.ExportMetadataRequest{BatchSize: batchSize})
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		} else if err != nil {
+			return n, err
+		}
+
+		var ratingsByID map[string]*gen.GetAggregatedRatingResponse
+		if ratings != nil {
+			if ratingsByID, err = aggregatedRatings(ctx, ratings, res.Metadata); err != nil {
+				return n, err
+			}
+		}
+		for _, m := range res.Metadata {
+			r := newRecord(model.MetadataFromProto(m))
+			if agg, ok := ratingsByID[m.Id]; ok {
+				v := agg.RatingValue
+				r.Rating = &v
+			}
+			if err := w.Write(r); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+}
+
+// aggregatedRatings returns the aggregated ratings of a batch of movies by ID, with one rating
+// RPC per ratingBatchSize movies. Movies that have not been rated are left out.
+func aggregatedRatings(ctx context.Context, ratings gen.RatingServiceClient, movies []*gen.Metadata) (map[string]*gen.GetAggregatedRatingResponse, error) {
+	res := make(map[string]*gen.GetAggregatedRatingResponse, len(movies))
+	for batch := range slices.Chunk(movies, ratingBatchSize) {
+		ids := make([]string, len(batch))
+		for i, m := range batch {
+			ids[i] = m.Id
+		}
+		got, err := ratings.BatchGetAggregatedRatings(ctx, &gen.BatchGetAggregatedRatingsRequest{
+			RecordIds:  ids,
+			RecordType: string(ratingmodel.RecordTypeMovie),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get ratings of %s to %s: %w", ids[0], ids[len(ids)-1], err)
+		}
+		maps.Copy(res, got.Ratings)
+	}
+	return res, nil
+}
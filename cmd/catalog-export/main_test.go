@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"movieexample.com/metadata/pkg/model"
+)
+
+var heat = &model.Metadata{
+	ID:             "1",
+	Title:          "Heat",
+	Director:       "Michael Mann",
+	Genres:         []string{"Crime", "Thriller"},
+	Credits:        []model.Credit{{Name: "Al Pacino", Role: model.CreditRoleActor, Character: "Vincent Hanna", Order: 1}},
+	RuntimeMinutes: 170,
+	ReleaseDate:    time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC),
+	ExternalIDs:    []model.ExternalID{{Provider: model.ExternalIDProviderIMDb, ID: "tt0113277"}, {Provider: model.ExternalIDProviderTMDb, ID: "949"}},
+	Version:        3,
+}
+
+func writeRecords(t *testing.T, format string, records ...record) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := newWriter(&buf, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestWriteCSV(t *testing.T) {
+	rating := 4.5
+	r := newRecord(heat)
+	r.Rating = &rating
+
+	got := writeRecords(t, "csv", r, newRecord(&model.Metadata{ID: "2", Title: "Unrated"})).String()
+	want := `id,title,description,director,genres,credits,runtime_minutes,release_date,original_language,country,external_ids,version,rating
+1,Heat,,Michael Mann,Crime|Thriller,"[{""name"":""Al Pacino"",""role"":""actor"",""character"":""Vincent Hanna"",""order"":1}]",170,1995-12-15,,,imdb:tt0113277|tmdb:949,3,4.5
+2,Unrated,,,,,,,,,,0,
+`
+	if got != want {
+		t.Errorf("csv export =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	got := writeRecords(t, "jsonl", newRecord(heat), newRecord(heat)).String()
+	if lines := strings.Split(strings.TrimSpace(got), "\n"); len(lines) != 2 {
+		t.Fatalf("jsonl export has %d lines, want 2", len(lines))
+	}
+	if !strings.Contains(got, `"release_date":"1995-12-15T00:00:00Z"`) || strings.Contains(got, `"rating"`) {
+		t.Errorf("jsonl export = %s", got)
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	rating := 4.5
+	rated := newRecord(heat)
+	rated.Rating = &rating
+	unrated := newRecord(&model.Metadata{ID: "2", Title: "Unrated"})
+
+	buf := writeRecords(t, "parquet", rated, unrated)
+	got, err := parquet.Read[parquetRecord](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []parquetRecord{
+		{
+			ID:             "1",
+			Title:          "Heat",
+			Director:       "Michael Mann",
+			Genres:         []string{"Crime", "Thriller"},
+			Credits:        []credit{{Name: "Al Pacino", Role: "actor", Character: "Vincent Hanna", Order: 1}},
+			RuntimeMinutes: 170,
+			ReleaseDate:    9479,
+			ExternalIDs:    []externalID{{Provider: "imdb", ID: "tt0113277"}, {Provider: "tmdb", ID: "949"}},
+			Version:        3,
+			Rating:         &rating,
+		},
+		{ID: "2", Title: "Unrated", Genres: []string{}, Credits: []credit{}, ExternalIDs: []externalID{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parquet export = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	if _, err := newWriter(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("newWriter() error = nil for an unknown format")
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// parquetRowGroupSize is the number of rows buffered before a Parquet row group is flushed,
+// which bounds the memory held by the writer.
+const parquetRowGroupSize = 10000
+
+const secondsPerDay = 24 * 60 * 60
+
+// record is one exported movie. Rating is the aggregated rating and is only set when ratings
+// are exported and the movie has been rated.
+type record struct {
+	ID               string       `json:"id"`
+	Title            string       `json:"title"`
+	Description      string       `json:"description"`
+	Director         string       `json:"director"`
+	Genres           []string     `json:"genres,omitempty"`
+	Credits          []credit     `json:"credits,omitempty"`
+	RuntimeMinutes   int32        `json:"runtime_minutes,omitempty"`
+	ReleaseDate      *time.Time   `json:"release_date,omitempty"`
+	OriginalLanguage string       `json:"original_language,omitempty"`
+	Country          string       `json:"country,omitempty"`
+	ExternalIDs      []externalID `json:"external_ids,omitempty"`
+	Version          int64        `json:"version"`
+	Rating           *float64     `json:"rating,omitempty"`
+}
+
+// parquetRecord is the Parquet row of a record. The release date uses the DATE logical type, which
+// counts days since the Unix epoch; optional zero values are written as null, so day zero itself
+// cannot be told apart from an unknown date.
+type parquetRecord struct {
+	ID               string       `parquet:"id"`
+	Title            string       `parquet:"title"`
+	Description      string       `parquet:"description"`
+	Director         string       `parquet:"director"`
+	Genres           []string     `parquet:"genres,list"`
+	Credits          []credit     `parquet:"credits,list"`
+	RuntimeMinutes   int32        `parquet:"runtime_minutes"`
+	ReleaseDate      int32        `parquet:"release_date,optional,date"`
+	OriginalLanguage string       `parquet:"original_language"`
+	Country          string       `parquet:"country"`
+	ExternalIDs      []externalID `parquet:"external_ids,list"`
+	Version          int64        `parquet:"version"`
+	Rating           *float64     `parquet:"rating,optional"`
+}
+
+type credit struct {
+	Name      string `json:"name" parquet:"name"`
+	Role      string `json:"role" parquet:"role"`
+	Character string `json:"character,omitempty" parquet:"character"`
+	Order     int32  `json:"order" parquet:"order"`
+}
+
+type externalID struct {
+	Provider string `json:"provider" parquet:"provider"`
+	ID       string `json:"id" parquet:"id"`
+}
+
+func newRecord(m *model.Metadata) record {
+	r := record{
+		ID:               m.ID,
+		Title:            m.Title,
+		Description:      m.Description,
+		Director:         m.Director,
+		Genres:           m.Genres,
+		RuntimeMinutes:   m.RuntimeMinutes,
+		OriginalLanguage: m.OriginalLanguage,
+		Country:          m.Country,
+		Version:          m.Version,
+	}
+	if !m.ReleaseDate.IsZero() {
+		d := m.ReleaseDate.UTC()
+		r.ReleaseDate = &d
+	}
+	for _, c := range m.Credits {
+		r.Credits = append(r.Credits, credit{Name: c.Name, Role: string(c.Role), Character: c.Character, Order: c.Order})
+	}
+	for _, e := range m.ExternalIDs {
+		r.ExternalIDs = append(r.ExternalIDs, externalID{Provider: string(e.Provider), ID: e.ID})
+	}
+	return r
+}
+
+// recordWriter writes exported records in one file format.
+type recordWriter interface {
+	Write(r record) error
+	// Close flushes buffered records. It does not close the underlying writer.
+	Close() error
+}
+
+// newWriter returns the writer of the given format, one of csv, jsonl or parquet.
+func newWriter(w io.Writer, format string) (recordWriter, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(w), nil
+	case "jsonl", "ndjson":
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case "parquet":
+		return &parquetWriter{w: parquet.NewGenericWriter[parquetRecord](w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, want csv, jsonl or parquet", format)
+	}
+}
+
+// csvColumns is the header of CSV exports. It matches the default column names of catalog-import,
+// so an export can be imported again as is.
+var csvColumns = []string{
+	"id", "title", "description", "director", "genres", "credits", "runtime_minutes",
+	"release_date", "original_language", "country", "external_ids", "version", "rating",
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(r record) error {
+	if !c.header {
+		if err := c.w.Write(csvColumns); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	var credits, releaseDate, rating, runtime string
+	externalIDs := make([]string, 0, len(r.ExternalIDs))
+	for _, e := range r.ExternalIDs {
+		externalIDs = append(externalIDs, e.Provider+":"+e.ID)
+	}
+	if len(r.Credits) > 0 {
+		b, err := json.Marshal(r.Credits)
+		if err != nil {
+			return err
+		}
+		credits = string(b)
+	}
+	if r.ReleaseDate != nil {
+		releaseDate = r.ReleaseDate.Format(time.DateOnly)
+	}
+	if r.Rating != nil {
+		rating = strconv.FormatFloat(*r.Rating, 'f', -1, 64)
+	}
+	if r.RuntimeMinutes != 0 {
+		runtime = strconv.Itoa(int(r.RuntimeMinutes))
+	}
+
+	return c.w.Write([]string{
+		r.ID,
+		r.Title,
+		r.Description,
+		r.Director,
+		strings.Join(r.Genres, "|"),
+		credits,
+		runtime,
+		releaseDate,
+		r.OriginalLanguage,
+		r.Country,
+		strings.Join(externalIDs, "|"),
+		strconv.FormatInt(r.Version, 10),
+		rating,
+	})
+}
+
+func (c *csvWriter) Close() error {
+	if !c.header {
+		if err := c.w.Write(csvColumns); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(r record) error {
+	return j.enc.Encode(r)
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+type parquetWriter struct {
+	w    *parquet.GenericWriter[parquetRecord]
+	rows int
+}
+
+func (p *parquetWriter) Write(r record) error {
+	row := parquetRecord{
+		ID:               r.ID,
+		Title:            r.Title,
+		Description:      r.Description,
+		Director:         r.Director,
+		Genres:           r.Genres,
+		Credits:          r.Credits,
+		RuntimeMinutes:   r.RuntimeMinutes,
+		OriginalLanguage: r.OriginalLanguage,
+		Country:          r.Country,
+		ExternalIDs:      r.ExternalIDs,
+		Version:          r.Version,
+		Rating:           r.Rating,
+	}
+	if r.ReleaseDate != nil {
+		row.ReleaseDate = int32(r.ReleaseDate.Unix() / secondsPerDay)
+	}
+	if _, err := p.w.Write([]parquetRecord{row}); err != nil {
+		return err
+	}
+	p.rows++
+	if p.rows%parquetRowGroupSize == 0 {
+		return p.w.Flush()
+	}
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	return p.w.Close()
+}
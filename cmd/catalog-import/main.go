@@ -0,0 +1,142 @@
+// Command catalog-import bulk loads movie metadata from CSV or JSON Lines files into the
+// metadata service through the client-streaming ImportMetadata RPC.
+//
+//	catalog-import -addr localhost:8081 -map title=name,release_date=released movies.csv
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcmetadata "google.golang.org/grpc/metadata"
+	"movieexample.com/gen"
+	"movieexample.com/internal/grpcutil"
+	"movieexample.com/metadata/pkg/model"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8081", "address of the metadata service")
+	format := flag.String("format", "", "input format, csv or jsonl; guessed from the file extension when empty")
+	columns := flag.String("map", "", "comma separated field=column pairs naming the column each metadata field is read from")
+	author := flag.String("author", "", "author recorded in the revision history of the imported movies")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: catalog-import [flags] file...")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	cols, err := parseMapping(*columns)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	client := gen.NewMetadataServiceClient(conn)
+
+	ctx := context.Background()
+	if *author != "" {
+		ctx = grpcmetadata.AppendToOutgoingContext(ctx, grpcutil.AuthorHeader, *author)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		summary, err := importFile(ctx, client, path, *format, cols)
+		if err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+		for _, e := range summary.Errors {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s: %s\n", path, e.Row, e.MovieID, e.Message)
+		}
+		if n := summary.Failed - int64(len(summary.Errors)); n > 0 {
+			fmt.Fprintf(os.Stderr, "%s: %d more errors not shown\n", path, n)
+		}
+		fmt.Printf("%s: %d rows, %d imported, %d failed\n", path, summary.Received, summary.Imported, summary.Failed)
+		failed = failed || summary.Failed > 0
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// importFile streams the rows of one file to the metadata service. Rows that cannot be parsed
+// are not sent; they are merged into the summary returned by the service.
+func importFile(ctx context.Context, client gen.MetadataServiceClient, path, format string, cols mapping) (model.ImportSummary, error) {
+	read, err := readerFor(path, format)
+	if err != nil {
+		return model.ImportSummary{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return model.ImportSummary{}, err
+	}
+	defer f.Close()
+
+	stream, err := client.ImportMetadata(ctx)
+	if err != nil {
+		return model.ImportSummary{}, err
+	}
+
+	var local []model.ImportError
+	err = read(f, cols, func(row int64, m *model.Metadata, err error) error {
+		if err != nil {
+			var id string
+			if m != nil {
+				id = m.ID
+			}
+			local = append(local, model.ImportError{Row: row, MovieID: id, Message: err.Error()})
+			return nil
+		}
+		return stream.Send(&gen.ImportMetadataRequest{Metadata: model.MetadataToProto(m), Row: row})
+	})
+	if err != nil {
+		return model.ImportSummary{}, err
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return model.ImportSummary{}, err
+	}
+
+	summary := model.ImportSummaryFromProto(res)
+	summary.Received += int64(len(local))
+	summary.Failed += int64(len(local))
+	summary.Errors = append(summary.Errors, local...)
+	slices.SortFunc(summary.Errors, func(a, b model.ImportError) int {
+		return cmp.Compare(a.Row, b.Row)
+	})
+
+	return summary, nil
+}
+
+// readerFor picks the reader of a file from the format, or from the file extension if no format is given.
+func readerFor(path, format string) (func(io.Reader, mapping, emitFunc) error, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format {
+	case "csv":
+		return readCSV, nil
+	case "jsonl", "ndjson":
+		return readJSONL, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, want csv or jsonl", format)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"movieexample.com/metadata/pkg/model"
+)
+
+type row struct {
+	line int64
+	m    *model.Metadata
+	err  bool
+}
+
+func collect(t *testing.T, read func(string, mapping, emitFunc) error, input string, cols mapping) []row {
+	t.Helper()
+	var rows []row
+	err := read(input, cols, func(line int64, m *model.Metadata, err error) error {
+		rows = append(rows, row{line: line, m: m, err: err != nil})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestReadCSV(t *testing.T) {
+	cols, err := parseMapping("id=movie_id, title=name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := `movie_id,name,genres,runtime_minutes,release_date,credits,external_ids
+1,Heat,Crime|Thriller,170,1995-12-15,"[{""name"":""Al Pacino"",""role"":""actor"",""order"":1}]",imdb:tt0113277|tmdb:949
+2,Broken,,not a number,,,
+3,Unknown,,,,,tt0113277
+`
+	rows := collect(t, func(s string, c mapping, emit emitFunc) error {
+		return readCSV(strings.NewReader(s), c, emit)
+	}, input, cols)
+
+	want := []row{
+		{line: 2, m: &model.Metadata{
+			ID:             "1",
+			Title:          "Heat",
+			Genres:         []string{"Crime", "Thriller"},
+			RuntimeMinutes: 170,
+			ReleaseDate:    time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC),
+			Credits:        []model.Credit{{Name: "Al Pacino", Role: model.CreditRoleActor, Order: 1}},
+			ExternalIDs:    []model.ExternalID{{Provider: model.ExternalIDProviderIMDb, ID: "tt0113277"}, {Provider: model.ExternalIDProviderTMDb, ID: "949"}},
+		}},
+		{line: 3, err: true},
+		{line: 4, err: true},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("readCSV() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestReadCSVMissingColumn(t *testing.T) {
+	cols := mapping{model.FieldTitle: "name"}
+	err := readCSV(strings.NewReader("id,title\n1,Heat\n"), cols, func(int64, *model.Metadata, error) error { return nil })
+	if err == nil {
+		t.Error("readCSV() error = nil, want an error for the missing name column")
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	cols := mapping{model.FieldDirector: "directed_by"}
+	input := `{"id": "1", "title": "Heat", "directed_by": "Michael Mann", "release_date": "1995-12-15", "director": "ignored"}
+
+{"id": "2", "title": 3}
+not json
+`
+	rows := collect(t, func(s string, c mapping, emit emitFunc) error {
+		return readJSONL(strings.NewReader(s), c, emit)
+	}, input, cols)
+
+	want := []row{
+		{line: 1, m: &model.Metadata{
+			ID:          "1",
+			Title:       "Heat",
+			Director:    "Michael Mann",
+			ReleaseDate: time.Date(1995, 12, 15, 0, 0, 0, 0, time.UTC),
+		}},
+		{line: 3, err: true},
+		{line: 4, err: true},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("readJSONL() = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	if _, err := parseMapping("rating=stars"); err == nil {
+		t.Error("parseMapping() error = nil for an unknown field")
+	}
+	if _, err := parseMapping("title"); err == nil {
+		t.Error("parseMapping() error = nil for a pair without column")
+	}
+}
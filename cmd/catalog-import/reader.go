@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"movieexample.com/metadata/pkg/model"
+)
+
+// fieldID names the id column, which is not an updatable field but is read like one.
+const fieldID = model.Field("id")
+
+// genreSeparator separates the genres of a movie in a single CSV cell.
+const genreSeparator = "|"
+
+// externalIDSeparator separates the provider from the ID of an external ID in a CSV cell,
+// as in "imdb:tt0111161|tmdb:278".
+const externalIDSeparator = ":"
+
+// mapping maps metadata fields to the names of the columns or keys they are read from.
+// Fields without an entry are read from a column named after the field.
+type mapping map[model.Field]string
+
+// parseMapping parses a comma separated list of field=column pairs.
+func parseMapping(s string) (mapping, error) {
+	res := mapping{}
+	if strings.TrimSpace(s) == "" {
+		return res, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return nil, fmt.Errorf("invalid mapping %q, want field=column", pair)
+		}
+		f := model.Field(field)
+		if f != fieldID && !slices.Contains(model.Fields, f) {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+		res[f] = column
+	}
+	return res, nil
+}
+
+func (m mapping) column(f model.Field) string {
+	if c, ok := m[f]; ok {
+		return c
+	}
+	return string(f)
+}
+
+// sourceFields lists the id followed by every metadata field.
+var sourceFields = append([]model.Field{fieldID}, model.Fields...)
+
+// emitFunc receives every row read from the input. A row that could not be parsed comes with
+// a nil record and the parse error. Returning an error stops reading.
+type emitFunc func(row int64, m *model.Metadata, err error) error
+
+// readCSV reads movies from a CSV file with a header row. Rows are numbered by their line in the file.
+// Genres are separated by "|" and credits are given as a JSON array.
+func readCSV(r io.Reader, cols mapping, emit emitFunc) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	for f, c := range cols {
+		if _, ok := index[c]; !ok {
+			return fmt.Errorf("column %q mapped to %s is missing from the header", c, f)
+		}
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if err := emit(int64(parseErr.StartLine), nil, err); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		line, _ := cr.FieldPos(0)
+		m, err := parseCSVRecord(record, index, cols)
+		if err := emit(int64(line), m, err); err != nil {
+			return err
+		}
+	}
+}
+
+func parseCSVRecord(record []string, index map[string]int, cols mapping) (*model.Metadata, error) {
+	m := &model.Metadata{}
+	for _, f := range sourceFields {
+		i, ok := index[cols.column(f)]
+		if !ok || i >= len(record) {
+			continue
+		}
+		v := strings.TrimSpace(record[i])
+		if v == "" {
+			continue
+		}
+		if err := setField(m, f, v); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// setField parses the textual value of a CSV cell into the field.
+func setField(m *model.Metadata, f model.Field, v string) error {
+	switch f {
+	case fieldID:
+		m.ID = v
+	case model.FieldTitle:
+		m.Title = v
+	case model.FieldDescription:
+		m.Description = v
+	case model.FieldDirector:
+		m.Director = v
+	case model.FieldGenres:
+		for _, g := range strings.Split(v, genreSeparator) {
+			if g = strings.TrimSpace(g); g != "" {
+				m.Genres = append(m.Genres, g)
+			}
+		}
+	case model.FieldCredits:
+		if err := json.Unmarshal([]byte(v), &m.Credits); err != nil {
+			return fmt.Errorf("invalid credits: %w", err)
+		}
+	case model.FieldRuntimeMinutes:
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid runtime_minutes %q", v)
+		}
+		m.RuntimeMinutes = int32(n)
+	case model.FieldReleaseDate:
+		t, err := parseDate(v)
+		if err != nil {
+			return err
+		}
+		m.ReleaseDate = t
+	case model.FieldOriginalLanguage:
+		m.OriginalLanguage = v
+	case model.FieldCountry:
+		m.Country = v
+	case model.FieldExternalIDs:
+		for _, e := range strings.Split(v, genreSeparator) {
+			if e = strings.TrimSpace(e); e == "" {
+				continue
+			}
+			provider, id, ok := strings.Cut(e, externalIDSeparator)
+			if !ok {
+				return fmt.Errorf("invalid external_ids entry %q, want provider:id", e)
+			}
+			m.ExternalIDs = append(m.ExternalIDs, model.ExternalID{Provider: model.ExternalIDProvider(provider), ID: id})
+		}
+	}
+	return nil
+}
+
+// parseDate accepts plain dates as well as RFC 3339 timestamps.
+func parseDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid release_date %q, want YYYY-MM-DD", v)
+	}
+	return t, nil
+}
+
+// readJSONL reads one JSON object per line, with keys renamed according to cols.
+// Blank lines are skipped; rows are numbered by their line in the file.
+func readJSONL(r io.Reader, cols mapping, emit emitFunc) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var line int64
+	for sc.Scan() {
+		line++
+		b := sc.Bytes()
+		if strings.TrimSpace(string(b)) == "" {
+			continue
+		}
+		m, err := parseJSONRecord(b, cols)
+		if err := emit(line, m, err); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+func parseJSONRecord(b []byte, cols mapping) (*model.Metadata, error) {
+	var in map[string]json.RawMessage
+	if err := json.Unmarshal(b, &in); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	out := make(map[string]json.RawMessage, len(in))
+	for _, f := range sourceFields {
+		if v, ok := in[cols.column(f)]; ok {
+			out[string(f)] = v
+		}
+	}
+
+	// release dates are commonly written without a time, which time.Time does not decode
+	var releaseDate string
+	if v, ok := out[string(model.FieldReleaseDate)]; ok {
+		if err := json.Unmarshal(v, &releaseDate); err != nil {
+			return nil, fmt.Errorf("invalid release_date: %w", err)
+		}
+		delete(out, string(model.FieldReleaseDate))
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	m := &model.Metadata{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("invalid record: %w", err)
+	}
+	if releaseDate != "" {
+		if m.ReleaseDate, err = parseDate(releaseDate); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
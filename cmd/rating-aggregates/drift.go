@@ -0,0 +1,97 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	dbGen "movieexample.com/gen/db"
+)
+
+// record identifies the ratings of one record.
+type record struct {
+	recordType string
+	recordID   string
+}
+
+// totals are the running totals of the ratings of a record, as stored or as computed.
+type totals struct {
+	count     int64
+	sum       int64
+	histogram map[int32]int64
+}
+
+// drift is a record whose stored aggregate differs from the one computed from its ratings.
+type drift struct {
+	record
+	stored, actual totals
+}
+
+func (d drift) String() string {
+	return fmt.Sprintf("%s/%s: stored count %d sum %d histogram %v, actual count %d sum %d histogram %v",
+		d.recordType, d.recordID,
+		d.stored.count, d.stored.sum, d.stored.histogram,
+		d.actual.count, d.actual.sum, d.actual.histogram)
+}
+
+// storedTotals collects the stored aggregates and histograms by record.
+func storedTotals(aggregates []dbGen.RatingAggregate, histograms []dbGen.RatingHistogram) map[record]totals {
+	res := map[record]totals{}
+	for _, a := range aggregates {
+		res[record{a.RecordType, a.RecordID}] = totals{count: a.RatingsCount, sum: a.RatingsSum, histogram: map[int32]int64{}}
+	}
+	for _, h := range histograms {
+		key := record{h.RecordType, h.RecordID}
+		t, ok := res[key]
+		if !ok {
+			t = totals{histogram: map[int32]int64{}}
+			res[key] = t
+		}
+		t.histogram[h.Value] = h.Ratings
+	}
+
+	return res
+}
+
+// computedTotals derives the totals of every record from its histogram computed from the ratings.
+func computedTotals(rows []dbGen.ComputeRatingHistogramsRow) map[record]totals {
+	res := map[record]totals{}
+	for _, row := range rows {
+		key := record{row.RecordType, row.RecordID}
+		t, ok := res[key]
+		if !ok {
+			t = totals{histogram: map[int32]int64{}}
+		}
+		t.count += row.Ratings
+		t.sum += row.Ratings * int64(row.Value)
+		t.histogram[row.Value] = row.Ratings
+		res[key] = t
+	}
+
+	return res
+}
+
+// compare returns the records whose stored and actual totals differ, ordered by record type and
+// ID. A record missing on either side counts as having no ratings there.
+func compare(stored, actual map[record]totals) []drift {
+	keys := slices.Collect(maps.Keys(stored))
+	for key := range actual {
+		if _, ok := stored[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b record) int {
+		return cmp.Or(cmp.Compare(a.recordType, b.recordType), cmp.Compare(a.recordID, b.recordID))
+	})
+
+	var res []drift
+	for _, key := range keys {
+		s, a := stored[key], actual[key]
+		if s.count != a.count || s.sum != a.sum || !maps.Equal(s.histogram, a.histogram) {
+			res = append(res, drift{record: key, stored: s, actual: a})
+		}
+	}
+
+	return res
+}
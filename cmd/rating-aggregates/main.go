@@ -0,0 +1,92 @@
+// Command rating-aggregates recomputes the rating aggregates the rating service maintains in
+// PostgreSQL from the raw ratings, reports every record whose stored aggregate drifted from them
+// and rebuilds the aggregates if any did. Writes to ratings are blocked while it runs.
+//
+//	rating-aggregates -dsn "host=localhost port=5432 dbname=movies user=postgres sslmode=disable" -dry-run
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	dbGen "movieexample.com/gen/db"
+)
+
+func main() {
+	dsn := flag.String("dsn", "host=localhost port=5432 dbname=movies user=postgres sslmode=disable", "PostgreSQL connection string of the rating database")
+	dryRun := flag.Bool("dry-run", false, "only report drift, without rebuilding the aggregates; exits with status 1 if any is found")
+	flag.Parse()
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, *dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	drifts, err := rebuild(ctx, db, *dryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, d := range drifts {
+		fmt.Println(d)
+	}
+
+	switch {
+	case len(drifts) == 0:
+		fmt.Println("no drift found")
+	case *dryRun:
+		fmt.Printf("%d records drifted\n", len(drifts))
+		os.Exit(1)
+	default:
+		fmt.Printf("%d records drifted, aggregates rebuilt\n", len(drifts))
+	}
+}
+
+// rebuild compares the stored aggregates with the raw ratings in one transaction that blocks
+// writes to them, and replaces all aggregates with recomputed ones if any drifted, unless dryRun.
+func rebuild(ctx context.Context, db *pgxpool.Pool, dryRun bool) ([]drift, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op once committed
+
+	q := dbGen.New(tx)
+	if err := q.LockRatingTables(ctx); err != nil {
+		return nil, err
+	}
+	aggregates, err := q.ListRatingAggregates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	histograms, err := q.ListRatingHistograms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	computed, err := q.ComputeRatingHistograms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := compare(storedTotals(aggregates, histograms), computedTotals(computed))
+	if dryRun || len(drifts) == 0 {
+		return drifts, nil
+	}
+
+	if err := q.ClearRatingAggregates(ctx); err != nil {
+		return nil, err
+	}
+	if err := q.RebuildRatingAggregates(ctx); err != nil {
+		return nil, err
+	}
+	if err := q.RebuildRatingHistograms(ctx); err != nil {
+		return nil, err
+	}
+
+	return drifts, tx.Commit(ctx)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	dbGen "movieexample.com/gen/db"
+)
+
+func TestCompare(t *testing.T) {
+	stored := storedTotals(
+		[]dbGen.RatingAggregate{
+			{RecordID: "1", RecordType: "movie", RatingsCount: 3, RatingsSum: 12},
+			{RecordID: "2", RecordType: "movie", RatingsCount: 2, RatingsSum: 9},
+			{RecordID: "3", RecordType: "movie"},
+			{RecordID: "4", RecordType: "movie", RatingsCount: 1, RatingsSum: 4},
+		},
+		[]dbGen.RatingHistogram{
+			{RecordID: "1", RecordType: "movie", Value: 4, Ratings: 3},
+			{RecordID: "2", RecordType: "movie", Value: 4, Ratings: 1},
+			{RecordID: "2", RecordType: "movie", Value: 5, Ratings: 1},
+			{RecordID: "4", RecordType: "movie", Value: 4, Ratings: 1},
+		},
+	)
+	actual := computedTotals([]dbGen.ComputeRatingHistogramsRow{
+		{RecordID: "1", RecordType: "movie", Value: 4, Ratings: 3},
+		{RecordID: "2", RecordType: "movie", Value: 4, Ratings: 1},
+		{RecordID: "2", RecordType: "movie", Value: 5, Ratings: 2},
+		{RecordID: "4", RecordType: "movie", Value: 5, Ratings: 1},
+		{RecordID: "5", RecordType: "movie", Value: 1, Ratings: 1},
+	})
+
+	got := compare(stored, actual)
+	want := []drift{
+		{
+			record: record{"movie", "2"},
+			stored: totals{count: 2, sum: 9, histogram: map[int32]int64{4: 1, 5: 1}},
+			actual: totals{count: 3, sum: 14, histogram: map[int32]int64{4: 1, 5: 2}},
+		},
+		{
+			record: record{"movie", "4"},
+			stored: totals{count: 1, sum: 4, histogram: map[int32]int64{4: 1}},
+			actual: totals{count: 1, sum: 5, histogram: map[int32]int64{5: 1}},
+		},
+		{
+			record: record{"movie", "5"},
+			actual: totals{count: 1, sum: 1, histogram: map[int32]int64{1: 1}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("compare() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,398 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: aggregates.sql
+
+package dbGen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addToRatingAggregate = `-- name: AddToRatingAggregate :exec
+UPDATE rating_aggregates
+SET ratings_count = ratings_count + $1,
+    ratings_sum = ratings_sum + $2
+WHERE record_id = $3
+  AND record_type = $4
+`
+
+type AddToRatingAggregateParams struct {
+	CountDelta int64
+	SumDelta   int64
+	RecordID   string
+	RecordType string
+}
+
+func (q *Queries) AddToRatingAggregate(ctx context.Context, arg AddToRatingAggregateParams) error {
+	_, err := q.db.Exec(ctx, addToRatingAggregate,
+		arg.CountDelta,
+		arg.SumDelta,
+		arg.RecordID,
+		arg.RecordType,
+	)
+	return err
+}
+
+const addToRatingHistogram = `-- name: AddToRatingHistogram :exec
+INSERT INTO rating_histograms (record_id, record_type, value, ratings)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (record_id, record_type, value) DO UPDATE
+SET ratings = rating_histograms.ratings + EXCLUDED.ratings
+`
+
+type AddToRatingHistogramParams struct {
+	RecordID   string
+	RecordType string
+	Value      int32
+	Ratings    int64
+}
+
+func (q *Queries) AddToRatingHistogram(ctx context.Context, arg AddToRatingHistogramParams) error {
+	_, err := q.db.Exec(ctx, addToRatingHistogram,
+		arg.RecordID,
+		arg.RecordType,
+		arg.Value,
+		arg.Ratings,
+	)
+	return err
+}
+
+const clearRatingAggregates = `-- name: ClearRatingAggregates :exec
+DELETE FROM rating_aggregates
+`
+
+func (q *Queries) ClearRatingAggregates(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, clearRatingAggregates)
+	return err
+}
+
+const computeRatingHistograms = `-- name: ComputeRatingHistograms :many
+SELECT record_id, record_type, value::int AS value, count(*) AS ratings
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type, value
+ORDER BY record_type, record_id, value
+`
+
+type ComputeRatingHistogramsRow struct {
+	RecordID   string
+	RecordType string
+	Value      int32
+	Ratings    int64
+}
+
+// ComputeRatingHistograms counts the raw ratings of every record by value.
+func (q *Queries) ComputeRatingHistograms(ctx context.Context) ([]ComputeRatingHistogramsRow, error) {
+	rows, err := q.db.Query(ctx, computeRatingHistograms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ComputeRatingHistogramsRow
+	for rows.Next() {
+		var i ComputeRatingHistogramsRow
+		if err := rows.Scan(
+			&i.RecordID,
+			&i.RecordType,
+			&i.Value,
+			&i.Ratings,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatingHistogram = `-- name: GetRatingHistogram :many
+SELECT value, ratings
+FROM rating_histograms
+WHERE record_id = $1
+  AND record_type = $2
+  AND ratings > 0
+ORDER BY value
+`
+
+type GetRatingHistogramParams struct {
+	RecordID   string
+	RecordType string
+}
+
+type GetRatingHistogramRow struct {
+	Value   int32
+	Ratings int64
+}
+
+func (q *Queries) GetRatingHistogram(ctx context.Context, arg GetRatingHistogramParams) ([]GetRatingHistogramRow, error) {
+	rows, err := q.db.Query(ctx, getRatingHistogram, arg.RecordID, arg.RecordType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatingHistogramRow
+	for rows.Next() {
+		var i GetRatingHistogramRow
+		if err := rows.Scan(&i.Value, &i.Ratings); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRatingHistograms = `-- name: GetRatingHistograms :many
+SELECT record_id, value, ratings
+FROM rating_histograms
+WHERE record_type = $1
+  AND record_id = ANY($2::text[])
+  AND ratings > 0
+ORDER BY record_id, value
+`
+
+type GetRatingHistogramsParams struct {
+	RecordType string
+	RecordIds  []string
+}
+
+type GetRatingHistogramsRow struct {
+	RecordID string
+	Value    int32
+	Ratings  int64
+}
+
+func (q *Queries) GetRatingHistograms(ctx context.Context, arg GetRatingHistogramsParams) ([]GetRatingHistogramsRow, error) {
+	rows, err := q.db.Query(ctx, getRatingHistograms, arg.RecordType, arg.RecordIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRatingHistogramsRow
+	for rows.Next() {
+		var i GetRatingHistogramsRow
+		if err := rows.Scan(&i.RecordID, &i.Value, &i.Ratings); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecordTypeHistogram = `-- name: GetRecordTypeHistogram :many
+SELECT value, sum(ratings)::bigint AS ratings
+FROM rating_histograms
+WHERE record_type = $1
+  AND ratings > 0
+GROUP BY value
+ORDER BY value
+`
+
+type GetRecordTypeHistogramRow struct {
+	Value   int32
+	Ratings int64
+}
+
+func (q *Queries) GetRecordTypeHistogram(ctx context.Context, recordType string) ([]GetRecordTypeHistogramRow, error) {
+	rows, err := q.db.Query(ctx, getRecordTypeHistogram, recordType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecordTypeHistogramRow
+	for rows.Next() {
+		var i GetRecordTypeHistogramRow
+		if err := rows.Scan(&i.Value, &i.Ratings); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRatingAggregates = `-- name: ListRatingAggregates :many
+SELECT record_id, record_type, ratings_count, ratings_sum
+FROM rating_aggregates
+ORDER BY record_type, record_id
+`
+
+func (q *Queries) ListRatingAggregates(ctx context.Context) ([]RatingAggregate, error) {
+	rows, err := q.db.Query(ctx, listRatingAggregates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RatingAggregate
+	for rows.Next() {
+		var i RatingAggregate
+		if err := rows.Scan(
+			&i.RecordID,
+			&i.RecordType,
+			&i.RatingsCount,
+			&i.RatingsSum,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRatingHistograms = `-- name: ListRatingHistograms :many
+SELECT record_id, record_type, value, ratings
+FROM rating_histograms
+WHERE ratings <> 0
+ORDER BY record_type, record_id, value
+`
+
+func (q *Queries) ListRatingHistograms(ctx context.Context) ([]RatingHistogram, error) {
+	rows, err := q.db.Query(ctx, listRatingHistograms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RatingHistogram
+	for rows.Next() {
+		var i RatingHistogram
+		if err := rows.Scan(
+			&i.RecordID,
+			&i.RecordType,
+			&i.Value,
+			&i.Ratings,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopRatedAllTime = `-- name: ListTopRatedAllTime :many
+SELECT record_id,
+       record_type,
+       ratings_count,
+       (ratings_sum::float8 / ratings_count)::float8 AS mean,
+       rank() OVER (ORDER BY ratings_sum::float8 / ratings_count DESC, ratings_count DESC) AS rank
+FROM rating_aggregates
+WHERE ratings_count > 0
+  AND ratings_count >= $1
+  AND ($2::text IS NULL OR record_type = $2)
+ORDER BY rank, record_type, record_id
+LIMIT $3
+`
+
+type ListTopRatedAllTimeParams struct {
+	MinVotes   int64
+	RecordType pgtype.Text
+	MaxRecords int32
+}
+
+type ListTopRatedAllTimeRow struct {
+	RecordID     string
+	RecordType   string
+	RatingsCount int64
+	Mean         float64
+	Rank         int64
+}
+
+// ListTopRatedAllTime ranks records by the mean of their aggregate, then by their number of ratings.
+func (q *Queries) ListTopRatedAllTime(ctx context.Context, arg ListTopRatedAllTimeParams) ([]ListTopRatedAllTimeRow, error) {
+	rows, err := q.db.Query(ctx, listTopRatedAllTime, arg.MinVotes, arg.RecordType, arg.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTopRatedAllTimeRow
+	for rows.Next() {
+		var i ListTopRatedAllTimeRow
+		if err := rows.Scan(
+			&i.RecordID,
+			&i.RecordType,
+			&i.RatingsCount,
+			&i.Mean,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockRatingAggregate = `-- name: LockRatingAggregate :exec
+INSERT INTO rating_aggregates (record_id, record_type)
+VALUES ($1, $2)
+ON CONFLICT (record_id, record_type) DO UPDATE
+SET ratings_count = rating_aggregates.ratings_count
+`
+
+type LockRatingAggregateParams struct {
+	RecordID   string
+	RecordType string
+}
+
+// LockRatingAggregate creates the aggregate of a record if needed and locks it until the end of
+// the transaction, so that writes to the ratings of the record apply their deltas one at a time.
+func (q *Queries) LockRatingAggregate(ctx context.Context, arg LockRatingAggregateParams) error {
+	_, err := q.db.Exec(ctx, lockRatingAggregate, arg.RecordID, arg.RecordType)
+	return err
+}
+
+const lockRatingTables = `-- name: LockRatingTables :exec
+LOCK TABLE rating_aggregates, rating_histograms, ratings IN SHARE ROW EXCLUSIVE MODE
+`
+
+// LockRatingTables blocks writes to ratings and their aggregates until the end of the
+// transaction. The aggregates are locked first, like writers do, so that neither waits on the other.
+func (q *Queries) LockRatingTables(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockRatingTables)
+	return err
+}
+
+const rebuildRatingAggregates = `-- name: RebuildRatingAggregates :exec
+INSERT INTO rating_aggregates (record_id, record_type, ratings_count, ratings_sum)
+SELECT record_id, record_type, count(*), sum(value)
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type
+`
+
+func (q *Queries) RebuildRatingAggregates(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, rebuildRatingAggregates)
+	return err
+}
+
+const rebuildRatingHistograms = `-- name: RebuildRatingHistograms :exec
+INSERT INTO rating_histograms (record_id, record_type, value, ratings)
+SELECT record_id, record_type, value, count(*)
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type, value
+`
+
+func (q *Queries) RebuildRatingHistograms(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, rebuildRatingHistograms)
+	return err
+}
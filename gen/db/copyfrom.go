@@ -0,0 +1,149 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: copyfrom.go
+
+package dbGen
+
+import (
+	"context"
+)
+
+// iteratorForCopyMovieCredits implements pgx.CopyFromSource.
+type iteratorForCopyMovieCredits struct {
+	rows                 []CopyMovieCreditsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyMovieCredits) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyMovieCredits) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].MovieID,
+		r.rows[0].PersonName,
+		r.rows[0].Role,
+		r.rows[0].CharacterName,
+		r.rows[0].BillingOrder,
+	}, nil
+}
+
+func (r iteratorForCopyMovieCredits) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyMovieCredits(ctx context.Context, arg []CopyMovieCreditsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"movie_credits"}, []string{"movie_id", "person_name", "role", "character_name", "billing_order"}, &iteratorForCopyMovieCredits{rows: arg})
+}
+
+// iteratorForCopyMovieExternalIDs implements pgx.CopyFromSource.
+type iteratorForCopyMovieExternalIDs struct {
+	rows                 []CopyMovieExternalIDsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyMovieExternalIDs) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyMovieExternalIDs) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].MovieID,
+		r.rows[0].Provider,
+		r.rows[0].ExternalID,
+	}, nil
+}
+
+func (r iteratorForCopyMovieExternalIDs) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyMovieExternalIDs(ctx context.Context, arg []CopyMovieExternalIDsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"movie_external_ids"}, []string{"movie_id", "provider", "external_id"}, &iteratorForCopyMovieExternalIDs{rows: arg})
+}
+
+// iteratorForCopyMovieGenres implements pgx.CopyFromSource.
+type iteratorForCopyMovieGenres struct {
+	rows                 []CopyMovieGenresParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyMovieGenres) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyMovieGenres) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].MovieID,
+		r.rows[0].GenreID,
+	}, nil
+}
+
+func (r iteratorForCopyMovieGenres) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyMovieGenres(ctx context.Context, arg []CopyMovieGenresParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"movie_genres"}, []string{"movie_id", "genre_id"}, &iteratorForCopyMovieGenres{rows: arg})
+}
+
+// iteratorForCopyRevisions implements pgx.CopyFromSource.
+type iteratorForCopyRevisions struct {
+	rows                 []CopyRevisionsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyRevisions) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyRevisions) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].MovieID,
+		r.rows[0].Version,
+		r.rows[0].Author,
+		r.rows[0].Changes,
+		r.rows[0].Snapshot,
+	}, nil
+}
+
+func (r iteratorForCopyRevisions) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyRevisions(ctx context.Context, arg []CopyRevisionsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"movie_revisions"}, []string{"movie_id", "version", "author", "changes", "snapshot"}, &iteratorForCopyRevisions{rows: arg})
+}
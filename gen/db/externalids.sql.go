@@ -0,0 +1,133 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: externalids.sql
+
+package dbGen
+
+import (
+	"context"
+)
+
+type CopyMovieExternalIDsParams struct {
+	MovieID    string
+	Provider   string
+	ExternalID string
+}
+
+const deleteExternalIDsForMovies = `-- name: DeleteExternalIDsForMovies :exec
+DELETE FROM movie_external_ids
+WHERE movie_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteExternalIDsForMovies(ctx context.Context, movieIds []string) error {
+	_, err := q.db.Exec(ctx, deleteExternalIDsForMovies, movieIds)
+	return err
+}
+
+const deleteMovieExternalIDs = `-- name: DeleteMovieExternalIDs :exec
+DELETE FROM movie_external_ids
+WHERE movie_id = $1
+`
+
+func (q *Queries) DeleteMovieExternalIDs(ctx context.Context, movieID string) error {
+	_, err := q.db.Exec(ctx, deleteMovieExternalIDs, movieID)
+	return err
+}
+
+const getMovieIDByExternalID = `-- name: GetMovieIDByExternalID :one
+SELECT m.id
+FROM movie_external_ids e
+JOIN movie m ON m.id = e.movie_id
+WHERE e.provider = $1
+  AND e.external_id = $2
+  AND m.deleted_at IS NULL
+`
+
+type GetMovieIDByExternalIDParams struct {
+	Provider   string
+	ExternalID string
+}
+
+// Soft deleted movies keep their external IDs but are not found.
+func (q *Queries) GetMovieIDByExternalID(ctx context.Context, arg GetMovieIDByExternalIDParams) (string, error) {
+	row := q.db.QueryRow(ctx, getMovieIDByExternalID, arg.Provider, arg.ExternalID)
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertMovieExternalID = `-- name: InsertMovieExternalID :exec
+INSERT INTO movie_external_ids (movie_id, provider, external_id)
+VALUES ($1, $2, $3)
+`
+
+type InsertMovieExternalIDParams struct {
+	MovieID    string
+	Provider   string
+	ExternalID string
+}
+
+func (q *Queries) InsertMovieExternalID(ctx context.Context, arg InsertMovieExternalIDParams) error {
+	_, err := q.db.Exec(ctx, insertMovieExternalID, arg.MovieID, arg.Provider, arg.ExternalID)
+	return err
+}
+
+const listExternalIDsForMovies = `-- name: ListExternalIDsForMovies :many
+SELECT movie_id, provider, external_id
+FROM movie_external_ids
+WHERE movie_id = ANY($1::varchar[])
+ORDER BY movie_id, provider
+`
+
+func (q *Queries) ListExternalIDsForMovies(ctx context.Context, movieIds []string) ([]MovieExternalID, error) {
+	rows, err := q.db.Query(ctx, listExternalIDsForMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MovieExternalID
+	for rows.Next() {
+		var i MovieExternalID
+		if err := rows.Scan(&i.MovieID, &i.Provider, &i.ExternalID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMovieExternalIDs = `-- name: ListMovieExternalIDs :many
+SELECT provider, external_id
+FROM movie_external_ids
+WHERE movie_id = $1
+ORDER BY provider
+`
+
+type ListMovieExternalIDsRow struct {
+	Provider   string
+	ExternalID string
+}
+
+func (q *Queries) ListMovieExternalIDs(ctx context.Context, movieID string) ([]ListMovieExternalIDsRow, error) {
+	rows, err := q.db.Query(ctx, listMovieExternalIDs, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMovieExternalIDsRow
+	for rows.Next() {
+		var i ListMovieExternalIDsRow
+		if err := rows.Scan(&i.Provider, &i.ExternalID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
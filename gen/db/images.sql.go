@@ -0,0 +1,85 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: images.sql
+
+package dbGen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const insertImage = `-- name: InsertImage :one
+INSERT INTO movie_images (id, movie_id, kind, content_type, width, height, size_bytes, storage_key, variants)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING created_at
+`
+
+type InsertImageParams struct {
+	ID          string
+	MovieID     string
+	Kind        string
+	ContentType string
+	Width       int32
+	Height      int32
+	SizeBytes   int64
+	StorageKey  string
+	Variants    []byte
+}
+
+func (q *Queries) InsertImage(ctx context.Context, arg InsertImageParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, insertImage,
+		arg.ID,
+		arg.MovieID,
+		arg.Kind,
+		arg.ContentType,
+		arg.Width,
+		arg.Height,
+		arg.SizeBytes,
+		arg.StorageKey,
+		arg.Variants,
+	)
+	var created_at pgtype.Timestamptz
+	err := row.Scan(&created_at)
+	return created_at, err
+}
+
+const listImages = `-- name: ListImages :many
+SELECT id, movie_id, kind, content_type, width, height, size_bytes, storage_key, variants, created_at
+FROM movie_images
+WHERE movie_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListImages(ctx context.Context, movieID string) ([]MovieImage, error) {
+	rows, err := q.db.Query(ctx, listImages, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MovieImage
+	for rows.Next() {
+		var i MovieImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.MovieID,
+			&i.Kind,
+			&i.ContentType,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.Variants,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -8,16 +8,120 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Genre struct {
+	ID   int32
+	Name string
+}
+
 type Movie struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+	SearchVector     interface{}
+	DeletedAt        pgtype.Timestamptz
+	Version          int64
+}
+
+type MovieCredit struct {
+	MovieID       string
+	PersonName    string
+	Role          string
+	CharacterName pgtype.Text
+	BillingOrder  int32
+}
+
+type MovieExternalID struct {
+	MovieID    string
+	Provider   string
+	ExternalID string
+}
+
+type MovieGenre struct {
+	MovieID string
+	GenreID int32
+}
+
+type MovieImage struct {
 	ID          string
-	Title       pgtype.Text
-	Description pgtype.Text
-	Director    pgtype.Text
+	MovieID     string
+	Kind        string
+	ContentType string
+	Width       int32
+	Height      int32
+	SizeBytes   int64
+	StorageKey  string
+	Variants    []byte
+	CreatedAt   pgtype.Timestamptz
+}
+
+type MovieRelation struct {
+	MovieID      string
+	RelationType string
+	RelatedID    string
+	CreatedAt    pgtype.Timestamptz
+}
+
+type MovieRevision struct {
+	MovieID   string
+	Version   int64
+	Author    string
+	CreatedAt pgtype.Timestamptz
+	Changes   []byte
+	Snapshot  []byte
+}
+
+type MovieSlug struct {
+	Slug      string
+	MovieID   string
+	IsCurrent bool
+	CreatedAt pgtype.Timestamptz
+}
+
+type MovieTranslation struct {
+	MovieID     string
+	Locale      string
+	Title       string
+	Description string
 }
 
 type Rating struct {
-	RecordID   pgtype.Text
-	RecordType pgtype.Text
-	UserID     pgtype.Text
-	Value      pgtype.Int4
+	RecordID         string
+	RecordType       string
+	UserID           string
+	Value            pgtype.Int4
+	UpdatedAt        pgtype.Timestamptz
+	CreatedAt        pgtype.Timestamptz
+	ReviewTitle      pgtype.Text
+	ReviewText       pgtype.Text
+	ReviewSpoiler    bool
+	ReviewedAt       pgtype.Timestamptz
+	ReviewState      pgtype.Text
+	ModerationReason pgtype.Text
+}
+
+type RatingAggregate struct {
+	RecordID     string
+	RecordType   string
+	RatingsCount int64
+	RatingsSum   int64
+}
+
+type RatingHistogram struct {
+	RecordID   string
+	RecordType string
+	Value      int32
+	Ratings    int64
+}
+
+type ReviewVote struct {
+	RecordID   string
+	RecordType string
+	AuthorID   string
+	VoterID    string
+	Helpful    bool
 }
@@ -11,43 +11,782 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyMovieCreditsParams struct {
+	MovieID       string
+	PersonName    string
+	Role          string
+	CharacterName pgtype.Text
+	BillingOrder  int32
+}
+
+type CopyMovieGenresParams struct {
+	MovieID string
+	GenreID int32
+}
+
+const deleteCreditsForMovies = `-- name: DeleteCreditsForMovies :exec
+DELETE FROM movie_credits
+WHERE movie_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteCreditsForMovies(ctx context.Context, movieIds []string) error {
+	_, err := q.db.Exec(ctx, deleteCreditsForMovies, movieIds)
+	return err
+}
+
+const deleteGenresForMovies = `-- name: DeleteGenresForMovies :exec
+DELETE FROM movie_genres
+WHERE movie_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteGenresForMovies(ctx context.Context, movieIds []string) error {
+	_, err := q.db.Exec(ctx, deleteGenresForMovies, movieIds)
+	return err
+}
+
+const deleteMovie = `-- name: DeleteMovie :execrows
+DELETE FROM movie
+WHERE id = $1
+`
+
+func (q *Queries) DeleteMovie(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMovie, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteMovieCredits = `-- name: DeleteMovieCredits :exec
+DELETE FROM movie_credits
+WHERE movie_id = $1
+`
+
+func (q *Queries) DeleteMovieCredits(ctx context.Context, movieID string) error {
+	_, err := q.db.Exec(ctx, deleteMovieCredits, movieID)
+	return err
+}
+
+const deleteMovieGenres = `-- name: DeleteMovieGenres :exec
+DELETE FROM movie_genres
+WHERE movie_id = $1
+`
+
+func (q *Queries) DeleteMovieGenres(ctx context.Context, movieID string) error {
+	_, err := q.db.Exec(ctx, deleteMovieGenres, movieID)
+	return err
+}
+
 const getMovie = `-- name: GetMovie :one
-SELECT title, description, director 
+SELECT title, description, director, runtime_minutes, release_date, original_language, country, version
 FROM movie 
 WHERE id = $1
+  AND deleted_at IS NULL
 `
 
 type GetMovieRow struct {
-	Title       pgtype.Text
-	Description pgtype.Text
-	Director    pgtype.Text
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+	Version          int64
 }
 
 func (q *Queries) GetMovie(ctx context.Context, id string) (GetMovieRow, error) {
 	row := q.db.QueryRow(ctx, getMovie, id)
 	var i GetMovieRow
-	err := row.Scan(&i.Title, &i.Description, &i.Director)
+	err := row.Scan(
+		&i.Title,
+		&i.Description,
+		&i.Director,
+		&i.RuntimeMinutes,
+		&i.ReleaseDate,
+		&i.OriginalLanguage,
+		&i.Country,
+		&i.Version,
+	)
 	return i, err
 }
 
-const insertMovie = `-- name: InsertMovie :exec
-INSERT INTO movie (id, title, description, director) 
-VALUES ($1, $2, $3, $4)
+const getMovies = `-- name: GetMovies :many
+SELECT id, title, description, director, runtime_minutes, release_date, original_language, country, version
+FROM movie
+WHERE id = ANY($1::varchar[])
+  AND deleted_at IS NULL
+ORDER BY id
 `
 
-type InsertMovieParams struct {
-	ID          string
-	Title       pgtype.Text
-	Description pgtype.Text
-	Director    pgtype.Text
+type GetMoviesRow struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+	Version          int64
+}
+
+func (q *Queries) GetMovies(ctx context.Context, movieIds []string) ([]GetMoviesRow, error) {
+	rows, err := q.db.Query(ctx, getMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMoviesRow
+	for rows.Next() {
+		var i GetMoviesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Director,
+			&i.RuntimeMinutes,
+			&i.ReleaseDate,
+			&i.OriginalLanguage,
+			&i.Country,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) InsertMovie(ctx context.Context, arg InsertMovieParams) error {
-	_, err := q.db.Exec(ctx, insertMovie,
+const insertMovieCredit = `-- name: InsertMovieCredit :exec
+INSERT INTO movie_credits (movie_id, person_name, role, character_name, billing_order)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type InsertMovieCreditParams struct {
+	MovieID       string
+	PersonName    string
+	Role          string
+	CharacterName pgtype.Text
+	BillingOrder  int32
+}
+
+func (q *Queries) InsertMovieCredit(ctx context.Context, arg InsertMovieCreditParams) error {
+	_, err := q.db.Exec(ctx, insertMovieCredit,
+		arg.MovieID,
+		arg.PersonName,
+		arg.Role,
+		arg.CharacterName,
+		arg.BillingOrder,
+	)
+	return err
+}
+
+const insertMovieGenre = `-- name: InsertMovieGenre :exec
+INSERT INTO movie_genres (movie_id, genre_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type InsertMovieGenreParams struct {
+	MovieID string
+	GenreID int32
+}
+
+func (q *Queries) InsertMovieGenre(ctx context.Context, arg InsertMovieGenreParams) error {
+	_, err := q.db.Exec(ctx, insertMovieGenre, arg.MovieID, arg.GenreID)
+	return err
+}
+
+const insertMovieIfAbsent = `-- name: InsertMovieIfAbsent :one
+INSERT INTO movie (id, title, description, director, runtime_minutes, release_date, original_language, country)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (id) DO NOTHING
+RETURNING version
+`
+
+type InsertMovieIfAbsentParams struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+}
+
+func (q *Queries) InsertMovieIfAbsent(ctx context.Context, arg InsertMovieIfAbsentParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertMovieIfAbsent,
 		arg.ID,
 		arg.Title,
 		arg.Description,
 		arg.Director,
+		arg.RuntimeMinutes,
+		arg.ReleaseDate,
+		arg.OriginalLanguage,
+		arg.Country,
 	)
-	return err
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const listCreditsForMovies = `-- name: ListCreditsForMovies :many
+SELECT movie_id, person_name, role, character_name, billing_order
+FROM movie_credits
+WHERE movie_id = ANY($1::varchar[])
+ORDER BY movie_id, billing_order, person_name
+`
+
+func (q *Queries) ListCreditsForMovies(ctx context.Context, movieIds []string) ([]MovieCredit, error) {
+	rows, err := q.db.Query(ctx, listCreditsForMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MovieCredit
+	for rows.Next() {
+		var i MovieCredit
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.PersonName,
+			&i.Role,
+			&i.CharacterName,
+			&i.BillingOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGenresForMovies = `-- name: ListGenresForMovies :many
+SELECT mg.movie_id, g.name
+FROM movie_genres mg
+JOIN genres g ON g.id = mg.genre_id
+WHERE mg.movie_id = ANY($1::varchar[])
+ORDER BY mg.movie_id, g.name
+`
+
+type ListGenresForMoviesRow struct {
+	MovieID string
+	Name    string
+}
+
+func (q *Queries) ListGenresForMovies(ctx context.Context, movieIds []string) ([]ListGenresForMoviesRow, error) {
+	rows, err := q.db.Query(ctx, listGenresForMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGenresForMoviesRow
+	for rows.Next() {
+		var i ListGenresForMoviesRow
+		if err := rows.Scan(&i.MovieID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMovieCredits = `-- name: ListMovieCredits :many
+SELECT person_name, role, character_name, billing_order
+FROM movie_credits
+WHERE movie_id = $1
+ORDER BY billing_order, person_name
+`
+
+type ListMovieCreditsRow struct {
+	PersonName    string
+	Role          string
+	CharacterName pgtype.Text
+	BillingOrder  int32
+}
+
+func (q *Queries) ListMovieCredits(ctx context.Context, movieID string) ([]ListMovieCreditsRow, error) {
+	rows, err := q.db.Query(ctx, listMovieCredits, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMovieCreditsRow
+	for rows.Next() {
+		var i ListMovieCreditsRow
+		if err := rows.Scan(
+			&i.PersonName,
+			&i.Role,
+			&i.CharacterName,
+			&i.BillingOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMovieGenres = `-- name: ListMovieGenres :many
+SELECT g.name
+FROM genres g
+JOIN movie_genres mg ON mg.genre_id = g.id
+WHERE mg.movie_id = $1
+ORDER BY g.name
+`
+
+func (q *Queries) ListMovieGenres(ctx context.Context, movieID string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listMovieGenres, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMovies = `-- name: ListMovies :many
+SELECT m.id, m.title, m.description, m.director, m.runtime_minutes, m.release_date, m.original_language, m.country, m.version
+FROM movie m
+WHERE m.deleted_at IS NULL
+  AND ($1::text IS NULL OR lower(m.director) = lower($1::text))
+  AND ($2::text IS NULL OR m.title ILIKE $2::text || '%')
+  AND ($3::text IS NULL OR EXISTS (
+    SELECT 1
+    FROM movie_genres mg
+    JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id AND g.name = $3::text
+  ))
+  AND ($4::int IS NULL OR EXTRACT(YEAR FROM m.release_date) >= $4::int)
+  AND ($5::int IS NULL OR EXTRACT(YEAR FROM m.release_date) <= $5::int)
+ORDER BY
+  CASE WHEN $6::text = 'title' THEN m.title END ASC NULLS LAST,
+  CASE WHEN $6::text = 'title_desc' THEN m.title END DESC NULLS LAST,
+  CASE WHEN $6::text = 'release_date' THEN m.release_date END ASC NULLS LAST,
+  CASE WHEN $6::text = 'release_date_desc' THEN m.release_date END DESC NULLS LAST,
+  m.id
+LIMIT $8 OFFSET $7
+`
+
+type ListMoviesParams struct {
+	Director        pgtype.Text
+	TitlePrefix     pgtype.Text
+	Genre           pgtype.Text
+	ReleaseYearFrom pgtype.Int4
+	ReleaseYearTo   pgtype.Int4
+	SortOrder       string
+	PageOffset      int32
+	PageLimit       int32
+}
+
+type ListMoviesRow struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+	Version          int64
+}
+
+func (q *Queries) ListMovies(ctx context.Context, arg ListMoviesParams) ([]ListMoviesRow, error) {
+	rows, err := q.db.Query(ctx, listMovies,
+		arg.Director,
+		arg.TitlePrefix,
+		arg.Genre,
+		arg.ReleaseYearFrom,
+		arg.ReleaseYearTo,
+		arg.SortOrder,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMoviesRow
+	for rows.Next() {
+		var i ListMoviesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Director,
+			&i.RuntimeMinutes,
+			&i.ReleaseDate,
+			&i.OriginalLanguage,
+			&i.Country,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMoviesAfter = `-- name: ListMoviesAfter :many
+SELECT id, title, description, director, runtime_minutes, release_date, original_language, country, version
+FROM movie
+WHERE deleted_at IS NULL
+  AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListMoviesAfterParams struct {
+	AfterID   string
+	PageLimit int32
+}
+
+type ListMoviesAfterRow struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+	Version          int64
+}
+
+// Keyset pagination over the primary key, so every page is an index range scan
+// no matter how deep into the catalog it is.
+func (q *Queries) ListMoviesAfter(ctx context.Context, arg ListMoviesAfterParams) ([]ListMoviesAfterRow, error) {
+	rows, err := q.db.Query(ctx, listMoviesAfter, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMoviesAfterRow
+	for rows.Next() {
+		var i ListMoviesAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Director,
+			&i.RuntimeMinutes,
+			&i.ReleaseDate,
+			&i.OriginalLanguage,
+			&i.Country,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchMovies = `-- name: SearchMovies :many
+SELECT m.id, m.title, m.description, m.director, m.runtime_minutes, m.release_date, m.original_language, m.country, m.version,
+  ts_rank(m.search_vector, q.query)::float8 AS score
+FROM movie m, websearch_to_tsquery('english', $1::text) AS q(query)
+WHERE m.search_vector @@ q.query
+  AND m.deleted_at IS NULL
+ORDER BY score DESC, m.id
+LIMIT $3 OFFSET $2
+`
+
+type SearchMoviesParams struct {
+	Query      string
+	PageOffset int32
+	PageLimit  int32
+}
+
+type SearchMoviesRow struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+	Version          int64
+	Score            float64
+}
+
+func (q *Queries) SearchMovies(ctx context.Context, arg SearchMoviesParams) ([]SearchMoviesRow, error) {
+	rows, err := q.db.Query(ctx, searchMovies, arg.Query, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchMoviesRow
+	for rows.Next() {
+		var i SearchMoviesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Director,
+			&i.RuntimeMinutes,
+			&i.ReleaseDate,
+			&i.OriginalLanguage,
+			&i.Country,
+			&i.Version,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteMovie = `-- name: SoftDeleteMovie :execrows
+UPDATE movie
+SET deleted_at = now()
+WHERE id = $1
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteMovie(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteMovie, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const undeleteMovie = `-- name: UndeleteMovie :execrows
+UPDATE movie
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) UndeleteMovie(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, undeleteMovie, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateMovieFields = `-- name: UpdateMovieFields :one
+UPDATE movie SET
+  title = CASE WHEN $1::boolean THEN $2::varchar ELSE title END,
+  description = CASE WHEN $3::boolean THEN $4::varchar ELSE description END,
+  director = CASE WHEN $5::boolean THEN $6::varchar ELSE director END,
+  runtime_minutes = CASE WHEN $7::boolean THEN $8::int ELSE runtime_minutes END,
+  release_date = CASE WHEN $9::boolean THEN $10::date ELSE release_date END,
+  original_language = CASE WHEN $11::boolean THEN $12::varchar ELSE original_language END,
+  country = CASE WHEN $13::boolean THEN $14::varchar ELSE country END,
+  version = version + 1
+WHERE id = $15
+  AND deleted_at IS NULL
+  AND ($16::bigint = 0 OR version = $16)
+RETURNING version
+`
+
+type UpdateMovieFieldsParams struct {
+	SetTitle            bool
+	Title               pgtype.Text
+	SetDescription      bool
+	Description         pgtype.Text
+	SetDirector         bool
+	Director            pgtype.Text
+	SetRuntimeMinutes   bool
+	RuntimeMinutes      pgtype.Int4
+	SetReleaseDate      bool
+	ReleaseDate         pgtype.Date
+	SetOriginalLanguage bool
+	OriginalLanguage    pgtype.Text
+	SetCountry          bool
+	Country             pgtype.Text
+	ID                  string
+	ExpectedVersion     int64
+}
+
+// Only the columns whose set_ flag is true are written; the others keep their stored value.
+// An expected_version of 0 skips the version check.
+func (q *Queries) UpdateMovieFields(ctx context.Context, arg UpdateMovieFieldsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateMovieFields,
+		arg.SetTitle,
+		arg.Title,
+		arg.SetDescription,
+		arg.Description,
+		arg.SetDirector,
+		arg.Director,
+		arg.SetRuntimeMinutes,
+		arg.RuntimeMinutes,
+		arg.SetReleaseDate,
+		arg.ReleaseDate,
+		arg.SetOriginalLanguage,
+		arg.OriginalLanguage,
+		arg.SetCountry,
+		arg.Country,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const updateMovieIfVersion = `-- name: UpdateMovieIfVersion :one
+UPDATE movie SET
+  title = $2,
+  description = $3,
+  director = $4,
+  runtime_minutes = $5,
+  release_date = $6,
+  original_language = $7,
+  country = $8,
+  deleted_at = NULL,
+  version = version + 1
+WHERE id = $1
+  AND version = $9
+RETURNING version
+`
+
+type UpdateMovieIfVersionParams struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+	ExpectedVersion  int64
+}
+
+func (q *Queries) UpdateMovieIfVersion(ctx context.Context, arg UpdateMovieIfVersionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateMovieIfVersion,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.Director,
+		arg.RuntimeMinutes,
+		arg.ReleaseDate,
+		arg.OriginalLanguage,
+		arg.Country,
+		arg.ExpectedVersion,
+	)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
+const upsertGenre = `-- name: UpsertGenre :one
+INSERT INTO genres (name)
+VALUES ($1)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id
+`
+
+func (q *Queries) UpsertGenre(ctx context.Context, name string) (int32, error) {
+	row := q.db.QueryRow(ctx, upsertGenre, name)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const upsertGenres = `-- name: UpsertGenres :many
+INSERT INTO genres (name)
+SELECT unnest($1::varchar[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name
+`
+
+func (q *Queries) UpsertGenres(ctx context.Context, names []string) ([]Genre, error) {
+	rows, err := q.db.Query(ctx, upsertGenres, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Genre
+	for rows.Next() {
+		var i Genre
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertMovie = `-- name: UpsertMovie :one
+INSERT INTO movie (id, title, description, director, runtime_minutes, release_date, original_language, country) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (id) DO UPDATE SET
+  title = EXCLUDED.title,
+  description = EXCLUDED.description,
+  director = EXCLUDED.director,
+  runtime_minutes = EXCLUDED.runtime_minutes,
+  release_date = EXCLUDED.release_date,
+  original_language = EXCLUDED.original_language,
+  country = EXCLUDED.country,
+  deleted_at = NULL,
+  version = movie.version + 1
+RETURNING version
+`
+
+type UpsertMovieParams struct {
+	ID               string
+	Title            pgtype.Text
+	Description      pgtype.Text
+	Director         pgtype.Text
+	RuntimeMinutes   pgtype.Int4
+	ReleaseDate      pgtype.Date
+	OriginalLanguage pgtype.Text
+	Country          pgtype.Text
+}
+
+func (q *Queries) UpsertMovie(ctx context.Context, arg UpsertMovieParams) (int64, error) {
+	row := q.db.QueryRow(ctx, upsertMovie,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.Director,
+		arg.RuntimeMinutes,
+		arg.ReleaseDate,
+		arg.OriginalLanguage,
+		arg.Country,
+	)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
 }
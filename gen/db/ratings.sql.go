@@ -8,25 +8,78 @@ package dbGen
 import (
 	"context"
 
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteRating = `-- name: DeleteRating :execrows
+DELETE FROM ratings
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3
+`
+
+type DeleteRatingParams struct {
+	RecordID   string
+	RecordType string
+	UserID     string
+}
+
+func (q *Queries) DeleteRating(ctx context.Context, arg DeleteRatingParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRating, arg.RecordID, arg.RecordType, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getDecayedMean = `-- name: GetDecayedMean :one
+SELECT COALESCE(
+           sum(value * power(0.5, extract(EPOCH FROM $1::timestamptz - updated_at) / $2::float8))
+               / NULLIF(sum(power(0.5, extract(EPOCH FROM $1::timestamptz - updated_at) / $2::float8)), 0),
+           0)::float8 AS mean
+FROM ratings
+WHERE record_id = $3
+  AND record_type = $4
+  AND value IS NOT NULL
+`
+
+type GetDecayedMeanParams struct {
+	Now             pgtype.Timestamptz
+	HalfLifeSeconds float64
+	RecordID        string
+	RecordType      string
+}
+
+// GetDecayedMean weighs every rating by 0.5 to the power of its age in half-lives.
+func (q *Queries) GetDecayedMean(ctx context.Context, arg GetDecayedMeanParams) (float64, error) {
+	row := q.db.QueryRow(ctx, getDecayedMean,
+		arg.Now,
+		arg.HalfLifeSeconds,
+		arg.RecordID,
+		arg.RecordType,
+	)
+	var mean float64
+	err := row.Scan(&mean)
+	return mean, err
+}
+
 const getRatings = `-- name: GetRatings :many
-SELECT user_id, value
+SELECT user_id, value, created_at, updated_at
 FROM ratings
 WHERE record_id = $1
   AND record_type = $2
 `
 
 type GetRatingsParams struct {
-	RecordID   pgtype.Text
-	RecordType pgtype.Text
+	RecordID   string
+	RecordType string
 }
 
 type GetRatingsRow struct {
-	UserID pgtype.Text
-	Value  pgtype.Int4
+	UserID    string
+	Value     pgtype.Int4
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
 }
 
 func (q *Queries) GetRatings(ctx context.Context, arg GetRatingsParams) ([]GetRatingsRow, error) {
@@ -38,7 +91,12 @@ func (q *Queries) GetRatings(ctx context.Context, arg GetRatingsParams) ([]GetRa
 	var items []GetRatingsRow
 	for rows.Next() {
 		var i GetRatingsRow
-		if err := rows.Scan(&i.UserID, &i.Value); err != nil {
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Value,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -49,23 +107,263 @@ func (q *Queries) GetRatings(ctx context.Context, arg GetRatingsParams) ([]GetRa
 	return items, nil
 }
 
-const insertRating = `-- name: InsertRating :execresult
+const getUserRating = `-- name: GetUserRating :one
+SELECT value, created_at, updated_at
+FROM ratings
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3
+`
+
+type GetUserRatingParams struct {
+	RecordID   string
+	RecordType string
+	UserID     string
+}
+
+type GetUserRatingRow struct {
+	Value     pgtype.Int4
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetUserRating(ctx context.Context, arg GetUserRatingParams) (GetUserRatingRow, error) {
+	row := q.db.QueryRow(ctx, getUserRating, arg.RecordID, arg.RecordType, arg.UserID)
+	var i GetUserRatingRow
+	err := row.Scan(&i.Value, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const listTopRatedSince = `-- name: ListTopRatedSince :many
+WITH totals AS (
+    SELECT record_id, record_type, count(*) AS ratings_count, avg(value)::float8 AS mean
+    FROM ratings
+    WHERE value IS NOT NULL
+      AND updated_at >= $2
+      AND ($3::text IS NULL OR record_type = $3)
+    GROUP BY record_id, record_type
+    HAVING count(*) >= $4::bigint
+)
+SELECT record_id,
+       record_type,
+       ratings_count,
+       mean,
+       rank() OVER (ORDER BY mean DESC, ratings_count DESC) AS rank
+FROM totals
+ORDER BY rank, record_type, record_id
+LIMIT $1
+`
+
+type ListTopRatedSinceParams struct {
+	MaxRecords int32
+	Since      pgtype.Timestamptz
+	RecordType pgtype.Text
+	MinVotes   int64
+}
+
+type ListTopRatedSinceRow struct {
+	RecordID     string
+	RecordType   string
+	RatingsCount int64
+	Mean         float64
+	Rank         int64
+}
+
+// ListTopRatedSince ranks records by the mean of the ratings given or changed since a time, then
+// by their number.
+func (q *Queries) ListTopRatedSince(ctx context.Context, arg ListTopRatedSinceParams) ([]ListTopRatedSinceRow, error) {
+	rows, err := q.db.Query(ctx, listTopRatedSince,
+		arg.MaxRecords,
+		arg.Since,
+		arg.RecordType,
+		arg.MinVotes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTopRatedSinceRow
+	for rows.Next() {
+		var i ListTopRatedSinceRow
+		if err := rows.Scan(
+			&i.RecordID,
+			&i.RecordType,
+			&i.RatingsCount,
+			&i.Mean,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrending = `-- name: ListTrending :many
+WITH totals AS (
+    SELECT record_id, record_type, count(*) AS ratings_count, avg(value)::float8 AS mean
+    FROM ratings
+    WHERE value IS NOT NULL
+      AND updated_at >= $2
+      AND ($3::text IS NULL OR record_type = $3)
+    GROUP BY record_id, record_type
+    HAVING count(*) >= $4::bigint
+)
+SELECT record_id,
+       record_type,
+       ratings_count,
+       mean,
+       rank() OVER (ORDER BY ratings_count DESC, mean DESC) AS rank
+FROM totals
+ORDER BY rank, record_type, record_id
+LIMIT $1
+`
+
+type ListTrendingParams struct {
+	MaxRecords int32
+	Since      pgtype.Timestamptz
+	RecordType pgtype.Text
+	MinVotes   int64
+}
+
+type ListTrendingRow struct {
+	RecordID     string
+	RecordType   string
+	RatingsCount int64
+	Mean         float64
+	Rank         int64
+}
+
+// ListTrending ranks records by the number of ratings given or changed since a time, then by
+// their mean.
+func (q *Queries) ListTrending(ctx context.Context, arg ListTrendingParams) ([]ListTrendingRow, error) {
+	rows, err := q.db.Query(ctx, listTrending,
+		arg.MaxRecords,
+		arg.Since,
+		arg.RecordType,
+		arg.MinVotes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrendingRow
+	for rows.Next() {
+		var i ListTrendingRow
+		if err := rows.Scan(
+			&i.RecordID,
+			&i.RecordType,
+			&i.RatingsCount,
+			&i.Mean,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRatings = `-- name: ListUserRatings :many
+SELECT record_id, record_type, value, created_at, updated_at
+FROM ratings
+WHERE user_id = $1
+  AND value IS NOT NULL
+  AND ($2::text IS NULL OR record_type = $2)
+  AND ($3::timestamptz IS NULL
+       OR (updated_at, record_type, record_id) < ($3::timestamptz, $4::text, $5::text))
+ORDER BY updated_at DESC, record_type DESC, record_id DESC
+LIMIT $6
+`
+
+type ListUserRatingsParams struct {
+	UserID          string
+	RecordType      pgtype.Text
+	AfterUpdatedAt  pgtype.Timestamptz
+	AfterRecordType string
+	AfterRecordID   string
+	PageLimit       int32
+}
+
+type ListUserRatingsRow struct {
+	RecordID   string
+	RecordType string
+	Value      pgtype.Int4
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
+// ListUserRatings returns a page of the ratings of a user, most recently changed first, starting
+// after the rating at the after_ cursor when it is set.
+func (q *Queries) ListUserRatings(ctx context.Context, arg ListUserRatingsParams) ([]ListUserRatingsRow, error) {
+	rows, err := q.db.Query(ctx, listUserRatings,
+		arg.UserID,
+		arg.RecordType,
+		arg.AfterUpdatedAt,
+		arg.AfterRecordType,
+		arg.AfterRecordID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserRatingsRow
+	for rows.Next() {
+		var i ListUserRatingsRow
+		if err := rows.Scan(
+			&i.RecordID,
+			&i.RecordType,
+			&i.Value,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertRating = `-- name: UpsertRating :one
 INSERT INTO ratings (record_id, record_type, user_id, value)
 VALUES ($1, $2, $3, $4)
+ON CONFLICT (record_id, record_type, user_id) DO UPDATE
+SET value = EXCLUDED.value,
+    updated_at = now()
+RETURNING (xmax = 0)::boolean AS created, created_at, updated_at
 `
 
-type InsertRatingParams struct {
-	RecordID   pgtype.Text
-	RecordType pgtype.Text
-	UserID     pgtype.Text
+type UpsertRatingParams struct {
+	RecordID   string
+	RecordType string
+	UserID     string
 	Value      pgtype.Int4
 }
 
-func (q *Queries) InsertRating(ctx context.Context, arg InsertRatingParams) (pgconn.CommandTag, error) {
-	return q.db.Exec(ctx, insertRating,
+type UpsertRatingRow struct {
+	Created   bool
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertRating(ctx context.Context, arg UpsertRatingParams) (UpsertRatingRow, error) {
+	row := q.db.QueryRow(ctx, upsertRating,
 		arg.RecordID,
 		arg.RecordType,
 		arg.UserID,
 		arg.Value,
 	)
+	var i UpsertRatingRow
+	err := row.Scan(&i.Created, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
 }
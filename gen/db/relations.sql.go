@@ -0,0 +1,123 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: relations.sql
+
+package dbGen
+
+import (
+	"context"
+)
+
+const deleteMovieRelation = `-- name: DeleteMovieRelation :execrows
+DELETE FROM movie_relations
+WHERE movie_id = $1
+  AND relation_type = $2
+  AND related_id = $3
+`
+
+type DeleteMovieRelationParams struct {
+	MovieID      string
+	RelationType string
+	RelatedID    string
+}
+
+func (q *Queries) DeleteMovieRelation(ctx context.Context, arg DeleteMovieRelationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMovieRelation, arg.MovieID, arg.RelationType, arg.RelatedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const insertMovieRelation = `-- name: InsertMovieRelation :exec
+INSERT INTO movie_relations (movie_id, relation_type, related_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (movie_id, relation_type, related_id) DO NOTHING
+`
+
+type InsertMovieRelationParams struct {
+	MovieID      string
+	RelationType string
+	RelatedID    string
+}
+
+func (q *Queries) InsertMovieRelation(ctx context.Context, arg InsertMovieRelationParams) error {
+	_, err := q.db.Exec(ctx, insertMovieRelation, arg.MovieID, arg.RelationType, arg.RelatedID)
+	return err
+}
+
+const listMovieRelations = `-- name: ListMovieRelations :many
+SELECT movie_id, relation_type, related_id
+FROM movie_relations
+WHERE movie_id = $1
+   OR related_id = $1
+ORDER BY relation_type, movie_id, related_id
+`
+
+type ListMovieRelationsRow struct {
+	MovieID      string
+	RelationType string
+	RelatedID    string
+}
+
+func (q *Queries) ListMovieRelations(ctx context.Context, id string) ([]ListMovieRelationsRow, error) {
+	rows, err := q.db.Query(ctx, listMovieRelations, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMovieRelationsRow
+	for rows.Next() {
+		var i ListMovieRelationsRow
+		if err := rows.Scan(&i.MovieID, &i.RelationType, &i.RelatedID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockRelationType = `-- name: LockRelationType :exec
+SELECT pg_advisory_xact_lock(hashtext('movie_relations:' || $1::text))
+`
+
+// LockRelationType serializes the writers of relations of one type until the end of the
+// transaction, so that two concurrent inserts cannot each close half of a cycle.
+func (q *Queries) LockRelationType(ctx context.Context, relationType string) error {
+	_, err := q.db.Exec(ctx, lockRelationType, relationType)
+	return err
+}
+
+const relationReaches = `-- name: RelationReaches :one
+WITH RECURSIVE reachable(id) AS (
+    SELECT related_id
+    FROM movie_relations
+    WHERE movie_id = $2::varchar
+      AND relation_type = $3::text
+    UNION
+    SELECT r.related_id
+    FROM movie_relations r
+    JOIN reachable ON r.movie_id = reachable.id
+    WHERE r.relation_type = $3::text
+)
+SELECT EXISTS (SELECT 1 FROM reachable WHERE id = $1::varchar)
+`
+
+type RelationReachesParams struct {
+	ToID         string
+	FromID       string
+	RelationType string
+}
+
+// RelationReaches reports whether to_id can be reached from from_id by following relations of
+// one type forward. UNION drops the rows already seen, so the walk ends on cyclic data too.
+func (q *Queries) RelationReaches(ctx context.Context, arg RelationReachesParams) (bool, error) {
+	row := q.db.QueryRow(ctx, relationReaches, arg.ToID, arg.FromID, arg.RelationType)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
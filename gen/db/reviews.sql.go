@@ -0,0 +1,324 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: reviews.sql
+
+package dbGen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listReviewsHelpful = `-- name: ListReviewsHelpful :many
+SELECT r.user_id,
+       r.value,
+       r.review_title,
+       r.review_text,
+       r.review_spoiler,
+       r.review_state,
+       r.moderation_reason,
+       r.created_at,
+       r.updated_at,
+       r.reviewed_at,
+       v.helpful_votes,
+       v.unhelpful_votes
+FROM ratings r
+CROSS JOIN LATERAL (
+    SELECT count(*) FILTER (WHERE helpful) AS helpful_votes,
+           count(*) FILTER (WHERE NOT helpful) AS unhelpful_votes
+    FROM review_votes
+    WHERE record_id = r.record_id
+      AND record_type = r.record_type
+      AND author_id = r.user_id
+) v
+WHERE r.record_id = $1
+  AND r.record_type = $2
+  AND r.review_text IS NOT NULL
+  AND r.review_state = $3
+  AND ($4::timestamptz IS NULL
+       OR (v.helpful_votes, -v.unhelpful_votes, r.reviewed_at, r.user_id)
+          < ($5::bigint, -$6::bigint, $4::timestamptz, $7::text))
+ORDER BY v.helpful_votes DESC, v.unhelpful_votes, r.reviewed_at DESC, r.user_id DESC
+LIMIT $8
+`
+
+type ListReviewsHelpfulParams struct {
+	RecordID            string
+	RecordType          string
+	ReviewState         pgtype.Text
+	AfterReviewedAt     pgtype.Timestamptz
+	AfterHelpfulVotes   int64
+	AfterUnhelpfulVotes int64
+	AfterUserID         string
+	PageLimit           int32
+}
+
+type ListReviewsHelpfulRow struct {
+	UserID           string
+	Value            pgtype.Int4
+	ReviewTitle      pgtype.Text
+	ReviewText       pgtype.Text
+	ReviewSpoiler    bool
+	ReviewState      pgtype.Text
+	ModerationReason pgtype.Text
+	CreatedAt        pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
+	ReviewedAt       pgtype.Timestamptz
+	HelpfulVotes     int64
+	UnhelpfulVotes   int64
+}
+
+// ListReviewsHelpful is ListReviewsNewest ordered by helpful votes, descending, then unhelpful
+// votes, ascending, first. Negating the unhelpful votes lets one row comparison page over both.
+func (q *Queries) ListReviewsHelpful(ctx context.Context, arg ListReviewsHelpfulParams) ([]ListReviewsHelpfulRow, error) {
+	rows, err := q.db.Query(ctx, listReviewsHelpful,
+		arg.RecordID,
+		arg.RecordType,
+		arg.ReviewState,
+		arg.AfterReviewedAt,
+		arg.AfterHelpfulVotes,
+		arg.AfterUnhelpfulVotes,
+		arg.AfterUserID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListReviewsHelpfulRow
+	for rows.Next() {
+		var i ListReviewsHelpfulRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Value,
+			&i.ReviewTitle,
+			&i.ReviewText,
+			&i.ReviewSpoiler,
+			&i.ReviewState,
+			&i.ModerationReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ReviewedAt,
+			&i.HelpfulVotes,
+			&i.UnhelpfulVotes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReviewsNewest = `-- name: ListReviewsNewest :many
+SELECT r.user_id,
+       r.value,
+       r.review_title,
+       r.review_text,
+       r.review_spoiler,
+       r.review_state,
+       r.moderation_reason,
+       r.created_at,
+       r.updated_at,
+       r.reviewed_at,
+       v.helpful_votes,
+       v.unhelpful_votes
+FROM ratings r
+CROSS JOIN LATERAL (
+    SELECT count(*) FILTER (WHERE helpful) AS helpful_votes,
+           count(*) FILTER (WHERE NOT helpful) AS unhelpful_votes
+    FROM review_votes
+    WHERE record_id = r.record_id
+      AND record_type = r.record_type
+      AND author_id = r.user_id
+) v
+WHERE r.record_id = $1
+  AND r.record_type = $2
+  AND r.review_text IS NOT NULL
+  AND r.review_state = $3
+  AND ($4::timestamptz IS NULL
+       OR (r.reviewed_at, r.user_id) < ($4::timestamptz, $5::text))
+ORDER BY r.reviewed_at DESC, r.user_id DESC
+LIMIT $6
+`
+
+type ListReviewsNewestParams struct {
+	RecordID        string
+	RecordType      string
+	ReviewState     pgtype.Text
+	AfterReviewedAt pgtype.Timestamptz
+	AfterUserID     string
+	PageLimit       int32
+}
+
+type ListReviewsNewestRow struct {
+	UserID           string
+	Value            pgtype.Int4
+	ReviewTitle      pgtype.Text
+	ReviewText       pgtype.Text
+	ReviewSpoiler    bool
+	ReviewState      pgtype.Text
+	ModerationReason pgtype.Text
+	CreatedAt        pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
+	ReviewedAt       pgtype.Timestamptz
+	HelpfulVotes     int64
+	UnhelpfulVotes   int64
+}
+
+// ListReviewsNewest returns a page of the reviews of a record in a moderation state, newest first,
+// starting after the review at the after_ cursor when it is set. The votes are counted by a
+// lateral subquery so that no column of ratings has to be grouped.
+func (q *Queries) ListReviewsNewest(ctx context.Context, arg ListReviewsNewestParams) ([]ListReviewsNewestRow, error) {
+	rows, err := q.db.Query(ctx, listReviewsNewest,
+		arg.RecordID,
+		arg.RecordType,
+		arg.ReviewState,
+		arg.AfterReviewedAt,
+		arg.AfterUserID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListReviewsNewestRow
+	for rows.Next() {
+		var i ListReviewsNewestRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Value,
+			&i.ReviewTitle,
+			&i.ReviewText,
+			&i.ReviewSpoiler,
+			&i.ReviewState,
+			&i.ModerationReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ReviewedAt,
+			&i.HelpfulVotes,
+			&i.UnhelpfulVotes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moderateReview = `-- name: ModerateReview :execrows
+UPDATE ratings
+SET review_state = $4,
+    moderation_reason = $5
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3
+  AND review_text IS NOT NULL
+`
+
+type ModerateReviewParams struct {
+	RecordID         string
+	RecordType       string
+	UserID           string
+	ReviewState      pgtype.Text
+	ModerationReason pgtype.Text
+}
+
+func (q *Queries) ModerateReview(ctx context.Context, arg ModerateReviewParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moderateReview,
+		arg.RecordID,
+		arg.RecordType,
+		arg.UserID,
+		arg.ReviewState,
+		arg.ModerationReason,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const putReview = `-- name: PutReview :one
+UPDATE ratings
+SET review_title = $4,
+    review_text = $5,
+    review_spoiler = $6,
+    review_state = $7,
+    moderation_reason = $8,
+    reviewed_at = now()
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3
+RETURNING reviewed_at
+`
+
+type PutReviewParams struct {
+	RecordID         string
+	RecordType       string
+	UserID           string
+	ReviewTitle      pgtype.Text
+	ReviewText       pgtype.Text
+	ReviewSpoiler    bool
+	ReviewState      pgtype.Text
+	ModerationReason pgtype.Text
+}
+
+// PutReview sets the review on the rating of a user, and returns no rows if there is no such rating.
+func (q *Queries) PutReview(ctx context.Context, arg PutReviewParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, putReview,
+		arg.RecordID,
+		arg.RecordType,
+		arg.UserID,
+		arg.ReviewTitle,
+		arg.ReviewText,
+		arg.ReviewSpoiler,
+		arg.ReviewState,
+		arg.ModerationReason,
+	)
+	var reviewed_at pgtype.Timestamptz
+	err := row.Scan(&reviewed_at)
+	return reviewed_at, err
+}
+
+const upsertReviewVote = `-- name: UpsertReviewVote :execrows
+INSERT INTO review_votes (record_id, record_type, author_id, voter_id, helpful)
+SELECT r.record_id, r.record_type, r.user_id, $1::text, $2::boolean
+FROM ratings r
+WHERE r.record_id = $3
+  AND r.record_type = $4
+  AND r.user_id = $5
+  AND r.review_state = 'approved'
+ON CONFLICT (record_id, record_type, author_id, voter_id) DO UPDATE
+SET helpful = EXCLUDED.helpful
+`
+
+type UpsertReviewVoteParams struct {
+	VoterID    string
+	Helpful    bool
+	RecordID   string
+	RecordType string
+	AuthorID   string
+}
+
+// UpsertReviewVote records or changes the vote of a voter on a review, and affects no rows if the
+// author has no approved review of the record.
+func (q *Queries) UpsertReviewVote(ctx context.Context, arg UpsertReviewVoteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertReviewVote,
+		arg.VoterID,
+		arg.Helpful,
+		arg.RecordID,
+		arg.RecordType,
+		arg.AuthorID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,198 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: revisions.sql
+
+package dbGen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CopyRevisionsParams struct {
+	MovieID  string
+	Version  int64
+	Author   string
+	Changes  []byte
+	Snapshot []byte
+}
+
+const getLatestRevision = `-- name: GetLatestRevision :one
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+ORDER BY version DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestRevision(ctx context.Context, movieID string) (MovieRevision, error) {
+	row := q.db.QueryRow(ctx, getLatestRevision, movieID)
+	var i MovieRevision
+	err := row.Scan(
+		&i.MovieID,
+		&i.Version,
+		&i.Author,
+		&i.CreatedAt,
+		&i.Changes,
+		&i.Snapshot,
+	)
+	return i, err
+}
+
+const getRevision = `-- name: GetRevision :one
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+  AND version = $2
+`
+
+type GetRevisionParams struct {
+	MovieID string
+	Version int64
+}
+
+func (q *Queries) GetRevision(ctx context.Context, arg GetRevisionParams) (MovieRevision, error) {
+	row := q.db.QueryRow(ctx, getRevision, arg.MovieID, arg.Version)
+	var i MovieRevision
+	err := row.Scan(
+		&i.MovieID,
+		&i.Version,
+		&i.Author,
+		&i.CreatedAt,
+		&i.Changes,
+		&i.Snapshot,
+	)
+	return i, err
+}
+
+const getRevisionAsOf = `-- name: GetRevisionAsOf :one
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+  AND created_at <= $2
+ORDER BY version DESC
+LIMIT 1
+`
+
+type GetRevisionAsOfParams struct {
+	MovieID string
+	AsOf    pgtype.Timestamptz
+}
+
+func (q *Queries) GetRevisionAsOf(ctx context.Context, arg GetRevisionAsOfParams) (MovieRevision, error) {
+	row := q.db.QueryRow(ctx, getRevisionAsOf, arg.MovieID, arg.AsOf)
+	var i MovieRevision
+	err := row.Scan(
+		&i.MovieID,
+		&i.Version,
+		&i.Author,
+		&i.CreatedAt,
+		&i.Changes,
+		&i.Snapshot,
+	)
+	return i, err
+}
+
+const insertRevision = `-- name: InsertRevision :one
+INSERT INTO movie_revisions (movie_id, version, author, changes, snapshot)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING created_at
+`
+
+type InsertRevisionParams struct {
+	MovieID  string
+	Version  int64
+	Author   string
+	Changes  []byte
+	Snapshot []byte
+}
+
+func (q *Queries) InsertRevision(ctx context.Context, arg InsertRevisionParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, insertRevision,
+		arg.MovieID,
+		arg.Version,
+		arg.Author,
+		arg.Changes,
+		arg.Snapshot,
+	)
+	var created_at pgtype.Timestamptz
+	err := row.Scan(&created_at)
+	return created_at, err
+}
+
+const listLatestRevisions = `-- name: ListLatestRevisions :many
+SELECT DISTINCT ON (movie_id) movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = ANY($1::varchar[])
+ORDER BY movie_id, version DESC
+`
+
+func (q *Queries) ListLatestRevisions(ctx context.Context, movieIds []string) ([]MovieRevision, error) {
+	rows, err := q.db.Query(ctx, listLatestRevisions, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MovieRevision
+	for rows.Next() {
+		var i MovieRevision
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.Version,
+			&i.Author,
+			&i.CreatedAt,
+			&i.Changes,
+			&i.Snapshot,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRevisions = `-- name: ListRevisions :many
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+ORDER BY version DESC
+LIMIT $3 OFFSET $2
+`
+
+type ListRevisionsParams struct {
+	MovieID    string
+	PageOffset int32
+	PageLimit  int32
+}
+
+func (q *Queries) ListRevisions(ctx context.Context, arg ListRevisionsParams) ([]MovieRevision, error) {
+	rows, err := q.db.Query(ctx, listRevisions, arg.MovieID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MovieRevision
+	for rows.Next() {
+		var i MovieRevision
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.Version,
+			&i.Author,
+			&i.CreatedAt,
+			&i.Changes,
+			&i.Snapshot,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,109 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: slugs.sql
+
+package dbGen
+
+import (
+	"context"
+)
+
+const claimSlug = `-- name: ClaimSlug :execrows
+INSERT INTO movie_slugs (slug, movie_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE
+SET is_current = TRUE
+WHERE movie_slugs.movie_id = EXCLUDED.movie_id
+`
+
+type ClaimSlugParams struct {
+	Slug    string
+	MovieID string
+}
+
+// A slug that belongs to another movie is left alone, so no row is affected.
+func (q *Queries) ClaimSlug(ctx context.Context, arg ClaimSlugParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimSlug, arg.Slug, arg.MovieID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCurrentSlug = `-- name: GetCurrentSlug :one
+SELECT slug
+FROM movie_slugs
+WHERE movie_id = $1
+  AND is_current
+`
+
+func (q *Queries) GetCurrentSlug(ctx context.Context, movieID string) (string, error) {
+	row := q.db.QueryRow(ctx, getCurrentSlug, movieID)
+	var slug string
+	err := row.Scan(&slug)
+	return slug, err
+}
+
+const listCurrentSlugsForMovies = `-- name: ListCurrentSlugsForMovies :many
+SELECT movie_id, slug
+FROM movie_slugs
+WHERE movie_id = ANY($1::varchar[])
+  AND is_current
+`
+
+type ListCurrentSlugsForMoviesRow struct {
+	MovieID string
+	Slug    string
+}
+
+func (q *Queries) ListCurrentSlugsForMovies(ctx context.Context, movieIds []string) ([]ListCurrentSlugsForMoviesRow, error) {
+	rows, err := q.db.Query(ctx, listCurrentSlugsForMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCurrentSlugsForMoviesRow
+	for rows.Next() {
+		var i ListCurrentSlugsForMoviesRow
+		if err := rows.Scan(&i.MovieID, &i.Slug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resolveSlug = `-- name: ResolveSlug :one
+SELECT movie_id
+FROM movie_slugs
+WHERE slug = $1
+`
+
+func (q *Queries) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	row := q.db.QueryRow(ctx, resolveSlug, slug)
+	var movie_id string
+	err := row.Scan(&movie_id)
+	return movie_id, err
+}
+
+const retireCurrentSlug = `-- name: RetireCurrentSlug :exec
+UPDATE movie_slugs
+SET is_current = FALSE
+WHERE movie_id = $1
+  AND is_current
+  AND slug <> $2::varchar
+`
+
+type RetireCurrentSlugParams struct {
+	MovieID string
+	Keep    string
+}
+
+func (q *Queries) RetireCurrentSlug(ctx context.Context, arg RetireCurrentSlugParams) error {
+	_, err := q.db.Exec(ctx, retireCurrentSlug, arg.MovieID, arg.Keep)
+	return err
+}
@@ -0,0 +1,119 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: translations.sql
+
+package dbGen
+
+import (
+	"context"
+)
+
+const deleteTranslation = `-- name: DeleteTranslation :execrows
+DELETE FROM movie_translations
+WHERE movie_id = $1
+  AND locale = $2
+`
+
+type DeleteTranslationParams struct {
+	MovieID string
+	Locale  string
+}
+
+func (q *Queries) DeleteTranslation(ctx context.Context, arg DeleteTranslationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTranslation, arg.MovieID, arg.Locale)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listTranslations = `-- name: ListTranslations :many
+SELECT locale, title, description
+FROM movie_translations
+WHERE movie_id = $1
+ORDER BY locale
+`
+
+type ListTranslationsRow struct {
+	Locale      string
+	Title       string
+	Description string
+}
+
+func (q *Queries) ListTranslations(ctx context.Context, movieID string) ([]ListTranslationsRow, error) {
+	rows, err := q.db.Query(ctx, listTranslations, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTranslationsRow
+	for rows.Next() {
+		var i ListTranslationsRow
+		if err := rows.Scan(&i.Locale, &i.Title, &i.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTranslationsForMovies = `-- name: ListTranslationsForMovies :many
+SELECT movie_id, locale, title, description
+FROM movie_translations
+WHERE movie_id = ANY($1::varchar[])
+ORDER BY movie_id, locale
+`
+
+func (q *Queries) ListTranslationsForMovies(ctx context.Context, movieIds []string) ([]MovieTranslation, error) {
+	rows, err := q.db.Query(ctx, listTranslationsForMovies, movieIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MovieTranslation
+	for rows.Next() {
+		var i MovieTranslation
+		if err := rows.Scan(
+			&i.MovieID,
+			&i.Locale,
+			&i.Title,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTranslation = `-- name: UpsertTranslation :exec
+INSERT INTO movie_translations (movie_id, locale, title, description)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (movie_id, locale) DO UPDATE
+SET title = EXCLUDED.title,
+    description = EXCLUDED.description
+`
+
+type UpsertTranslationParams struct {
+	MovieID     string
+	Locale      string
+	Title       string
+	Description string
+}
+
+func (q *Queries) UpsertTranslation(ctx context.Context, arg UpsertTranslationParams) error {
+	_, err := q.db.Exec(ctx, upsertTranslation,
+		arg.MovieID,
+		arg.Locale,
+		arg.Title,
+		arg.Description,
+	)
+	return err
+}
@@ -12,36 +12,94 @@ package repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 	model "movieexample.com/metadata/pkg/model"
 )
 
-// MockmetadataRepository is a mock of metadataRepository interface.
-type MockmetadataRepository struct {
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockmetadataRepositoryMockRecorder
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
 }
 
-// MockmetadataRepositoryMockRecorder is the mock recorder for MockmetadataRepository.
-type MockmetadataRepositoryMockRecorder struct {
-	mock *MockmetadataRepository
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
 }
 
-// NewMockmetadataRepository creates a new mock instance.
-func NewMockmetadataRepository(ctrl *gomock.Controller) *MockmetadataRepository {
-	mock := &MockmetadataRepository{ctrl: ctrl}
-	mock.recorder = &MockmetadataRepositoryMockRecorder{mock}
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockmetadataRepository) EXPECT() *MockmetadataRepositoryMockRecorder {
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CompareAndPut mocks base method.
+func (m_2 *MockRepository) CompareAndPut(ctx context.Context, id string, m *model.Metadata, expectedVersion int64) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "CompareAndPut", ctx, id, m, expectedVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareAndPut indicates an expected call of CompareAndPut.
+func (mr *MockRepositoryMockRecorder) CompareAndPut(ctx, id, m, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndPut", reflect.TypeOf((*MockRepository)(nil).CompareAndPut), ctx, id, m, expectedVersion)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
+}
+
+// DeleteRelation mocks base method.
+func (m *MockRepository) DeleteRelation(ctx context.Context, rel model.Relation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRelation", ctx, rel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRelation indicates an expected call of DeleteRelation.
+func (mr *MockRepositoryMockRecorder) DeleteRelation(ctx, rel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRelation", reflect.TypeOf((*MockRepository)(nil).DeleteRelation), ctx, rel)
+}
+
+// DeleteTranslation mocks base method.
+func (m *MockRepository) DeleteTranslation(ctx context.Context, movieID, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranslation", ctx, movieID, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTranslation indicates an expected call of DeleteTranslation.
+func (mr *MockRepositoryMockRecorder) DeleteTranslation(ctx, movieID, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranslation", reflect.TypeOf((*MockRepository)(nil).DeleteTranslation), ctx, movieID, locale)
+}
+
 // Get mocks base method.
-func (m *MockmetadataRepository) Get(ctx context.Context, id string) (*model.Metadata, error) {
+func (m *MockRepository) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(*model.Metadata)
@@ -50,13 +108,192 @@ func (m *MockmetadataRepository) Get(ctx context.Context, id string) (*model.Met
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockmetadataRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, id)
+}
+
+// GetAsOf mocks base method.
+func (m *MockRepository) GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAsOf", ctx, id, asOf)
+	ret0, _ := ret[0].(*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAsOf indicates an expected call of GetAsOf.
+func (mr *MockRepositoryMockRecorder) GetAsOf(ctx, id, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAsOf", reflect.TypeOf((*MockRepository)(nil).GetAsOf), ctx, id, asOf)
+}
+
+// GetByExternalID mocks base method.
+func (m *MockRepository) GetByExternalID(ctx context.Context, id model.ExternalID) (*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalID", ctx, id)
+	ret0, _ := ret[0].(*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalID indicates an expected call of GetByExternalID.
+func (mr *MockRepositoryMockRecorder) GetByExternalID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalID", reflect.TypeOf((*MockRepository)(nil).GetByExternalID), ctx, id)
+}
+
+// GetMany mocks base method.
+func (m *MockRepository) GetMany(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", ctx, ids)
+	ret0, _ := ret[0].([]*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockRepositoryMockRecorder) GetMany(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockRepository)(nil).GetMany), ctx, ids)
+}
+
+// GetRevision mocks base method.
+func (m *MockRepository) GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id, version)
+	ret0, _ := ret[0].(*model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockRepositoryMockRecorder) GetRevision(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockRepository)(nil).GetRevision), ctx, id, version)
+}
+
+// List mocks base method.
+func (m *MockRepository) List(ctx context.Context, filter model.Filter, sort model.SortOrder, offset, limit int) ([]*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter, sort, offset, limit)
+	ret0, _ := ret[0].([]*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryMockRecorder) List(ctx, filter, sort, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepository)(nil).List), ctx, filter, sort, offset, limit)
+}
+
+// ListAfter mocks base method.
+func (m *MockRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, afterID, limit)
+	ret0, _ := ret[0].([]*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockRepositoryMockRecorder) ListAfter(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockRepository)(nil).ListAfter), ctx, afterID, limit)
+}
+
+// ListImages mocks base method.
+func (m *MockRepository) ListImages(ctx context.Context, movieID string) ([]model.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImages", ctx, movieID)
+	ret0, _ := ret[0].([]model.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImages indicates an expected call of ListImages.
+func (mr *MockRepositoryMockRecorder) ListImages(ctx, movieID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImages", reflect.TypeOf((*MockRepository)(nil).ListImages), ctx, movieID)
+}
+
+// ListRelations mocks base method.
+func (m *MockRepository) ListRelations(ctx context.Context, id string) ([]model.Relation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRelations", ctx, id)
+	ret0, _ := ret[0].([]model.Relation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRelations indicates an expected call of ListRelations.
+func (mr *MockRepositoryMockRecorder) ListRelations(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmetadataRepository)(nil).Get), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelations", reflect.TypeOf((*MockRepository)(nil).ListRelations), ctx, id)
+}
+
+// ListRevisions mocks base method.
+func (m *MockRepository) ListRevisions(ctx context.Context, id string, offset, limit int) ([]*model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, id, offset, limit)
+	ret0, _ := ret[0].([]*model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockRepositoryMockRecorder) ListRevisions(ctx, id, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockRepository)(nil).ListRevisions), ctx, id, offset, limit)
+}
+
+// ListTranslations mocks base method.
+func (m *MockRepository) ListTranslations(ctx context.Context, movieID string) ([]model.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranslations", ctx, movieID)
+	ret0, _ := ret[0].([]model.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranslations indicates an expected call of ListTranslations.
+func (mr *MockRepositoryMockRecorder) ListTranslations(ctx, movieID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranslations", reflect.TypeOf((*MockRepository)(nil).ListTranslations), ctx, movieID)
+}
+
+// ListTranslationsForMovies mocks base method.
+func (m *MockRepository) ListTranslationsForMovies(ctx context.Context, movieIDs []string) (map[string][]model.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranslationsForMovies", ctx, movieIDs)
+	ret0, _ := ret[0].(map[string][]model.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranslationsForMovies indicates an expected call of ListTranslationsForMovies.
+func (mr *MockRepositoryMockRecorder) ListTranslationsForMovies(ctx, movieIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranslationsForMovies", reflect.TypeOf((*MockRepository)(nil).ListTranslationsForMovies), ctx, movieIDs)
+}
+
+// Purge mocks base method.
+func (m *MockRepository) Purge(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockRepositoryMockRecorder) Purge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepository)(nil).Purge), ctx, id)
 }
 
 // Put mocks base method.
-func (m_2 *MockmetadataRepository) Put(ctx context.Context, id string, m *model.Metadata) error {
+func (m_2 *MockRepository) Put(ctx context.Context, id string, m *model.Metadata) error {
 	m_2.ctrl.T.Helper()
 	ret := m_2.ctrl.Call(m_2, "Put", ctx, id, m)
 	ret0, _ := ret[0].(error)
@@ -64,7 +301,122 @@ func (m_2 *MockmetadataRepository) Put(ctx context.Context, id string, m *model.
 }
 
 // Put indicates an expected call of Put.
-func (mr *MockmetadataRepositoryMockRecorder) Put(ctx, id, m any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Put(ctx, id, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockRepository)(nil).Put), ctx, id, m)
+}
+
+// PutImage mocks base method.
+func (m *MockRepository) PutImage(ctx context.Context, img *model.Image) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutImage", ctx, img)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutImage indicates an expected call of PutImage.
+func (mr *MockRepositoryMockRecorder) PutImage(ctx, img any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutImage", reflect.TypeOf((*MockRepository)(nil).PutImage), ctx, img)
+}
+
+// PutMany mocks base method.
+func (m *MockRepository) PutMany(ctx context.Context, ms []*model.Metadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutMany", ctx, ms)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutMany indicates an expected call of PutMany.
+func (mr *MockRepositoryMockRecorder) PutMany(ctx, ms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutMany", reflect.TypeOf((*MockRepository)(nil).PutMany), ctx, ms)
+}
+
+// PutRelation mocks base method.
+func (m *MockRepository) PutRelation(ctx context.Context, rel model.Relation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutRelation", ctx, rel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutRelation indicates an expected call of PutRelation.
+func (mr *MockRepositoryMockRecorder) PutRelation(ctx, rel any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRelation", reflect.TypeOf((*MockRepository)(nil).PutRelation), ctx, rel)
+}
+
+// PutTranslation mocks base method.
+func (m *MockRepository) PutTranslation(ctx context.Context, movieID string, t model.Translation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutTranslation", ctx, movieID, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutTranslation indicates an expected call of PutTranslation.
+func (mr *MockRepositoryMockRecorder) PutTranslation(ctx, movieID, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutTranslation", reflect.TypeOf((*MockRepository)(nil).PutTranslation), ctx, movieID, t)
+}
+
+// ResolveSlug mocks base method.
+func (m *MockRepository) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveSlug", ctx, slug)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveSlug indicates an expected call of ResolveSlug.
+func (mr *MockRepositoryMockRecorder) ResolveSlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveSlug", reflect.TypeOf((*MockRepository)(nil).ResolveSlug), ctx, slug)
+}
+
+// Search mocks base method.
+func (m *MockRepository) Search(ctx context.Context, query string, offset, limit int) ([]model.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, offset, limit)
+	ret0, _ := ret[0].([]model.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockRepositoryMockRecorder) Search(ctx, query, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockRepository)(nil).Search), ctx, query, offset, limit)
+}
+
+// Undelete mocks base method.
+func (m *MockRepository) Undelete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockRepositoryMockRecorder) Undelete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockRepository)(nil).Undelete), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(ctx context.Context, id string, patch *model.Metadata, fields []model.Field, expectedVersion int64) (*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, patch, fields, expectedVersion)
+	ret0, _ := ret[0].(*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(ctx, id, patch, fields, expectedVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockmetadataRepository)(nil).Put), ctx, id, m)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, id, patch, fields, expectedVersion)
 }
@@ -22,6 +22,7 @@ import (
 type MockratingGateway struct {
 	ctrl     *gomock.Controller
 	recorder *MockratingGatewayMockRecorder
+	isgomock struct{}
 }
 
 // MockratingGatewayMockRecorder is the mock recorder for MockratingGateway.
@@ -56,6 +57,51 @@ func (mr *MockratingGatewayMockRecorder) GetAggregatedRating(ctx, recordID, reco
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggregatedRating", reflect.TypeOf((*MockratingGateway)(nil).GetAggregatedRating), ctx, recordID, recordType)
 }
 
+// GetAggregatedRatings mocks base method.
+func (m *MockratingGateway) GetAggregatedRatings(ctx context.Context, recordIDs []model0.RecordID, recordType model0.RecordType) (map[model0.RecordID]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAggregatedRatings", ctx, recordIDs, recordType)
+	ret0, _ := ret[0].(map[model0.RecordID]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAggregatedRatings indicates an expected call of GetAggregatedRatings.
+func (mr *MockratingGatewayMockRecorder) GetAggregatedRatings(ctx, recordIDs, recordType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggregatedRatings", reflect.TypeOf((*MockratingGateway)(nil).GetAggregatedRatings), ctx, recordIDs, recordType)
+}
+
+// ListTopRated mocks base method.
+func (m *MockratingGateway) ListTopRated(ctx context.Context, query model0.LeaderboardQuery) ([]model0.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTopRated", ctx, query)
+	ret0, _ := ret[0].([]model0.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTopRated indicates an expected call of ListTopRated.
+func (mr *MockratingGatewayMockRecorder) ListTopRated(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTopRated", reflect.TypeOf((*MockratingGateway)(nil).ListTopRated), ctx, query)
+}
+
+// ListTrending mocks base method.
+func (m *MockratingGateway) ListTrending(ctx context.Context, query model0.LeaderboardQuery) ([]model0.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrending", ctx, query)
+	ret0, _ := ret[0].([]model0.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrending indicates an expected call of ListTrending.
+func (mr *MockratingGatewayMockRecorder) ListTrending(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrending", reflect.TypeOf((*MockratingGateway)(nil).ListTrending), ctx, query)
+}
+
 // PutRating mocks base method.
 func (m *MockratingGateway) PutRating(ctx context.Context, recordID model0.RecordID, recordType model0.RecordType, rating *model0.Rating) error {
 	m.ctrl.T.Helper()
@@ -74,6 +120,7 @@ func (mr *MockratingGatewayMockRecorder) PutRating(ctx, recordID, recordType, ra
 type MockmetadataGateway struct {
 	ctrl     *gomock.Controller
 	recorder *MockmetadataGatewayMockRecorder
+	isgomock struct{}
 }
 
 // MockmetadataGatewayMockRecorder is the mock recorder for MockmetadataGateway.
@@ -94,16 +141,61 @@ func (m *MockmetadataGateway) EXPECT() *MockmetadataGatewayMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockmetadataGateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
+func (m *MockmetadataGateway) Get(ctx context.Context, id string, locales []string) (*model.Metadata, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret := m.ctrl.Call(m, "Get", ctx, id, locales)
 	ret0, _ := ret[0].(*model.Metadata)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockmetadataGatewayMockRecorder) Get(ctx, id any) *gomock.Call {
+func (mr *MockmetadataGatewayMockRecorder) Get(ctx, id, locales any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmetadataGateway)(nil).Get), ctx, id, locales)
+}
+
+// GetBySlug mocks base method.
+func (m *MockmetadataGateway) GetBySlug(ctx context.Context, slug string, locales []string) (*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySlug", ctx, slug, locales)
+	ret0, _ := ret[0].(*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySlug indicates an expected call of GetBySlug.
+func (mr *MockmetadataGatewayMockRecorder) GetBySlug(ctx, slug, locales any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySlug", reflect.TypeOf((*MockmetadataGateway)(nil).GetBySlug), ctx, slug, locales)
+}
+
+// GetMany mocks base method.
+func (m *MockmetadataGateway) GetMany(ctx context.Context, ids, locales []string) ([]*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", ctx, ids, locales)
+	ret0, _ := ret[0].([]*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockmetadataGatewayMockRecorder) GetMany(ctx, ids, locales any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockmetadataGateway)(nil).GetMany), ctx, ids, locales)
+}
+
+// GetRelated mocks base method.
+func (m *MockmetadataGateway) GetRelated(ctx context.Context, id string, locales []string) ([]model.RelatedMovie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelated", ctx, id, locales)
+	ret0, _ := ret[0].([]model.RelatedMovie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelated indicates an expected call of GetRelated.
+func (mr *MockmetadataGatewayMockRecorder) GetRelated(ctx, id, locales any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmetadataGateway)(nil).Get), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelated", reflect.TypeOf((*MockmetadataGateway)(nil).GetRelated), ctx, id, locales)
 }
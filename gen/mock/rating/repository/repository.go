@@ -12,36 +12,52 @@ package repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 	model "movieexample.com/rating/pkg/model"
 )
 
-// MockratingRepository is a mock of ratingRepository interface.
-type MockratingRepository struct {
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockratingRepositoryMockRecorder
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
 }
 
-// MockratingRepositoryMockRecorder is the mock recorder for MockratingRepository.
-type MockratingRepositoryMockRecorder struct {
-	mock *MockratingRepository
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
 }
 
-// NewMockratingRepository creates a new mock instance.
-func NewMockratingRepository(ctrl *gomock.Controller) *MockratingRepository {
-	mock := &MockratingRepository{ctrl: ctrl}
-	mock.recorder = &MockratingRepositoryMockRecorder{mock}
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockratingRepository) EXPECT() *MockratingRepositoryMockRecorder {
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// DeleteRating mocks base method.
+func (m *MockRepository) DeleteRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRating", ctx, recordID, recordType, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRating indicates an expected call of DeleteRating.
+func (mr *MockRepositoryMockRecorder) DeleteRating(ctx, recordID, recordType, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRating", reflect.TypeOf((*MockRepository)(nil).DeleteRating), ctx, recordID, recordType, userID)
+}
+
 // Get mocks base method.
-func (m *MockratingRepository) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
+func (m *MockRepository) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", ctx, recordID, recordType)
 	ret0, _ := ret[0].([]model.Rating)
@@ -50,29 +66,208 @@ func (m *MockratingRepository) Get(ctx context.Context, recordID model.RecordID,
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockratingRepositoryMockRecorder) Get(ctx, recordID, recordType any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Get(ctx, recordID, recordType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, recordID, recordType)
+}
+
+// GetDecayedMean mocks base method.
+func (m *MockRepository) GetDecayedMean(ctx context.Context, recordID model.RecordID, recordType model.RecordType, halfLife time.Duration, now time.Time) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDecayedMean", ctx, recordID, recordType, halfLife, now)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDecayedMean indicates an expected call of GetDecayedMean.
+func (mr *MockRepositoryMockRecorder) GetDecayedMean(ctx, recordID, recordType, halfLife, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDecayedMean", reflect.TypeOf((*MockRepository)(nil).GetDecayedMean), ctx, recordID, recordType, halfLife, now)
+}
+
+// GetHistogram mocks base method.
+func (m *MockRepository) GetHistogram(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistogram", ctx, recordID, recordType)
+	ret0, _ := ret[0].(map[model.RatingValue]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistogram indicates an expected call of GetHistogram.
+func (mr *MockRepositoryMockRecorder) GetHistogram(ctx, recordID, recordType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistogram", reflect.TypeOf((*MockRepository)(nil).GetHistogram), ctx, recordID, recordType)
+}
+
+// GetHistograms mocks base method.
+func (m *MockRepository) GetHistograms(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]map[model.RatingValue]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistograms", ctx, recordIDs, recordType)
+	ret0, _ := ret[0].(map[model.RecordID]map[model.RatingValue]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistograms indicates an expected call of GetHistograms.
+func (mr *MockRepositoryMockRecorder) GetHistograms(ctx, recordIDs, recordType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistograms", reflect.TypeOf((*MockRepository)(nil).GetHistograms), ctx, recordIDs, recordType)
+}
+
+// GetRecordTypeHistogram mocks base method.
+func (m *MockRepository) GetRecordTypeHistogram(ctx context.Context, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordTypeHistogram", ctx, recordType)
+	ret0, _ := ret[0].(map[model.RatingValue]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordTypeHistogram indicates an expected call of GetRecordTypeHistogram.
+func (mr *MockRepositoryMockRecorder) GetRecordTypeHistogram(ctx, recordType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordTypeHistogram", reflect.TypeOf((*MockRepository)(nil).GetRecordTypeHistogram), ctx, recordType)
+}
+
+// GetUserRating mocks base method.
+func (m *MockRepository) GetUserRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) (*model.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRating", ctx, recordID, recordType, userID)
+	ret0, _ := ret[0].(*model.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRating indicates an expected call of GetUserRating.
+func (mr *MockRepositoryMockRecorder) GetUserRating(ctx, recordID, recordType, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRating", reflect.TypeOf((*MockRepository)(nil).GetUserRating), ctx, recordID, recordType, userID)
+}
+
+// ListReviews mocks base method.
+func (m *MockRepository) ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, state model.ReviewState, sort model.ReviewSort, after *model.ReviewsCursor, limit int) ([]model.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReviews", ctx, recordID, recordType, state, sort, after, limit)
+	ret0, _ := ret[0].([]model.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReviews indicates an expected call of ListReviews.
+func (mr *MockRepositoryMockRecorder) ListReviews(ctx, recordID, recordType, state, sort, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviews", reflect.TypeOf((*MockRepository)(nil).ListReviews), ctx, recordID, recordType, state, sort, after, limit)
+}
+
+// ListTopRated mocks base method.
+func (m *MockRepository) ListTopRated(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTopRated", ctx, query, now)
+	ret0, _ := ret[0].([]model.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTopRated indicates an expected call of ListTopRated.
+func (mr *MockRepositoryMockRecorder) ListTopRated(ctx, query, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTopRated", reflect.TypeOf((*MockRepository)(nil).ListTopRated), ctx, query, now)
+}
+
+// ListTrending mocks base method.
+func (m *MockRepository) ListTrending(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrending", ctx, query, now)
+	ret0, _ := ret[0].([]model.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrending indicates an expected call of ListTrending.
+func (mr *MockRepositoryMockRecorder) ListTrending(ctx, query, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrending", reflect.TypeOf((*MockRepository)(nil).ListTrending), ctx, query, now)
+}
+
+// ListUserRatings mocks base method.
+func (m *MockRepository) ListUserRatings(ctx context.Context, userID model.UserID, recordType model.RecordType, after *model.UserRatingsCursor, limit int) ([]model.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRatings", ctx, userID, recordType, after, limit)
+	ret0, _ := ret[0].([]model.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRatings indicates an expected call of ListUserRatings.
+func (mr *MockRepositoryMockRecorder) ListUserRatings(ctx, userID, recordType, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRatings", reflect.TypeOf((*MockRepository)(nil).ListUserRatings), ctx, userID, recordType, after, limit)
+}
+
+// ModerateReview mocks base method.
+func (m *MockRepository) ModerateReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, state model.ReviewState, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateReview", ctx, recordID, recordType, userID, state, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateReview indicates an expected call of ModerateReview.
+func (mr *MockRepositoryMockRecorder) ModerateReview(ctx, recordID, recordType, userID, state, reason any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockratingRepository)(nil).Get), ctx, recordID, recordType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateReview", reflect.TypeOf((*MockRepository)(nil).ModerateReview), ctx, recordID, recordType, userID, state, reason)
 }
 
 // Put mocks base method.
-func (m *MockratingRepository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+func (m *MockRepository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Put", ctx, recordID, recordType, rating)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockRepositoryMockRecorder) Put(ctx, recordID, recordType, rating any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockRepository)(nil).Put), ctx, recordID, recordType, rating)
+}
+
+// PutReview mocks base method.
+func (m *MockRepository) PutReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, review *model.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutReview", ctx, recordID, recordType, userID, review)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Put indicates an expected call of Put.
-func (mr *MockratingRepositoryMockRecorder) Put(ctx, recordID, recordType, rating any) *gomock.Call {
+// PutReview indicates an expected call of PutReview.
+func (mr *MockRepositoryMockRecorder) PutReview(ctx, recordID, recordType, userID, review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutReview", reflect.TypeOf((*MockRepository)(nil).PutReview), ctx, recordID, recordType, userID, review)
+}
+
+// VoteReview mocks base method.
+func (m *MockRepository) VoteReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, authorID, voterID model.UserID, helpful bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteReview", ctx, recordID, recordType, authorID, voterID, helpful)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VoteReview indicates an expected call of VoteReview.
+func (mr *MockRepositoryMockRecorder) VoteReview(ctx, recordID, recordType, authorID, voterID, helpful any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockratingRepository)(nil).Put), ctx, recordID, recordType, rating)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteReview", reflect.TypeOf((*MockRepository)(nil).VoteReview), ctx, recordID, recordType, authorID, voterID, helpful)
 }
 
 // MockrateIngester is a mock of rateIngester interface.
 type MockrateIngester struct {
 	ctrl     *gomock.Controller
 	recorder *MockrateIngesterMockRecorder
+	isgomock struct{}
 }
 
 // MockrateIngesterMockRecorder is the mock recorder for MockrateIngester.
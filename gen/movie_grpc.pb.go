@@ -23,7 +23,25 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MetadataServiceClient interface {
 	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
+	BatchGetMetadata(ctx context.Context, in *BatchGetMetadataRequest, opts ...grpc.CallOption) (*BatchGetMetadataResponse, error)
 	PutMetadata(ctx context.Context, in *PutMetadataRequest, opts ...grpc.CallOption) (*PutMetadataResponse, error)
+	UpdateMetadata(ctx context.Context, in *UpdateMetadataRequest, opts ...grpc.CallOption) (*UpdateMetadataResponse, error)
+	ListMetadata(ctx context.Context, in *ListMetadataRequest, opts ...grpc.CallOption) (*ListMetadataResponse, error)
+	SearchMetadata(ctx context.Context, in *SearchMetadataRequest, opts ...grpc.CallOption) (*SearchMetadataResponse, error)
+	DeleteMetadata(ctx context.Context, in *DeleteMetadataRequest, opts ...grpc.CallOption) (*DeleteMetadataResponse, error)
+	UndeleteMetadata(ctx context.Context, in *UndeleteMetadataRequest, opts ...grpc.CallOption) (*UndeleteMetadataResponse, error)
+	ListMetadataRevisions(ctx context.Context, in *ListMetadataRevisionsRequest, opts ...grpc.CallOption) (*ListMetadataRevisionsResponse, error)
+	RevertMetadata(ctx context.Context, in *RevertMetadataRequest, opts ...grpc.CallOption) (*RevertMetadataResponse, error)
+	ImportMetadata(ctx context.Context, opts ...grpc.CallOption) (MetadataService_ImportMetadataClient, error)
+	ExportMetadata(ctx context.Context, in *ExportMetadataRequest, opts ...grpc.CallOption) (MetadataService_ExportMetadataClient, error)
+	UploadImage(ctx context.Context, opts ...grpc.CallOption) (MetadataService_UploadImageClient, error)
+	PutMetadataTranslation(ctx context.Context, in *PutMetadataTranslationRequest, opts ...grpc.CallOption) (*PutMetadataTranslationResponse, error)
+	DeleteMetadataTranslation(ctx context.Context, in *DeleteMetadataTranslationRequest, opts ...grpc.CallOption) (*DeleteMetadataTranslationResponse, error)
+	ListMetadataTranslations(ctx context.Context, in *ListMetadataTranslationsRequest, opts ...grpc.CallOption) (*ListMetadataTranslationsResponse, error)
+	LookupMetadataByExternalID(ctx context.Context, in *LookupMetadataByExternalIDRequest, opts ...grpc.CallOption) (*LookupMetadataByExternalIDResponse, error)
+	PutMetadataRelation(ctx context.Context, in *PutMetadataRelationRequest, opts ...grpc.CallOption) (*PutMetadataRelationResponse, error)
+	DeleteMetadataRelation(ctx context.Context, in *DeleteMetadataRelationRequest, opts ...grpc.CallOption) (*DeleteMetadataRelationResponse, error)
+	GetRelatedMovies(ctx context.Context, in *GetRelatedMoviesRequest, opts ...grpc.CallOption) (*GetRelatedMoviesResponse, error)
 }
 
 type metadataServiceClient struct {
@@ -43,6 +61,15 @@ func (c *metadataServiceClient) GetMetadata(ctx context.Context, in *GetMetadata
 	return out, nil
 }
 
+func (c *metadataServiceClient) BatchGetMetadata(ctx context.Context, in *BatchGetMetadataRequest, opts ...grpc.CallOption) (*BatchGetMetadataResponse, error) {
+	out := new(BatchGetMetadataResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/BatchGetMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *metadataServiceClient) PutMetadata(ctx context.Context, in *PutMetadataRequest, opts ...grpc.CallOption) (*PutMetadataResponse, error) {
 	out := new(PutMetadataResponse)
 	err := c.cc.Invoke(ctx, "/MetadataService/PutMetadata", in, out, opts...)
@@ -52,12 +79,256 @@ func (c *metadataServiceClient) PutMetadata(ctx context.Context, in *PutMetadata
 	return out, nil
 }
 
+func (c *metadataServiceClient) UpdateMetadata(ctx context.Context, in *UpdateMetadataRequest, opts ...grpc.CallOption) (*UpdateMetadataResponse, error) {
+	out := new(UpdateMetadataResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/UpdateMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) ListMetadata(ctx context.Context, in *ListMetadataRequest, opts ...grpc.CallOption) (*ListMetadataResponse, error) {
+	out := new(ListMetadataResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/ListMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) SearchMetadata(ctx context.Context, in *SearchMetadataRequest, opts ...grpc.CallOption) (*SearchMetadataResponse, error) {
+	out := new(SearchMetadataResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/SearchMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) DeleteMetadata(ctx context.Context, in *DeleteMetadataRequest, opts ...grpc.CallOption) (*DeleteMetadataResponse, error) {
+	out := new(DeleteMetadataResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/DeleteMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) UndeleteMetadata(ctx context.Context, in *UndeleteMetadataRequest, opts ...grpc.CallOption) (*UndeleteMetadataResponse, error) {
+	out := new(UndeleteMetadataResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/UndeleteMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) ListMetadataRevisions(ctx context.Context, in *ListMetadataRevisionsRequest, opts ...grpc.CallOption) (*ListMetadataRevisionsResponse, error) {
+	out := new(ListMetadataRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/ListMetadataRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) RevertMetadata(ctx context.Context, in *RevertMetadataRequest, opts ...grpc.CallOption) (*RevertMetadataResponse, error) {
+	out := new(RevertMetadataResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/RevertMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) ImportMetadata(ctx context.Context, opts ...grpc.CallOption) (MetadataService_ImportMetadataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MetadataService_ServiceDesc.Streams[0], "/MetadataService/ImportMetadata", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metadataServiceImportMetadataClient{stream}
+	return x, nil
+}
+
+type MetadataService_ImportMetadataClient interface {
+	Send(*ImportMetadataRequest) error
+	CloseAndRecv() (*ImportMetadataResponse, error)
+	grpc.ClientStream
+}
+
+type metadataServiceImportMetadataClient struct {
+	grpc.ClientStream
+}
+
+func (x *metadataServiceImportMetadataClient) Send(m *ImportMetadataRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *metadataServiceImportMetadataClient) CloseAndRecv() (*ImportMetadataResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportMetadataResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *metadataServiceClient) ExportMetadata(ctx context.Context, in *ExportMetadataRequest, opts ...grpc.CallOption) (MetadataService_ExportMetadataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MetadataService_ServiceDesc.Streams[1], "/MetadataService/ExportMetadata", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metadataServiceExportMetadataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MetadataService_ExportMetadataClient interface {
+	Recv() (*ExportMetadataResponse, error)
+	grpc.ClientStream
+}
+
+type metadataServiceExportMetadataClient struct {
+	grpc.ClientStream
+}
+
+func (x *metadataServiceExportMetadataClient) Recv() (*ExportMetadataResponse, error) {
+	m := new(ExportMetadataResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *metadataServiceClient) UploadImage(ctx context.Context, opts ...grpc.CallOption) (MetadataService_UploadImageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MetadataService_ServiceDesc.Streams[2], "/MetadataService/UploadImage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metadataServiceUploadImageClient{stream}
+	return x, nil
+}
+
+type MetadataService_UploadImageClient interface {
+	Send(*UploadImageRequest) error
+	CloseAndRecv() (*UploadImageResponse, error)
+	grpc.ClientStream
+}
+
+type metadataServiceUploadImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *metadataServiceUploadImageClient) Send(m *UploadImageRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *metadataServiceUploadImageClient) CloseAndRecv() (*UploadImageResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadImageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *metadataServiceClient) PutMetadataTranslation(ctx context.Context, in *PutMetadataTranslationRequest, opts ...grpc.CallOption) (*PutMetadataTranslationResponse, error) {
+	out := new(PutMetadataTranslationResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/PutMetadataTranslation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) DeleteMetadataTranslation(ctx context.Context, in *DeleteMetadataTranslationRequest, opts ...grpc.CallOption) (*DeleteMetadataTranslationResponse, error) {
+	out := new(DeleteMetadataTranslationResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/DeleteMetadataTranslation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) ListMetadataTranslations(ctx context.Context, in *ListMetadataTranslationsRequest, opts ...grpc.CallOption) (*ListMetadataTranslationsResponse, error) {
+	out := new(ListMetadataTranslationsResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/ListMetadataTranslations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) LookupMetadataByExternalID(ctx context.Context, in *LookupMetadataByExternalIDRequest, opts ...grpc.CallOption) (*LookupMetadataByExternalIDResponse, error) {
+	out := new(LookupMetadataByExternalIDResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/LookupMetadataByExternalID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) PutMetadataRelation(ctx context.Context, in *PutMetadataRelationRequest, opts ...grpc.CallOption) (*PutMetadataRelationResponse, error) {
+	out := new(PutMetadataRelationResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/PutMetadataRelation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) DeleteMetadataRelation(ctx context.Context, in *DeleteMetadataRelationRequest, opts ...grpc.CallOption) (*DeleteMetadataRelationResponse, error) {
+	out := new(DeleteMetadataRelationResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/DeleteMetadataRelation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) GetRelatedMovies(ctx context.Context, in *GetRelatedMoviesRequest, opts ...grpc.CallOption) (*GetRelatedMoviesResponse, error) {
+	out := new(GetRelatedMoviesResponse)
+	err := c.cc.Invoke(ctx, "/MetadataService/GetRelatedMovies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MetadataServiceServer is the server API for MetadataService service.
 // All implementations must embed UnimplementedMetadataServiceServer
 // for forward compatibility
 type MetadataServiceServer interface {
 	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
+	BatchGetMetadata(context.Context, *BatchGetMetadataRequest) (*BatchGetMetadataResponse, error)
 	PutMetadata(context.Context, *PutMetadataRequest) (*PutMetadataResponse, error)
+	UpdateMetadata(context.Context, *UpdateMetadataRequest) (*UpdateMetadataResponse, error)
+	ListMetadata(context.Context, *ListMetadataRequest) (*ListMetadataResponse, error)
+	SearchMetadata(context.Context, *SearchMetadataRequest) (*SearchMetadataResponse, error)
+	DeleteMetadata(context.Context, *DeleteMetadataRequest) (*DeleteMetadataResponse, error)
+	UndeleteMetadata(context.Context, *UndeleteMetadataRequest) (*UndeleteMetadataResponse, error)
+	ListMetadataRevisions(context.Context, *ListMetadataRevisionsRequest) (*ListMetadataRevisionsResponse, error)
+	RevertMetadata(context.Context, *RevertMetadataRequest) (*RevertMetadataResponse, error)
+	ImportMetadata(MetadataService_ImportMetadataServer) error
+	ExportMetadata(*ExportMetadataRequest, MetadataService_ExportMetadataServer) error
+	UploadImage(MetadataService_UploadImageServer) error
+	PutMetadataTranslation(context.Context, *PutMetadataTranslationRequest) (*PutMetadataTranslationResponse, error)
+	DeleteMetadataTranslation(context.Context, *DeleteMetadataTranslationRequest) (*DeleteMetadataTranslationResponse, error)
+	ListMetadataTranslations(context.Context, *ListMetadataTranslationsRequest) (*ListMetadataTranslationsResponse, error)
+	LookupMetadataByExternalID(context.Context, *LookupMetadataByExternalIDRequest) (*LookupMetadataByExternalIDResponse, error)
+	PutMetadataRelation(context.Context, *PutMetadataRelationRequest) (*PutMetadataRelationResponse, error)
+	DeleteMetadataRelation(context.Context, *DeleteMetadataRelationRequest) (*DeleteMetadataRelationResponse, error)
+	GetRelatedMovies(context.Context, *GetRelatedMoviesRequest) (*GetRelatedMoviesResponse, error)
 	mustEmbedUnimplementedMetadataServiceServer()
 }
 
@@ -68,9 +339,63 @@ type UnimplementedMetadataServiceServer struct {
 func (UnimplementedMetadataServiceServer) GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
 }
+func (UnimplementedMetadataServiceServer) BatchGetMetadata(context.Context, *BatchGetMetadataRequest) (*BatchGetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetMetadata not implemented")
+}
 func (UnimplementedMetadataServiceServer) PutMetadata(context.Context, *PutMetadataRequest) (*PutMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutMetadata not implemented")
 }
+func (UnimplementedMetadataServiceServer) UpdateMetadata(context.Context, *UpdateMetadataRequest) (*UpdateMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) ListMetadata(context.Context, *ListMetadataRequest) (*ListMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) SearchMetadata(context.Context, *SearchMetadataRequest) (*SearchMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) DeleteMetadata(context.Context, *DeleteMetadataRequest) (*DeleteMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) UndeleteMetadata(context.Context, *UndeleteMetadataRequest) (*UndeleteMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeleteMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) ListMetadataRevisions(context.Context, *ListMetadataRevisionsRequest) (*ListMetadataRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMetadataRevisions not implemented")
+}
+func (UnimplementedMetadataServiceServer) RevertMetadata(context.Context, *RevertMetadataRequest) (*RevertMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) ImportMetadata(MetadataService_ImportMetadataServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) ExportMetadata(*ExportMetadataRequest, MetadataService_ExportMetadataServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) UploadImage(MetadataService_UploadImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadImage not implemented")
+}
+func (UnimplementedMetadataServiceServer) PutMetadataTranslation(context.Context, *PutMetadataTranslationRequest) (*PutMetadataTranslationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutMetadataTranslation not implemented")
+}
+func (UnimplementedMetadataServiceServer) DeleteMetadataTranslation(context.Context, *DeleteMetadataTranslationRequest) (*DeleteMetadataTranslationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMetadataTranslation not implemented")
+}
+func (UnimplementedMetadataServiceServer) ListMetadataTranslations(context.Context, *ListMetadataTranslationsRequest) (*ListMetadataTranslationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMetadataTranslations not implemented")
+}
+func (UnimplementedMetadataServiceServer) LookupMetadataByExternalID(context.Context, *LookupMetadataByExternalIDRequest) (*LookupMetadataByExternalIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupMetadataByExternalID not implemented")
+}
+func (UnimplementedMetadataServiceServer) PutMetadataRelation(context.Context, *PutMetadataRelationRequest) (*PutMetadataRelationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutMetadataRelation not implemented")
+}
+func (UnimplementedMetadataServiceServer) DeleteMetadataRelation(context.Context, *DeleteMetadataRelationRequest) (*DeleteMetadataRelationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMetadataRelation not implemented")
+}
+func (UnimplementedMetadataServiceServer) GetRelatedMovies(context.Context, *GetRelatedMoviesRequest) (*GetRelatedMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelatedMovies not implemented")
+}
 func (UnimplementedMetadataServiceServer) mustEmbedUnimplementedMetadataServiceServer() {}
 
 // UnsafeMetadataServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -102,6 +427,24 @@ func _MetadataService_GetMetadata_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetadataService_BatchGetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).BatchGetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/BatchGetMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).BatchGetMetadata(ctx, req.(*BatchGetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MetadataService_PutMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PutMetadataRequest)
 	if err := dec(in); err != nil {
@@ -117,57 +460,569 @@ func _MetadataService_PutMetadata_Handler(srv interface{}, ctx context.Context,
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(MetadataServiceServer).PutMetadata(ctx, req.(*PutMetadataRequest))
 	}
-	return interceptor(ctx, in, info, handler)
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_UpdateMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).UpdateMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/UpdateMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).UpdateMetadata(ctx, req.(*UpdateMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_ListMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).ListMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/ListMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).ListMetadata(ctx, req.(*ListMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_SearchMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).SearchMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/SearchMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).SearchMetadata(ctx, req.(*SearchMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_DeleteMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).DeleteMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/DeleteMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).DeleteMetadata(ctx, req.(*DeleteMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_UndeleteMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).UndeleteMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/UndeleteMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).UndeleteMetadata(ctx, req.(*UndeleteMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_ListMetadataRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMetadataRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).ListMetadataRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/ListMetadataRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).ListMetadataRevisions(ctx, req.(*ListMetadataRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_RevertMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).RevertMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/RevertMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).RevertMetadata(ctx, req.(*RevertMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_ImportMetadata_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MetadataServiceServer).ImportMetadata(&metadataServiceImportMetadataServer{stream})
+}
+
+type MetadataService_ImportMetadataServer interface {
+	SendAndClose(*ImportMetadataResponse) error
+	Recv() (*ImportMetadataRequest, error)
+	grpc.ServerStream
+}
+
+type metadataServiceImportMetadataServer struct {
+	grpc.ServerStream
+}
+
+func (x *metadataServiceImportMetadataServer) SendAndClose(m *ImportMetadataResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *metadataServiceImportMetadataServer) Recv() (*ImportMetadataRequest, error) {
+	m := new(ImportMetadataRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _MetadataService_ExportMetadata_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportMetadataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetadataServiceServer).ExportMetadata(m, &metadataServiceExportMetadataServer{stream})
+}
+
+type MetadataService_ExportMetadataServer interface {
+	Send(*ExportMetadataResponse) error
+	grpc.ServerStream
+}
+
+type metadataServiceExportMetadataServer struct {
+	grpc.ServerStream
+}
+
+func (x *metadataServiceExportMetadataServer) Send(m *ExportMetadataResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MetadataService_UploadImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MetadataServiceServer).UploadImage(&metadataServiceUploadImageServer{stream})
+}
+
+type MetadataService_UploadImageServer interface {
+	SendAndClose(*UploadImageResponse) error
+	Recv() (*UploadImageRequest, error)
+	grpc.ServerStream
+}
+
+type metadataServiceUploadImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *metadataServiceUploadImageServer) SendAndClose(m *UploadImageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *metadataServiceUploadImageServer) Recv() (*UploadImageRequest, error) {
+	m := new(UploadImageRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _MetadataService_PutMetadataTranslation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutMetadataTranslationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).PutMetadataTranslation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/PutMetadataTranslation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).PutMetadataTranslation(ctx, req.(*PutMetadataTranslationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_DeleteMetadataTranslation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMetadataTranslationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).DeleteMetadataTranslation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/DeleteMetadataTranslation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).DeleteMetadataTranslation(ctx, req.(*DeleteMetadataTranslationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_ListMetadataTranslations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMetadataTranslationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).ListMetadataTranslations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/ListMetadataTranslations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).ListMetadataTranslations(ctx, req.(*ListMetadataTranslationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_LookupMetadataByExternalID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupMetadataByExternalIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).LookupMetadataByExternalID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/LookupMetadataByExternalID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).LookupMetadataByExternalID(ctx, req.(*LookupMetadataByExternalIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_PutMetadataRelation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutMetadataRelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).PutMetadataRelation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/PutMetadataRelation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).PutMetadataRelation(ctx, req.(*PutMetadataRelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_DeleteMetadataRelation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMetadataRelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).DeleteMetadataRelation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/DeleteMetadataRelation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).DeleteMetadataRelation(ctx, req.(*DeleteMetadataRelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_GetRelatedMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelatedMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).GetRelatedMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MetadataService/GetRelatedMovies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).GetRelatedMovies(ctx, req.(*GetRelatedMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MetadataService_ServiceDesc is the grpc.ServiceDesc for MetadataService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MetadataService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "MetadataService",
+	HandlerType: (*MetadataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMetadata",
+			Handler:    _MetadataService_GetMetadata_Handler,
+		},
+		{
+			MethodName: "BatchGetMetadata",
+			Handler:    _MetadataService_BatchGetMetadata_Handler,
+		},
+		{
+			MethodName: "PutMetadata",
+			Handler:    _MetadataService_PutMetadata_Handler,
+		},
+		{
+			MethodName: "UpdateMetadata",
+			Handler:    _MetadataService_UpdateMetadata_Handler,
+		},
+		{
+			MethodName: "ListMetadata",
+			Handler:    _MetadataService_ListMetadata_Handler,
+		},
+		{
+			MethodName: "SearchMetadata",
+			Handler:    _MetadataService_SearchMetadata_Handler,
+		},
+		{
+			MethodName: "DeleteMetadata",
+			Handler:    _MetadataService_DeleteMetadata_Handler,
+		},
+		{
+			MethodName: "UndeleteMetadata",
+			Handler:    _MetadataService_UndeleteMetadata_Handler,
+		},
+		{
+			MethodName: "ListMetadataRevisions",
+			Handler:    _MetadataService_ListMetadataRevisions_Handler,
+		},
+		{
+			MethodName: "RevertMetadata",
+			Handler:    _MetadataService_RevertMetadata_Handler,
+		},
+		{
+			MethodName: "PutMetadataTranslation",
+			Handler:    _MetadataService_PutMetadataTranslation_Handler,
+		},
+		{
+			MethodName: "DeleteMetadataTranslation",
+			Handler:    _MetadataService_DeleteMetadataTranslation_Handler,
+		},
+		{
+			MethodName: "ListMetadataTranslations",
+			Handler:    _MetadataService_ListMetadataTranslations_Handler,
+		},
+		{
+			MethodName: "LookupMetadataByExternalID",
+			Handler:    _MetadataService_LookupMetadataByExternalID_Handler,
+		},
+		{
+			MethodName: "PutMetadataRelation",
+			Handler:    _MetadataService_PutMetadataRelation_Handler,
+		},
+		{
+			MethodName: "DeleteMetadataRelation",
+			Handler:    _MetadataService_DeleteMetadataRelation_Handler,
+		},
+		{
+			MethodName: "GetRelatedMovies",
+			Handler:    _MetadataService_GetRelatedMovies_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportMetadata",
+			Handler:       _MetadataService_ImportMetadata_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportMetadata",
+			Handler:       _MetadataService_ExportMetadata_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadImage",
+			Handler:       _MetadataService_UploadImage_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "movie.proto",
+}
+
+// RatingServiceClient is the client API for RatingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RatingServiceClient interface {
+	GetAggregatedRating(ctx context.Context, in *GetAggregatedRatingRequest, opts ...grpc.CallOption) (*GetAggregatedRatingResponse, error)
+	BatchGetAggregatedRatings(ctx context.Context, in *BatchGetAggregatedRatingsRequest, opts ...grpc.CallOption) (*BatchGetAggregatedRatingsResponse, error)
+	PutRating(ctx context.Context, in *PutRatingRequest, opts ...grpc.CallOption) (*PutRatingResponse, error)
+	GetUserRating(ctx context.Context, in *GetUserRatingRequest, opts ...grpc.CallOption) (*GetUserRatingResponse, error)
+	DeleteRating(ctx context.Context, in *DeleteRatingRequest, opts ...grpc.CallOption) (*DeleteRatingResponse, error)
+	ListTopRated(ctx context.Context, in *ListTopRatedRequest, opts ...grpc.CallOption) (*ListTopRatedResponse, error)
+	ListTrending(ctx context.Context, in *ListTrendingRequest, opts ...grpc.CallOption) (*ListTrendingResponse, error)
+	ListUserRatings(ctx context.Context, in *ListUserRatingsRequest, opts ...grpc.CallOption) (*ListUserRatingsResponse, error)
+	PutReview(ctx context.Context, in *PutReviewRequest, opts ...grpc.CallOption) (*PutReviewResponse, error)
+	ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (*ListReviewsResponse, error)
+	VoteReview(ctx context.Context, in *VoteReviewRequest, opts ...grpc.CallOption) (*VoteReviewResponse, error)
+	// ModerateReview requires admin access.
+	ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*ModerateReviewResponse, error)
+	GetRatingScale(ctx context.Context, in *GetRatingScaleRequest, opts ...grpc.CallOption) (*GetRatingScaleResponse, error)
+}
+
+type ratingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRatingServiceClient(cc grpc.ClientConnInterface) RatingServiceClient {
+	return &ratingServiceClient{cc}
+}
+
+func (c *ratingServiceClient) GetAggregatedRating(ctx context.Context, in *GetAggregatedRatingRequest, opts ...grpc.CallOption) (*GetAggregatedRatingResponse, error) {
+	out := new(GetAggregatedRatingResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/GetAggregatedRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) BatchGetAggregatedRatings(ctx context.Context, in *BatchGetAggregatedRatingsRequest, opts ...grpc.CallOption) (*BatchGetAggregatedRatingsResponse, error) {
+	out := new(BatchGetAggregatedRatingsResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/BatchGetAggregatedRatings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) PutRating(ctx context.Context, in *PutRatingRequest, opts ...grpc.CallOption) (*PutRatingResponse, error) {
+	out := new(PutRatingResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/PutRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) GetUserRating(ctx context.Context, in *GetUserRatingRequest, opts ...grpc.CallOption) (*GetUserRatingResponse, error) {
+	out := new(GetUserRatingResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/GetUserRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) DeleteRating(ctx context.Context, in *DeleteRatingRequest, opts ...grpc.CallOption) (*DeleteRatingResponse, error) {
+	out := new(DeleteRatingResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/DeleteRating", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) ListTopRated(ctx context.Context, in *ListTopRatedRequest, opts ...grpc.CallOption) (*ListTopRatedResponse, error) {
+	out := new(ListTopRatedResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/ListTopRated", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// MetadataService_ServiceDesc is the grpc.ServiceDesc for MetadataService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var MetadataService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "MetadataService",
-	HandlerType: (*MetadataServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetMetadata",
-			Handler:    _MetadataService_GetMetadata_Handler,
-		},
-		{
-			MethodName: "PutMetadata",
-			Handler:    _MetadataService_PutMetadata_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "movie.proto",
+func (c *ratingServiceClient) ListTrending(ctx context.Context, in *ListTrendingRequest, opts ...grpc.CallOption) (*ListTrendingResponse, error) {
+	out := new(ListTrendingResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/ListTrending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// RatingServiceClient is the client API for RatingService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-type RatingServiceClient interface {
-	GetAggregatedRating(ctx context.Context, in *GetAggregatedRatingRequest, opts ...grpc.CallOption) (*GetAggregatedRatingResponse, error)
-	PutRating(ctx context.Context, in *PutRatingRequest, opts ...grpc.CallOption) (*PutRatingResponse, error)
+func (c *ratingServiceClient) ListUserRatings(ctx context.Context, in *ListUserRatingsRequest, opts ...grpc.CallOption) (*ListUserRatingsResponse, error) {
+	out := new(ListUserRatingsResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/ListUserRatings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type ratingServiceClient struct {
-	cc grpc.ClientConnInterface
+func (c *ratingServiceClient) PutReview(ctx context.Context, in *PutReviewRequest, opts ...grpc.CallOption) (*PutReviewResponse, error) {
+	out := new(PutReviewResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/PutReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func NewRatingServiceClient(cc grpc.ClientConnInterface) RatingServiceClient {
-	return &ratingServiceClient{cc}
+func (c *ratingServiceClient) ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (*ListReviewsResponse, error) {
+	out := new(ListReviewsResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/ListReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *ratingServiceClient) GetAggregatedRating(ctx context.Context, in *GetAggregatedRatingRequest, opts ...grpc.CallOption) (*GetAggregatedRatingResponse, error) {
-	out := new(GetAggregatedRatingResponse)
-	err := c.cc.Invoke(ctx, "/RatingService/GetAggregatedRating", in, out, opts...)
+func (c *ratingServiceClient) VoteReview(ctx context.Context, in *VoteReviewRequest, opts ...grpc.CallOption) (*VoteReviewResponse, error) {
+	out := new(VoteReviewResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/VoteReview", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ratingServiceClient) PutRating(ctx context.Context, in *PutRatingRequest, opts ...grpc.CallOption) (*PutRatingResponse, error) {
-	out := new(PutRatingResponse)
-	err := c.cc.Invoke(ctx, "/RatingService/PutRating", in, out, opts...)
+func (c *ratingServiceClient) ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*ModerateReviewResponse, error) {
+	out := new(ModerateReviewResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/ModerateReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) GetRatingScale(ctx context.Context, in *GetRatingScaleRequest, opts ...grpc.CallOption) (*GetRatingScaleResponse, error) {
+	out := new(GetRatingScaleResponse)
+	err := c.cc.Invoke(ctx, "/RatingService/GetRatingScale", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -179,7 +1034,19 @@ func (c *ratingServiceClient) PutRating(ctx context.Context, in *PutRatingReques
 // for forward compatibility
 type RatingServiceServer interface {
 	GetAggregatedRating(context.Context, *GetAggregatedRatingRequest) (*GetAggregatedRatingResponse, error)
+	BatchGetAggregatedRatings(context.Context, *BatchGetAggregatedRatingsRequest) (*BatchGetAggregatedRatingsResponse, error)
 	PutRating(context.Context, *PutRatingRequest) (*PutRatingResponse, error)
+	GetUserRating(context.Context, *GetUserRatingRequest) (*GetUserRatingResponse, error)
+	DeleteRating(context.Context, *DeleteRatingRequest) (*DeleteRatingResponse, error)
+	ListTopRated(context.Context, *ListTopRatedRequest) (*ListTopRatedResponse, error)
+	ListTrending(context.Context, *ListTrendingRequest) (*ListTrendingResponse, error)
+	ListUserRatings(context.Context, *ListUserRatingsRequest) (*ListUserRatingsResponse, error)
+	PutReview(context.Context, *PutReviewRequest) (*PutReviewResponse, error)
+	ListReviews(context.Context, *ListReviewsRequest) (*ListReviewsResponse, error)
+	VoteReview(context.Context, *VoteReviewRequest) (*VoteReviewResponse, error)
+	// ModerateReview requires admin access.
+	ModerateReview(context.Context, *ModerateReviewRequest) (*ModerateReviewResponse, error)
+	GetRatingScale(context.Context, *GetRatingScaleRequest) (*GetRatingScaleResponse, error)
 	mustEmbedUnimplementedRatingServiceServer()
 }
 
@@ -190,9 +1057,42 @@ type UnimplementedRatingServiceServer struct {
 func (UnimplementedRatingServiceServer) GetAggregatedRating(context.Context, *GetAggregatedRatingRequest) (*GetAggregatedRatingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAggregatedRating not implemented")
 }
+func (UnimplementedRatingServiceServer) BatchGetAggregatedRatings(context.Context, *BatchGetAggregatedRatingsRequest) (*BatchGetAggregatedRatingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetAggregatedRatings not implemented")
+}
 func (UnimplementedRatingServiceServer) PutRating(context.Context, *PutRatingRequest) (*PutRatingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutRating not implemented")
 }
+func (UnimplementedRatingServiceServer) GetUserRating(context.Context, *GetUserRatingRequest) (*GetUserRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserRating not implemented")
+}
+func (UnimplementedRatingServiceServer) DeleteRating(context.Context, *DeleteRatingRequest) (*DeleteRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRating not implemented")
+}
+func (UnimplementedRatingServiceServer) ListTopRated(context.Context, *ListTopRatedRequest) (*ListTopRatedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTopRated not implemented")
+}
+func (UnimplementedRatingServiceServer) ListTrending(context.Context, *ListTrendingRequest) (*ListTrendingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrending not implemented")
+}
+func (UnimplementedRatingServiceServer) ListUserRatings(context.Context, *ListUserRatingsRequest) (*ListUserRatingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserRatings not implemented")
+}
+func (UnimplementedRatingServiceServer) PutReview(context.Context, *PutReviewRequest) (*PutReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutReview not implemented")
+}
+func (UnimplementedRatingServiceServer) ListReviews(context.Context, *ListReviewsRequest) (*ListReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviews not implemented")
+}
+func (UnimplementedRatingServiceServer) VoteReview(context.Context, *VoteReviewRequest) (*VoteReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteReview not implemented")
+}
+func (UnimplementedRatingServiceServer) ModerateReview(context.Context, *ModerateReviewRequest) (*ModerateReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedRatingServiceServer) GetRatingScale(context.Context, *GetRatingScaleRequest) (*GetRatingScaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRatingScale not implemented")
+}
 func (UnimplementedRatingServiceServer) mustEmbedUnimplementedRatingServiceServer() {}
 
 // UnsafeRatingServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -224,6 +1124,24 @@ func _RatingService_GetAggregatedRating_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RatingService_BatchGetAggregatedRatings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetAggregatedRatingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).BatchGetAggregatedRatings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/BatchGetAggregatedRatings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).BatchGetAggregatedRatings(ctx, req.(*BatchGetAggregatedRatingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RatingService_PutRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PutRatingRequest)
 	if err := dec(in); err != nil {
@@ -242,6 +1160,186 @@ func _RatingService_PutRating_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RatingService_GetUserRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).GetUserRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/GetUserRating",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).GetUserRating(ctx, req.(*GetUserRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_DeleteRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).DeleteRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/DeleteRating",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).DeleteRating(ctx, req.(*DeleteRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ListTopRated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTopRatedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).ListTopRated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/ListTopRated",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).ListTopRated(ctx, req.(*ListTopRatedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ListTrending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).ListTrending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/ListTrending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).ListTrending(ctx, req.(*ListTrendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ListUserRatings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserRatingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).ListUserRatings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/ListUserRatings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).ListUserRatings(ctx, req.(*ListUserRatingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_PutReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).PutReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/PutReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).PutReview(ctx, req.(*PutReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ListReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).ListReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/ListReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).ListReviews(ctx, req.(*ListReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_VoteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).VoteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/VoteReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).VoteReview(ctx, req.(*VoteReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/ModerateReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).ModerateReview(ctx, req.(*ModerateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_GetRatingScale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRatingScaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).GetRatingScale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RatingService/GetRatingScale",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).GetRatingScale(ctx, req.(*GetRatingScaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RatingService_ServiceDesc is the grpc.ServiceDesc for RatingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -253,10 +1351,54 @@ var RatingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAggregatedRating",
 			Handler:    _RatingService_GetAggregatedRating_Handler,
 		},
+		{
+			MethodName: "BatchGetAggregatedRatings",
+			Handler:    _RatingService_BatchGetAggregatedRatings_Handler,
+		},
 		{
 			MethodName: "PutRating",
 			Handler:    _RatingService_PutRating_Handler,
 		},
+		{
+			MethodName: "GetUserRating",
+			Handler:    _RatingService_GetUserRating_Handler,
+		},
+		{
+			MethodName: "DeleteRating",
+			Handler:    _RatingService_DeleteRating_Handler,
+		},
+		{
+			MethodName: "ListTopRated",
+			Handler:    _RatingService_ListTopRated_Handler,
+		},
+		{
+			MethodName: "ListTrending",
+			Handler:    _RatingService_ListTrending_Handler,
+		},
+		{
+			MethodName: "ListUserRatings",
+			Handler:    _RatingService_ListUserRatings_Handler,
+		},
+		{
+			MethodName: "PutReview",
+			Handler:    _RatingService_PutReview_Handler,
+		},
+		{
+			MethodName: "ListReviews",
+			Handler:    _RatingService_ListReviews_Handler,
+		},
+		{
+			MethodName: "VoteReview",
+			Handler:    _RatingService_VoteReview_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _RatingService_ModerateReview_Handler,
+		},
+		{
+			MethodName: "GetRatingScale",
+			Handler:    _RatingService_GetRatingScale_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "movie.proto",
@@ -267,6 +1409,7 @@ var RatingService_ServiceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MovieServiceClient interface {
 	GetMovieDetails(ctx context.Context, in *GetMovieDetailsRequest, opts ...grpc.CallOption) (*GetMovieDetailsResponse, error)
+	GetMovieLeaderboard(ctx context.Context, in *GetMovieLeaderboardRequest, opts ...grpc.CallOption) (*GetMovieLeaderboardResponse, error)
 }
 
 type movieServiceClient struct {
@@ -286,11 +1429,21 @@ func (c *movieServiceClient) GetMovieDetails(ctx context.Context, in *GetMovieDe
 	return out, nil
 }
 
+func (c *movieServiceClient) GetMovieLeaderboard(ctx context.Context, in *GetMovieLeaderboardRequest, opts ...grpc.CallOption) (*GetMovieLeaderboardResponse, error) {
+	out := new(GetMovieLeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/MovieService/GetMovieLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MovieServiceServer is the server API for MovieService service.
 // All implementations must embed UnimplementedMovieServiceServer
 // for forward compatibility
 type MovieServiceServer interface {
 	GetMovieDetails(context.Context, *GetMovieDetailsRequest) (*GetMovieDetailsResponse, error)
+	GetMovieLeaderboard(context.Context, *GetMovieLeaderboardRequest) (*GetMovieLeaderboardResponse, error)
 	mustEmbedUnimplementedMovieServiceServer()
 }
 
@@ -301,6 +1454,9 @@ type UnimplementedMovieServiceServer struct {
 func (UnimplementedMovieServiceServer) GetMovieDetails(context.Context, *GetMovieDetailsRequest) (*GetMovieDetailsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMovieDetails not implemented")
 }
+func (UnimplementedMovieServiceServer) GetMovieLeaderboard(context.Context, *GetMovieLeaderboardRequest) (*GetMovieLeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMovieLeaderboard not implemented")
+}
 func (UnimplementedMovieServiceServer) mustEmbedUnimplementedMovieServiceServer() {}
 
 // UnsafeMovieServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -332,6 +1488,24 @@ func _MovieService_GetMovieDetails_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MovieService_GetMovieLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMovieLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).GetMovieLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/MovieService/GetMovieLeaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).GetMovieLeaderboard(ctx, req.(*GetMovieLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MovieService_ServiceDesc is the grpc.ServiceDesc for MovieService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -343,6 +1517,10 @@ var MovieService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMovieDetails",
 			Handler:    _MovieService_GetMovieDetails_Handler,
 		},
+		{
+			MethodName: "GetMovieLeaderboard",
+			Handler:    _MovieService_GetMovieLeaderboard_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "movie.proto",
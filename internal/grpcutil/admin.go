@@ -0,0 +1,39 @@
+package grpcutil
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"movieexample.com/pkg/utilities"
+)
+
+// AdminTokenHeader is the request metadata key carrying the admin token.
+const AdminTokenHeader = "x-admin-token"
+
+// AdminUnaryInterceptor marks requests that carry the given admin token as coming from an
+// administrator, see utilities.IsAdmin. An empty token disables admin access altogether.
+func AdminUnaryInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if token != "" && hasToken(ctx, token) {
+			ctx = utilities.WithAdmin(ctx)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func hasToken(ctx context.Context, token string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, v := range md.Get(AdminTokenHeader) {
+		if subtle.ConstantTimeCompare([]byte(v), []byte(token)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
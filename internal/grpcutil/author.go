@@ -0,0 +1,26 @@
+package grpcutil
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"movieexample.com/pkg/utilities"
+)
+
+// AuthorHeader is the request metadata key naming who makes the changes of a request.
+const AuthorHeader = "x-author"
+
+// AuthorUnaryInterceptor records the author sent in the request metadata on the context,
+// see utilities.AuthorFromContext.
+func AuthorUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(AuthorHeader); len(v) > 0 {
+				ctx = utilities.WithAuthor(ctx, v[0])
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
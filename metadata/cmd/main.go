@@ -18,9 +18,12 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"movieexample.com/gen"
+	"movieexample.com/internal/grpcutil"
 	config "movieexample.com/metadata/configs"
+	"movieexample.com/metadata/internal/blob/local"
 	"movieexample.com/metadata/internal/controller/metadata"
 	grpchandler "movieexample.com/metadata/internal/handler/grpc"
+	httphandler "movieexample.com/metadata/internal/handler/http"
 	memoryRepo "movieexample.com/metadata/internal/repository/memory"
 	"movieexample.com/metadata/internal/repository/postgres"
 	"movieexample.com/pkg/discovery"
@@ -110,6 +113,12 @@ func main() {
 
 	var repo metadata.Repository
 
+	images, err := local.New(cfg.Images.Dir, cfg.Images.BaseURL)
+	if err != nil {
+		logger.Fatal("Failed to initialize image storage", zap.Error(err))
+	}
+	var ctrl *metadata.Controller
+
 	var wg sync.WaitGroup
 	var srv *grpc.Server
 	stop := make(chan os.Signal, 1)
@@ -145,7 +154,7 @@ func main() {
 		}
 
 		// setting up repository
-		ctrl := metadata.New(repo)
+		ctrl = metadata.New(repo, metadata.WithImageStore(images))
 		h := grpchandler.New(ctrl)
 
 		lis, err := net.Listen("tcp", fmt.Sprintf("%s:%v", cfg.Host, cfg.GRPC.Port))
@@ -158,6 +167,10 @@ func main() {
 				otelgrpc.WithPropagators(propagation.TraceContext{}),
 				otelgrpc.WithTracerProvider(tp),
 			)),
+			grpc.ChainUnaryInterceptor(
+				grpcutil.AdminUnaryInterceptor(cfg.Admin.Token),
+				grpcutil.AuthorUnaryInterceptor(),
+			),
 		)
 		// grpc.NewServer(grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor()))
 		reflection.Register(srv)
@@ -182,6 +195,8 @@ func main() {
 	httpServ.HandleFunc("/live", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
+	httpServ.HandleFunc("POST /metadata/images", httphandler.New(ctrl).UploadImage)
+	httpServ.Handle("GET /images/", http.StripPrefix("/images/", images.Handler()))
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.API.Port),
@@ -8,6 +8,8 @@ type Config struct {
 	GRPC       *GRPCConfig       `yaml:"grpc"`
 	Host       string            `yaml:"host"`
 	Postgres   *PostgresConfig   `yaml:"mysql"`
+	Admin      *AdminConfig      `yaml:"admin"`
+	Images     *ImagesConfig     `yaml:"images"`
 }
 
 type APIConfig struct {
@@ -39,3 +41,14 @@ type PostgresConfig struct {
 	Database string `yaml:"database"`
 	SslMode  string `yaml:"sslmode"`
 }
+
+type AdminConfig struct {
+	Token string `yaml:"token"`
+}
+
+// ImagesConfig configures the local storage of uploaded images. Dir is where the files are kept
+// and BaseURL the address they are served at.
+type ImagesConfig struct {
+	Dir     string `yaml:"dir"`
+	BaseURL string `yaml:"baseURL"`
+}
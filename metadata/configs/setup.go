@@ -35,6 +35,9 @@ func SetUpConfig() (*Config, error) {
 	postgresPassword := viperConfig.GetString("POSTGRES_PASSWORD")
 	postgresDatabase := viperConfig.GetString("POSTGRES_DATABASE")
 	postgresSslMode := viperConfig.GetString("POSTGRES_SSL_MODE")
+	adminToken := viperConfig.GetString("ADMIN_TOKEN")
+	imageDir := viperConfig.GetString("IMAGE_DIR")
+	imageBaseURL := viperConfig.GetString("IMAGE_BASE_URL")
 
 	cfg.API = &APIConfig{
 		Host: host,
@@ -59,6 +62,19 @@ func SetUpConfig() (*Config, error) {
 		Database: postgresDatabase,
 		SslMode:  postgresSslMode,
 	}
+	cfg.Admin = &AdminConfig{
+		Token: adminToken,
+	}
+	if imageDir == "" {
+		imageDir = "images"
+	}
+	if imageBaseURL == "" {
+		imageBaseURL = fmt.Sprintf("http://%s:%d/images", host, httpPort)
+	}
+	cfg.Images = &ImagesConfig{
+		Dir:     imageDir,
+		BaseURL: imageBaseURL,
+	}
 
 	return cfg, nil
 }
@@ -0,0 +1,99 @@
+// Package local stores blobs as files in a local directory.
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidKey is returned for keys that are not clean, relative slash separated paths.
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Store keeps every blob in a file named after its key below a root directory. The files are
+// served by Handler under the base URL.
+type Store struct {
+	dir     string
+	baseURL string
+}
+
+// New returns a store rooted at dir, creating the directory if needed. URLs of blobs are formed
+// by appending their key to baseURL.
+func New(dir, baseURL string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// Put writes a blob. The file is written under a temporary name and renamed into place, so
+// readers never see a partial file.
+func (s *Store) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Delete removes a blob. Deleting a missing blob is not an error.
+func (s *Store) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// URL returns the address a blob is served at.
+func (s *Store) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Handler serves the blobs by key. It is meant to be mounted under the path of the base URL
+// with http.StripPrefix.
+func (s *Store) Handler() http.Handler {
+	return http.FileServer(http.Dir(s.dir))
+}
+
+func (s *Store) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
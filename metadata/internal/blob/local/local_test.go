@@ -0,0 +1,43 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := New(dir, "http://localhost/images/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put(ctx, "movies/1/a.jpg", strings.NewReader("data"), 4, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "movies", "1", "a.jpg"))
+	if err != nil || string(b) != "data" {
+		t.Fatalf("ReadFile() = %q, %v", b, err)
+	}
+	if got, want := s.URL("movies/1/a.jpg"), "http://localhost/images/movies/1/a.jpg"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+
+	if err := s.Delete(ctx, "movies/1/a.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "movies/1/a.jpg"); err != nil {
+		t.Errorf("Delete() of a missing blob = %v", err)
+	}
+
+	for _, key := range []string{"", ".", "../escape", "/abs", "movies//a", "movies/../../a"} {
+		if err := s.Put(ctx, key, strings.NewReader("data"), 4, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package s3 stores blobs in a bucket of an S3 compatible object store.
+package s3
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// Client is the part of an S3 client the store needs. It is small enough to be implemented
+// with a few lines over the AWS SDK, MinIO or any other S3 compatible client, which keeps
+// those dependencies out of the service.
+type Client interface {
+	// PutObject uploads an object of the given size.
+	PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error
+	// DeleteObject removes an object. Removing a missing object is not an error.
+	DeleteObject(ctx context.Context, bucket, key string) error
+}
+
+// Store keeps every blob as an object of a single bucket, optionally below a key prefix.
+type Store struct {
+	client  Client
+	bucket  string
+	prefix  string
+	baseURL string
+}
+
+// New returns a store writing to bucket through client. Object keys are the blob keys with
+// prefix prepended, and URLs are formed by appending the object key to baseURL, which is
+// typically the public endpoint of the bucket or of a CDN in front of it.
+func New(client Client, bucket, prefix, baseURL string) *Store {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &Store{
+		client:  client,
+		bucket:  bucket,
+		prefix:  prefix,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Put uploads a blob.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return s.client.PutObject(ctx, s.bucket, s.prefix+key, r, size, contentType)
+}
+
+// Delete removes a blob.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.client.DeleteObject(ctx, s.bucket, s.prefix+key)
+}
+
+// URL returns the public address of a blob.
+func (s *Store) URL(key string) string {
+	return s.baseURL + "/" + s.prefix + key
+}
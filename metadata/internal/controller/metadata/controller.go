@@ -3,6 +3,8 @@ package metadata
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"movieexample.com/metadata/internal/repository"
@@ -11,28 +13,137 @@ import (
 
 var ErrNotFound = errors.New("not found")
 
+// ErrInvalidPageSize is returned when a negative page size is requested.
+var ErrInvalidPageSize = errors.New("invalid page size")
+
+// ErrVersionMismatch is returned when a conditional put finds a different version than expected.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// ErrEmptyQuery is returned when a search query has no terms.
+var ErrEmptyQuery = errors.New("empty search query")
+
+// ErrBatchTooLarge is returned when more than MaxBatchSize records are read at once.
+var ErrBatchTooLarge = errors.New("batch too large")
+
+const (
+	// DefaultPageSize is used when a listing does not specify a page size.
+	DefaultPageSize = 20
+	// MaxPageSize caps the number of records returned in a single page.
+	MaxPageSize = 100
+	// MaxExportBatchSize caps the number of records read at once during an export.
+	MaxExportBatchSize = 1000
+	// MaxBatchSize caps the number of records read by a single GetMany.
+	MaxBatchSize = 1000
+)
+
 // metadataRepository defines the interface for interacting with the metadata repository.
 // The Get method retrieves a metadata record by its ID.
+// Every write made by Put, CompareAndPut, PutMany and Update is recorded as an immutable revision
+// attributed to utilities.AuthorFromContext(ctx), in the same transaction as the write.
+// Writes replace the external IDs of a record and return repository.ErrExternalIDConflict if one
+// of them belongs to another record. Writes that may change the title or release date also assign
+// the slug of the record in the same transaction, see repository.AssignSlug, keeping its former
+// slugs as redirects; Put, CompareAndPut and PutMany write it back to Metadata.Slug. Reads return
+// the current slug in Metadata.Slug.
 type Repository interface {
 	// Get retrieves a metadata record by its ID.
 	// The context parameter is used to control the lifetime of the request.
 	// The id parameter is the unique identifier of the metadata record to retrieve.
 	// It returns the metadata record and an error if the record is not found or there is another error.
 	Get(ctx context.Context, id string) (*model.Metadata, error)
+	// GetMany returns the live records among ids, ordered by ID. Unknown and deleted IDs are skipped.
+	GetMany(ctx context.Context, ids []string) ([]*model.Metadata, error)
+	// Put upserts a record, bumping its version and writing the new version back to m.Version.
 	Put(ctx context.Context, id string, m *model.Metadata) error
+	// CompareAndPut is like Put but only writes if the stored version equals expectedVersion,
+	// where 0 means the record must not exist. Otherwise it returns repository.ErrVersionMismatch.
+	CompareAndPut(ctx context.Context, id string, m *model.Metadata, expectedVersion int64) error
+	// PutMany upserts a batch of records with unique IDs in one go, as Put would one by one.
+	// Either all records are written or none is. The new versions are written back to the records.
+	PutMany(ctx context.Context, ms []*model.Metadata) error
+	// Update copies the given fields from patch to the stored record, bumping its version, and
+	// returns the updated record. Fields that are not listed are left untouched. A non-zero
+	// expectedVersion makes the update conditional, as in CompareAndPut.
+	// It returns repository.ErrNotFound if there is no live record with the given ID.
+	Update(ctx context.Context, id string, patch *model.Metadata, fields []model.Field, expectedVersion int64) (*model.Metadata, error)
+	// List returns at most limit records matching filter, ordered by sort and skipping the first offset records.
+	// The order must be stable so that consecutive pages neither repeat nor skip records.
+	List(ctx context.Context, filter model.Filter, sort model.SortOrder, offset, limit int) ([]*model.Metadata, error)
+	// ListAfter returns at most limit live records whose ID sorts after afterID, ordered by ID.
+	// It is used to walk the whole catalog with constant memory.
+	ListAfter(ctx context.Context, afterID string, limit int) ([]*model.Metadata, error)
+	// Search returns at most limit records matching the full-text query, best match first,
+	// skipping the first offset results.
+	Search(ctx context.Context, query string, offset, limit int) ([]model.SearchResult, error)
+	// Delete soft deletes a record, hiding it from Get, List and Search until it is undeleted.
+	// It returns repository.ErrNotFound if there is no live record with the given ID.
+	Delete(ctx context.Context, id string) error
+	// Undelete restores a soft deleted record.
+	// It returns repository.ErrNotFound if there is no deleted record with the given ID.
+	Undelete(ctx context.Context, id string) error
+	// Purge permanently removes a record, whether it was soft deleted or not.
+	Purge(ctx context.Context, id string) error
+	// GetAsOf returns the record as written by its last revision made at or before asOf.
+	// It returns repository.ErrNotFound if the record had no revision by then.
+	GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.Metadata, error)
+	// GetRevision returns the revision of a record that produced the given version.
+	// It returns repository.ErrNotFound if there is no such revision.
+	GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error)
+	// ListRevisions returns at most limit revisions of a record, newest first, skipping the first offset ones.
+	ListRevisions(ctx context.Context, id string, offset, limit int) ([]*model.Revision, error)
+	// PutImage records an uploaded image of a movie and sets img.CreatedAt.
+	// It returns repository.ErrNotFound if the movie does not exist.
+	PutImage(ctx context.Context, img *model.Image) error
+	// ListImages returns the images of a movie, oldest first.
+	ListImages(ctx context.Context, movieID string) ([]model.Image, error)
+	// PutTranslation adds or replaces the translation of a movie into t.Locale.
+	// It returns repository.ErrNotFound if the movie does not exist.
+	PutTranslation(ctx context.Context, movieID string, t model.Translation) error
+	// DeleteTranslation removes the translation of a movie into locale.
+	// It returns repository.ErrNotFound if there is no such translation.
+	DeleteTranslation(ctx context.Context, movieID, locale string) error
+	// ListTranslations returns the translations of a movie ordered by locale.
+	ListTranslations(ctx context.Context, movieID string) ([]model.Translation, error)
+	// ListTranslationsForMovies returns the translations of each of the movies that has any,
+	// keyed by movie ID and ordered by locale.
+	ListTranslationsForMovies(ctx context.Context, movieIDs []string) (map[string][]model.Translation, error)
+	// GetByExternalID retrieves the live record that has the given external ID.
+	// It returns repository.ErrNotFound if there is none.
+	GetByExternalID(ctx context.Context, id model.ExternalID) (*model.Metadata, error)
+	// ResolveSlug returns the ID of the record that has slug as its current or a former slug.
+	// It returns repository.ErrNotFound if there is none.
+	ResolveSlug(ctx context.Context, slug string) (string, error)
+	// PutRelation links two records. Putting a relation that already exists is a no-op.
+	// It returns repository.ErrNotFound if either record does not exist and
+	// repository.ErrRelationCycle if the related record already leads back to the record through
+	// relations of the same type. The check and the write must be atomic.
+	PutRelation(ctx context.Context, rel model.Relation) error
+	// DeleteRelation removes a link between two records.
+	// It returns repository.ErrNotFound if there is no such relation.
+	DeleteRelation(ctx context.Context, rel model.Relation) error
+	// ListRelations returns the relations from and to a record, including those to soft deleted
+	// records, ordered by type, movie ID and related ID.
+	ListRelations(ctx context.Context, id string) ([]model.Relation, error)
 }
 
 // Controller is a struct that holds a metadataRepository, which is used to interact with the metadata repository.
 type Controller struct {
 	repo Repository
+	// images stores the files of uploaded images; nil disables uploads.
+	images BlobStore
 }
 
 // New creates a new instance of the Controller struct, which holds a metadataRepository
 // that is used to interact with the metadata repository.
-func New(repo Repository) *Controller {
-	return &Controller{
+func New(repo Repository, opts ...Option) *Controller {
+	c := &Controller{
 		repo: repo,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // Get retrieves a metadata record by its ID. The context parameter is used to control the lifetime of the request.
@@ -52,9 +163,330 @@ func (c *Controller) Get(ctx context.Context, id string) (*model.Metadata, error
 	return res, nil
 }
 
+// Put stores the metadata record unconditionally. On success m.Version holds the new version and
+// m.Slug the slug derived from the title and release year, made unique with a numeric suffix.
+// When these change, the former slug is kept as a redirect, see ResolveSlug.
+// The record is trimmed and validated first; an invalid record yields a *ValidationError.
 func (c *Controller) Put(ctx context.Context, m *model.Metadata) error {
 	ctx, span := otel.Tracer("").Start(ctx, "PutController")
 	defer span.End()
 
-	return c.repo.Put(ctx, m.ID, m)
+	n, err := validate(m)
+	if err != nil {
+		return err
+	}
+
+	err = c.repo.Put(ctx, n.ID, n)
+	if err != nil && errors.Is(err, repository.ErrExternalIDConflict) {
+		return ErrExternalIDConflict
+	} else if err != nil {
+		return err
+	}
+	*m = *n
+
+	return nil
+}
+
+// CompareAndPut stores the metadata record only if its stored version equals expectedVersion,
+// where 0 means the record must not exist yet. It returns ErrVersionMismatch if another writer
+// got there first. On success m.Version and m.Slug are set and the record is validated as in Put.
+func (c *Controller) CompareAndPut(ctx context.Context, m *model.Metadata, expectedVersion int64) error {
+	ctx, span := otel.Tracer("").Start(ctx, "CompareAndPutController")
+	defer span.End()
+
+	n, err := validate(m)
+	if err != nil {
+		return err
+	}
+
+	err = c.repo.CompareAndPut(ctx, n.ID, n, expectedVersion)
+	if err != nil && errors.Is(err, repository.ErrVersionMismatch) {
+		return ErrVersionMismatch
+	} else if err != nil && errors.Is(err, repository.ErrExternalIDConflict) {
+		return ErrExternalIDConflict
+	} else if err != nil {
+		return err
+	}
+	*m = *n
+
+	return nil
+}
+
+// GetAsOf retrieves a metadata record as it was at the given time.
+// It returns ErrNotFound if the record did not exist yet.
+func (c *Controller) GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.Metadata, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "GetAsOfController")
+	defer span.End()
+
+	res, err := c.repo.GetAsOf(ctx, id, asOf)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListRevisions returns a page of the revisions of a metadata record, newest first, along with
+// the token of the next page. Paging works the same way as in List.
+func (c *Controller) ListRevisions(ctx context.Context, id string, pageSize int, pageToken string) ([]*model.Revision, string, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "ListRevisionsController")
+	defer span.End()
+
+	pageSize, err := normalizePageSize(pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := revisionsQuery{MovieID: id}
+	offset, err := decodePageToken(pageToken, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := c.repo.ListRevisions(ctx, id, offset, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(res) > pageSize {
+		res = res[:pageSize]
+		next = encodePageToken(offset+pageSize, query)
+	}
+
+	return res, next, nil
+}
+
+// Revert writes the content of an earlier revision back as a new revision and returns the
+// reverted record. A non-zero expectedVersion makes the write conditional on the stored version.
+// It returns ErrNotFound if the revision does not exist.
+func (c *Controller) Revert(ctx context.Context, id string, version int64, expectedVersion int64) (*model.Metadata, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "RevertController")
+	defer span.End()
+
+	rev, err := c.repo.GetRevision(ctx, id, version)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the snapshot may be the one the repository keeps, so write a copy of it
+	m := rev.Snapshot.Clone()
+	m.ID = id
+	if expectedVersion != 0 {
+		err = c.CompareAndPut(ctx, m, expectedVersion)
+	} else {
+		err = c.Put(ctx, m)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Update applies a partial update to a metadata record and returns the updated record.
+// Only the given fields are copied from patch, and only those are validated; an empty field
+// list leaves the record as it is. Updating the title or release date assigns a new slug as Put does.
+// A non-zero expectedVersion makes the update conditional on the stored version.
+func (c *Controller) Update(ctx context.Context, id string, patch *model.Metadata, fields []model.Field, expectedVersion int64) (*model.Metadata, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "UpdateController")
+	defer span.End()
+
+	if len(fields) == 0 {
+		return c.Get(ctx, id)
+	}
+
+	patch, err := validateFields(patch, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.repo.Update(ctx, id, patch, fields, expectedVersion)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil && errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrVersionMismatch
+	} else if err != nil && errors.Is(err, repository.ErrExternalIDConflict) {
+		return nil, ErrExternalIDConflict
+	} else if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// List returns a page of metadata records matching filter in the given sort order, along with
+// the token of the next page. The next page token is empty when there are no more records.
+// A zero page size falls back to DefaultPageSize and sizes above MaxPageSize are capped.
+func (c *Controller) List(ctx context.Context, filter model.Filter, sort model.SortOrder, pageSize int, pageToken string) ([]*model.Metadata, string, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "ListController")
+	defer span.End()
+
+	pageSize, err := normalizePageSize(pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := listQuery{Filter: filter, Sort: sort}
+	offset, err := decodePageToken(pageToken, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// fetch one extra record to find out whether another page follows
+	res, err := c.repo.List(ctx, filter, sort, offset, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(res) > pageSize {
+		res = res[:pageSize]
+		next = encodePageToken(offset+pageSize, query)
+	}
+
+	return res, next, nil
+}
+
+// Export walks every live record in ID order, handing them to fn in batches of batchSize.
+// A zero batch size falls back to DefaultPageSize and sizes above MaxExportBatchSize are capped.
+// Export stops at the first error returned by fn.
+func (c *Controller) Export(ctx context.Context, batchSize int, fn func([]*model.Metadata) error) error {
+	ctx, span := otel.Tracer("").Start(ctx, "ExportController")
+	defer span.End()
+
+	switch {
+	case batchSize < 0:
+		return ErrInvalidPageSize
+	case batchSize == 0:
+		batchSize = DefaultPageSize
+	case batchSize > MaxExportBatchSize:
+		batchSize = MaxExportBatchSize
+	}
+
+	var after string
+	for {
+		res, err := c.repo.ListAfter(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(res) == 0 {
+			return nil
+		}
+		if err := fn(res); err != nil {
+			return err
+		}
+		if len(res) < batchSize {
+			return nil
+		}
+		after = res[len(res)-1].ID
+	}
+}
+
+// Search runs a full-text search over the catalog and returns a page of ranked results,
+// along with the token of the next page. Paging works the same way as in List.
+func (c *Controller) Search(ctx context.Context, query string, pageSize int, pageToken string) ([]model.SearchResult, string, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "SearchController")
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, "", ErrEmptyQuery
+	}
+
+	pageSize, err := normalizePageSize(pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	offset, err := decodePageToken(pageToken, searchQuery{Query: query})
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := c.repo.Search(ctx, query, offset, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(res) > pageSize {
+		res = res[:pageSize]
+		next = encodePageToken(offset+pageSize, searchQuery{Query: query})
+	}
+
+	return res, next, nil
+}
+
+// Delete removes a metadata record. By default the record is soft deleted and can be restored
+// with Undelete; a hard delete removes it permanently, along with the files of its images.
+// It returns ErrNotFound if there is no such record.
+func (c *Controller) Delete(ctx context.Context, id string, hard bool) error {
+	ctx, span := otel.Tracer("").Start(ctx, "DeleteController")
+	defer span.End()
+
+	if !hard {
+		err := c.repo.Delete(ctx, id)
+		if err != nil && errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+
+		return err
+	}
+
+	var keys []string
+	if c.images != nil {
+		imgs, err := c.repo.ListImages(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, img := range imgs {
+			keys = append(keys, img.Key)
+			for _, v := range img.Variants {
+				keys = append(keys, v.Key)
+			}
+		}
+	}
+	err := c.repo.Purge(ctx, id)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	// the files are only removed once the rows pointing at them are gone
+	c.deleteBlobs(ctx, keys)
+
+	return nil
+}
+
+// Undelete restores a soft deleted metadata record. It returns ErrNotFound if the record
+// does not exist, was hard deleted or is not deleted.
+func (c *Controller) Undelete(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer("").Start(ctx, "UndeleteController")
+	defer span.End()
+
+	err := c.repo.Undelete(ctx, id)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// normalizePageSize applies DefaultPageSize and MaxPageSize to a requested page size.
+func normalizePageSize(pageSize int) (int, error) {
+	switch {
+	case pageSize < 0:
+		return 0, ErrInvalidPageSize
+	case pageSize == 0:
+		return DefaultPageSize, nil
+	case pageSize > MaxPageSize:
+		return MaxPageSize, nil
+	default:
+		return pageSize, nil
+	}
 }
@@ -3,6 +3,7 @@ package metadata
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,7 +18,7 @@ import (
 func TestControllerPut(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	repoMock := gen.NewMockmetadataRepository(ctrl)
+	repoMock := gen.NewMockRepository(ctrl)
 	c := New(repoMock)
 	ctx := context.Background()
 	id := "id"
@@ -31,9 +32,13 @@ func TestControllerPut(t *testing.T) {
 	Trctx, span := otel.Tracer("").Start(ctx, "TestControllerPut")
 	defer span.End()
 
-	repoMock.EXPECT().Put(Trctx, id, m).Return(nil)
+	repoMock.EXPECT().Put(Trctx, id, m).DoAndReturn(func(_ context.Context, _ string, m *model.Metadata) error {
+		m.Slug = "title"
+		return nil
+	})
 	err := c.Put(ctx, m)
 	assert.NoError(t, err)
+	assert.Equal(t, "title", m.Slug)
 
 	repoMock.EXPECT().Get(Trctx, id).Return(m, nil)
 	res, err := c.Get(ctx, id)
@@ -69,7 +74,7 @@ func TestController(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
-			repoMock := gen.NewMockmetadataRepository(ctrl)
+			repoMock := gen.NewMockRepository(ctrl)
 			c := New(repoMock)
 			ctx := context.Background()
 			id := "id"
@@ -83,3 +88,175 @@ func TestController(t *testing.T) {
 		})
 	}
 }
+
+func TestControllerList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockRepository(ctrl)
+	c := New(repoMock)
+	ctx := context.Background()
+	filter := model.Filter{Director: "director"}
+	page := []*model.Metadata{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+	repoMock.EXPECT().List(gomock.Any(), filter, model.SortByTitle, 0, 3).Return(page, nil)
+	res, next, err := c.List(ctx, filter, model.SortByTitle, 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, page[:2], res)
+	assert.NotEmpty(t, next)
+
+	repoMock.EXPECT().List(gomock.Any(), filter, model.SortByTitle, 2, 3).Return(page[2:], nil)
+	res, next, err = c.List(ctx, filter, model.SortByTitle, 2, next)
+	assert.NoError(t, err)
+	assert.Equal(t, page[2:], res)
+	assert.Empty(t, next)
+}
+
+func TestControllerListInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockRepository(ctrl)
+	c := New(repoMock)
+	ctx := context.Background()
+
+	_, _, err := c.List(ctx, model.Filter{}, model.SortByID, -1, "")
+	assert.ErrorIs(t, err, ErrInvalidPageSize)
+
+	_, _, err = c.List(ctx, model.Filter{}, model.SortByID, 10, "not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+
+	// a token issued for one filter is rejected for another
+	token := encodePageToken(10, listQuery{Filter: model.Filter{Genre: "drama"}, Sort: model.SortByID})
+	_, _, err = c.List(ctx, model.Filter{Genre: "comedy"}, model.SortByID, 10, token)
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+
+	repoMock.EXPECT().List(gomock.Any(), model.Filter{}, model.SortByID, 0, MaxPageSize+1).Return(nil, nil)
+	_, _, err = c.List(ctx, model.Filter{}, model.SortByID, 1000, "")
+	assert.NoError(t, err)
+}
+
+func TestImporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockRepository(ctrl)
+	c := New(repoMock)
+	ctx := context.Background()
+
+	var batches [][]string
+	repoMock.EXPECT().PutMany(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ms []*model.Metadata) error {
+		var ids []string
+		for _, m := range ms {
+			ids = append(ids, m.ID)
+		}
+		batches = append(batches, ids)
+		if ids[0] == "fail" {
+			return errors.New("write failed")
+		}
+		return nil
+	}).AnyTimes()
+
+	importer := c.NewImporter(2)
+	importer.Add(ctx, 1, &model.Metadata{ID: "1", Title: "one"})
+	importer.Add(ctx, 2, &model.Metadata{ID: "2"})
+	importer.Add(ctx, 3, &model.Metadata{ID: "1", Title: "one again"})
+	importer.Add(ctx, 4, &model.Metadata{ID: "3", Title: "three"})
+	importer.Add(ctx, 5, &model.Metadata{ID: "fail", Title: "fail"})
+	importer.Add(ctx, 6, nil)
+	summary := importer.Close(ctx)
+
+	assert.Equal(t, [][]string{{"1"}, {"1", "3"}, {"fail"}}, batches)
+	assert.Equal(t, int64(6), summary.Received)
+	assert.Equal(t, int64(3), summary.Imported)
+	assert.Equal(t, int64(3), summary.Failed)
+	var rows []int64
+	for _, e := range summary.Errors {
+		rows = append(rows, e.Row)
+	}
+	// invalid rows are reported as they arrive, failed batches when they are written
+	assert.Equal(t, []int64{2, 6, 5}, rows)
+}
+
+func TestControllerExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockRepository(ctrl)
+	c := New(repoMock)
+	ctx := context.Background()
+
+	a, b, d := &model.Metadata{ID: "a"}, &model.Metadata{ID: "b"}, &model.Metadata{ID: "d"}
+	gomock.InOrder(
+		repoMock.EXPECT().ListAfter(gomock.Any(), "", 2).Return([]*model.Metadata{a, b}, nil),
+		repoMock.EXPECT().ListAfter(gomock.Any(), "b", 2).Return([]*model.Metadata{d}, nil),
+	)
+
+	var got [][]*model.Metadata
+	err := c.Export(ctx, 2, func(batch []*model.Metadata) error {
+		got = append(got, batch)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]*model.Metadata{{a, b}, {d}}, got)
+
+	assert.ErrorIs(t, c.Export(ctx, -1, nil), ErrInvalidPageSize)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		m          *model.Metadata
+		wantFields []string
+	}{
+		{name: "valid", m: &model.Metadata{ID: "tt0111161", Title: "The Shawshank Redemption"}},
+		{name: "nil", m: nil, wantFields: []string{"metadata"}},
+		{name: "missing id and title", m: &model.Metadata{Title: "  "}, wantFields: []string{"id", "title"}},
+		{name: "id characters", m: &model.Metadata{ID: "a b", Title: "t"}, wantFields: []string{"id"}},
+		{name: "title too long", m: &model.Metadata{ID: "1", Title: strings.Repeat("é", MaxTitleLength+1)}, wantFields: []string{"title"}},
+		{name: "control characters", m: &model.Metadata{ID: "1", Title: "a\x00b", Description: "line\nbreak"}, wantFields: []string{"title"}},
+		{name: "credits", m: &model.Metadata{ID: "1", Title: "t", Credits: []model.Credit{
+			{Name: "Jane", Role: model.CreditRoleActor},
+			{Name: " Jane ", Role: model.CreditRoleActor},
+			{Name: "", Role: "grip", Order: -1},
+		}}, wantFields: []string{"credits[1]", "credits[2].name", "credits[2].role", "credits[2].order"}},
+		{name: "external ids", m: &model.Metadata{ID: "1", Title: "t", ExternalIDs: []model.ExternalID{
+			{Provider: " IMDb ", ID: "tt0111161"},
+			{Provider: "tmdb", ID: "tt278"},
+			{Provider: "imdb", ID: "tt0068646"},
+			{Provider: "letterboxd", ID: "godfather"},
+		}}, wantFields: []string{"external_ids[1].id", "external_ids[2]", "external_ids[3].provider"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validate(tt.m)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *ValidationError
+			assert.True(t, errors.As(err, &verr))
+			assert.ErrorIs(t, err, ErrInvalidMetadata)
+			var fields []string
+			for _, v := range verr.Violations {
+				fields = append(fields, v.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestValidateCopies(t *testing.T) {
+	m := &model.Metadata{
+		ID:      " 1 ",
+		Title:   " Title ",
+		Genres:  []string{" Drama "},
+		Credits: []model.Credit{{Name: " Jane ", Role: model.CreditRoleActor}},
+	}
+	want := m.Clone()
+
+	res, err := validate(m)
+	assert.NoError(t, err)
+	assert.Equal(t, want, m)
+	assert.Equal(t, "1", res.ID)
+	assert.Equal(t, "Title", res.Title)
+	assert.Equal(t, []string{"Drama"}, res.Genres)
+	assert.Equal(t, "Jane", res.Credits[0].Name)
+}
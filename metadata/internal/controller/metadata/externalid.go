@@ -0,0 +1,42 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// ErrInvalidExternalID is returned when a lookup names an unknown provider or a malformed ID.
+var ErrInvalidExternalID = errors.New("invalid external id")
+
+// ErrExternalIDConflict is returned when a write would give an external ID that already
+// belongs to another record to a second one. Deleted records keep their external IDs until
+// they are purged.
+var ErrExternalIDConflict = errors.New("external id already in use")
+
+// GetByExternalID retrieves the live metadata record a partner ID belongs to. The provider is
+// case insensitive. It returns ErrNotFound if no live record has the ID.
+func (c *Controller) GetByExternalID(ctx context.Context, provider model.ExternalIDProvider, id string) (*model.Metadata, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "GetByExternalIDController")
+	defer span.End()
+
+	provider, id = normalizeExternalID(provider, id)
+	pattern, ok := externalIDPatterns[provider]
+	if !ok || !pattern.MatchString(id) {
+		return nil, ErrInvalidExternalID
+	}
+	span.SetAttributes(attribute.String("provider", string(provider)), attribute.String("external_id", id))
+
+	res, err := c.repo.GetByExternalID(ctx, model.ExternalID{Provider: provider, ID: id})
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
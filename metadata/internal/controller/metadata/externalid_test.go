@@ -0,0 +1,57 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"movieexample.com/metadata/internal/repository/memory"
+	"movieexample.com/metadata/pkg/model"
+)
+
+func TestControllerGetByExternalID(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New())
+
+	shawshank := &model.Metadata{ID: "1", Title: "The Shawshank Redemption", ExternalIDs: []model.ExternalID{
+		{Provider: "TMDB", ID: " 278 "},
+		{Provider: "imdb", ID: "tt0111161"},
+	}}
+	require.NoError(t, c.Put(ctx, shawshank))
+
+	got, err := c.GetByExternalID(ctx, "IMDb", "tt0111161")
+	require.NoError(t, err)
+	assert.Equal(t, "1", got.ID)
+	assert.Equal(t, []model.ExternalID{
+		{Provider: model.ExternalIDProviderIMDb, ID: "tt0111161"},
+		{Provider: model.ExternalIDProviderTMDb, ID: "278"},
+	}, got.ExternalIDs)
+
+	_, err = c.GetByExternalID(ctx, model.ExternalIDProviderTMDb, "238")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = c.GetByExternalID(ctx, "letterboxd", "shawshank")
+	assert.ErrorIs(t, err, ErrInvalidExternalID)
+	_, err = c.GetByExternalID(ctx, model.ExternalIDProviderIMDb, "0111161")
+	assert.ErrorIs(t, err, ErrInvalidExternalID)
+
+	// an ID belongs to one movie, soft deleted or not
+	other := &model.Metadata{ID: "2", Title: "Copy", ExternalIDs: []model.ExternalID{{Provider: "tmdb", ID: "278"}}}
+	assert.ErrorIs(t, c.Put(ctx, other), ErrExternalIDConflict)
+	require.NoError(t, c.Delete(ctx, "1", false))
+	_, err = c.GetByExternalID(ctx, model.ExternalIDProviderIMDb, "tt0111161")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, c.Put(ctx, other), ErrExternalIDConflict)
+	require.NoError(t, c.Delete(ctx, "1", true))
+	require.NoError(t, c.Put(ctx, other))
+
+	// replacing the IDs of a movie frees the old ones
+	patch := &model.Metadata{ExternalIDs: []model.ExternalID{{Provider: "tmdb", ID: "238"}}}
+	_, err = c.Update(ctx, "2", patch, []model.Field{model.FieldExternalIDs}, 0)
+	require.NoError(t, err)
+	_, err = c.GetByExternalID(ctx, model.ExternalIDProviderTMDb, "278")
+	assert.ErrorIs(t, err, ErrNotFound)
+	got, err = c.GetByExternalID(ctx, model.ExternalIDProviderTMDb, "238")
+	require.NoError(t, err)
+	assert.Equal(t, "2", got.ID)
+}
@@ -0,0 +1,265 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"movieexample.com/metadata/internal/imaging"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// ErrImagesDisabled is returned for uploads when the controller has no image store.
+var ErrImagesDisabled = errors.New("image uploads are not enabled")
+
+// ErrInvalidImageKind is returned for uploads of an unknown kind of image.
+var ErrInvalidImageKind = errors.New("invalid image kind")
+
+// ErrImageTooLarge is returned for uploads exceeding MaxImageSize or MaxImagePixels.
+var ErrImageTooLarge = errors.New("image too large")
+
+// ErrUnsupportedImage is returned for uploads that are not a JPEG, PNG, GIF or WebP image.
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+const (
+	// MaxImageSize caps the size of an uploaded image in bytes.
+	MaxImageSize = 20 << 20
+	// MaxImagePixels caps the number of pixels of an uploaded image, which bounds the memory
+	// needed to decode it.
+	MaxImagePixels = 50_000_000
+)
+
+// ThumbnailSize is a thumbnail generated for every uploaded image, scaled to Width while
+// keeping the aspect ratio. Images narrower than a size do not get a thumbnail of that size.
+type ThumbnailSize struct {
+	Name  string
+	Width int
+}
+
+// ThumbnailSizes lists the thumbnails generated for every uploaded image, smallest first.
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "small", Width: 185},
+	{Name: "medium", Width: 342},
+	{Name: "large", Width: 780},
+}
+
+var knownImageKinds = []model.ImageKind{model.ImageKindPoster, model.ImageKindStill, model.ImageKindBackdrop}
+
+// BlobStore stores the files of uploaded images. Keys are slash separated relative paths.
+type BlobStore interface {
+	// Put stores a blob of the given size, replacing any blob with the same key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete removes a blob. Removing a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients fetch a blob from.
+	URL(key string) string
+}
+
+// Option configures optional parts of a Controller.
+type Option func(*Controller)
+
+// WithImageStore enables image uploads, storing the files in store.
+func WithImageStore(store BlobStore) Option {
+	return func(c *Controller) {
+		c.images = store
+	}
+}
+
+// UploadImage stores an image of a movie along with its thumbnails and records it. The content
+// type is sniffed from the data rather than trusted from the client. The returned image has its
+// URLs filled in.
+func (c *Controller) UploadImage(ctx context.Context, movieID string, kind model.ImageKind, r io.Reader) (*model.Image, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "UploadImageController")
+	defer span.End()
+
+	if c.images == nil {
+		return nil, ErrImagesDisabled
+	}
+	if !slices.Contains(knownImageKinds, kind) {
+		return nil, fmt.Errorf("%w %q, want poster, still or backdrop", ErrInvalidImageKind, kind)
+	}
+	if _, err := c.Get(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImageSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrImageTooLarge, MaxImageSize)
+	}
+
+	contentType, err := imaging.Sniff(data)
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	cfg, err := imaging.DecodeConfig(data)
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, fmt.Errorf("%w: more than %d pixels", ErrImageTooLarge, MaxImagePixels)
+	}
+
+	id, err := newImageID()
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("movie_id", movieID), attribute.String("image_id", id))
+
+	dir := path.Join("movies", movieID, id)
+	img := &model.Image{
+		ID:          id,
+		MovieID:     movieID,
+		Kind:        kind,
+		ContentType: contentType,
+		Width:       int32(cfg.Width),
+		Height:      int32(cfg.Height),
+		Size:        int64(len(data)),
+		Key:         path.Join(dir, "original"+imaging.Extension(contentType)),
+	}
+
+	variants, files, err := thumbnails(data, cfg.Width, contentType, dir)
+	if err != nil {
+		return nil, err
+	}
+	img.Variants = variants
+
+	files[img.Key] = blob{data: data, contentType: contentType}
+	var stored []string
+	for key, f := range files {
+		if err := c.images.Put(ctx, key, bytes.NewReader(f.data), int64(len(f.data)), f.contentType); err != nil {
+			c.deleteBlobs(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+	}
+
+	if err := c.repo.PutImage(ctx, img); err != nil {
+		c.deleteBlobs(ctx, stored)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	c.fillImageURLs(img)
+
+	return img, nil
+}
+
+// ListImages returns the images of a movie with their URLs filled in, oldest first. A non-zero
+// asOf leaves out images uploaded after that time. Without an image store there are no images.
+func (c *Controller) ListImages(ctx context.Context, movieID string, asOf time.Time) ([]model.Image, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "ListImagesController")
+	defer span.End()
+
+	if c.images == nil {
+		return nil, nil
+	}
+
+	imgs, err := c.repo.ListImages(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := imgs[:0]
+	for _, img := range imgs {
+		if !asOf.IsZero() && img.CreatedAt.After(asOf) {
+			continue
+		}
+		c.fillImageURLs(&img)
+		res = append(res, img)
+	}
+
+	return res, nil
+}
+
+// blob is an encoded file waiting to be stored.
+type blob struct {
+	data        []byte
+	contentType string
+}
+
+// thumbnails scales an image down to every thumbnail size narrower than the image and encodes
+// the results, returning the variants and their files keyed by blob key.
+func thumbnails(data []byte, width int, contentType, dir string) ([]model.ImageVariant, map[string]blob, error) {
+	files := map[string]blob{}
+	if width <= ThumbnailSizes[0].Width {
+		return nil, files, nil
+	}
+
+	src, err := imaging.Decode(data)
+	if err != nil {
+		return nil, nil, ErrUnsupportedImage
+	}
+
+	thumbType := imaging.ThumbnailType(contentType)
+	var variants []model.ImageVariant
+	for _, size := range ThumbnailSizes {
+		if size.Width >= width {
+			break
+		}
+
+		thumb := imaging.Resize(src, size.Width)
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, thumb, thumbType); err != nil {
+			return nil, nil, err
+		}
+
+		key := path.Join(dir, size.Name+imaging.Extension(thumbType))
+		files[key] = blob{data: buf.Bytes(), contentType: thumbType}
+		variants = append(variants, model.ImageVariant{
+			Name:        size.Name,
+			ContentType: thumbType,
+			Width:       int32(thumb.Bounds().Dx()),
+			Height:      int32(thumb.Bounds().Dy()),
+			Key:         key,
+		})
+	}
+
+	return variants, files, nil
+}
+
+// deleteBlobs removes the files of a failed upload or a purged movie on a best effort basis.
+func (c *Controller) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		_ = c.images.Delete(context.WithoutCancel(ctx), key)
+	}
+}
+
+// fillImageURLs sets the URLs of an image and its variants. The variants are copied first, as
+// they may be shared with the repository.
+func (c *Controller) fillImageURLs(img *model.Image) {
+	img.URL = c.images.URL(img.Key)
+	variants := make([]model.ImageVariant, len(img.Variants))
+	for i, v := range img.Variants {
+		v.URL = c.images.URL(v.Key)
+		variants[i] = v
+	}
+	img.Variants = variants
+}
+
+// newImageID returns a random identifier for an image.
+func newImageID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,123 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"movieexample.com/metadata/internal/blob/local"
+	"movieexample.com/metadata/internal/repository/memory"
+	"movieexample.com/metadata/pkg/model"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		img.Set(x, x*height/width, color.NRGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestControllerUploadImage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := local.New(dir, "http://cdn.example.com/images/")
+	require.NoError(t, err)
+
+	repo := memory.New()
+	c := New(repo, WithImageStore(store))
+	require.NoError(t, c.Put(ctx, &model.Metadata{ID: "1", Title: "title"}))
+
+	data := testPNG(t, 400, 600)
+	img, err := c.UploadImage(ctx, "1", model.ImageKindPoster, bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, int32(400), img.Width)
+	assert.Equal(t, int32(600), img.Height)
+	assert.Equal(t, int64(len(data)), img.Size)
+	assert.Equal(t, "http://cdn.example.com/images/movies/1/"+img.ID+"/original.png", img.URL)
+
+	// the image is narrower than the large size, which is skipped
+	require.Len(t, img.Variants, 2)
+	assert.Equal(t, model.ImageVariant{
+		Name:        "small",
+		ContentType: "image/png",
+		Width:       185,
+		Height:      277,
+		Key:         "movies/1/" + img.ID + "/small.png",
+		URL:         "http://cdn.example.com/images/movies/1/" + img.ID + "/small.png",
+	}, img.Variants[0])
+	assert.Equal(t, "medium", img.Variants[1].Name)
+
+	file, err := os.ReadFile(filepath.Join(dir, "movies", "1", img.ID, "original.png"))
+	require.NoError(t, err)
+	assert.Equal(t, data, file)
+	thumb, err := os.ReadFile(filepath.Join(dir, "movies", "1", img.ID, "small.png"))
+	require.NoError(t, err)
+	cfg, err := png.DecodeConfig(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	assert.Equal(t, 185, cfg.Width)
+
+	imgs, err := c.ListImages(ctx, "1", img.CreatedAt.Add(-1))
+	require.NoError(t, err)
+	assert.Empty(t, imgs)
+	imgs, err = c.ListImages(ctx, "1", img.CreatedAt)
+	require.NoError(t, err)
+	require.Len(t, imgs, 1)
+	assert.Equal(t, *img, imgs[0])
+	// URLs are filled in on copies, the repository keeps only the keys
+	stored, err := repo.ListImages(ctx, "1")
+	require.NoError(t, err)
+	assert.Empty(t, stored[0].Variants[0].URL)
+
+	_, err = c.UploadImage(ctx, "1", model.ImageKindStill, strings.NewReader("%PDF-1.7 not an image"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+	_, err = c.UploadImage(ctx, "1", "banner", bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrInvalidImageKind)
+	_, err = c.UploadImage(ctx, "2", model.ImageKindPoster, bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = c.UploadImage(ctx, "1", model.ImageKindPoster, bytes.NewReader(make([]byte, MaxImageSize+1)))
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+
+	_, err = New(repo).UploadImage(ctx, "1", model.ImageKindPoster, bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrImagesDisabled)
+}
+
+func TestControllerPurgeDeletesImages(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := local.New(dir, "http://cdn.example.com/images/")
+	require.NoError(t, err)
+
+	repo := memory.New()
+	c := New(repo, WithImageStore(store))
+	require.NoError(t, c.Put(ctx, &model.Metadata{ID: "1", Title: "title"}))
+	img, err := c.UploadImage(ctx, "1", model.ImageKindPoster, bytes.NewReader(testPNG(t, 400, 600)))
+	require.NoError(t, err)
+	require.NotEmpty(t, img.Variants)
+
+	// a soft delete keeps the files so that the movie can be restored
+	require.NoError(t, c.Delete(ctx, "1", false))
+	assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(img.Key)))
+
+	require.NoError(t, c.Delete(ctx, "1", true))
+	assert.NoFileExists(t, filepath.Join(dir, filepath.FromSlash(img.Key)))
+	for _, v := range img.Variants {
+		assert.NoFileExists(t, filepath.Join(dir, filepath.FromSlash(v.Key)))
+	}
+	stored, err := repo.ListImages(ctx, "1")
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+	assert.ErrorIs(t, c.Delete(ctx, "1", true), ErrNotFound)
+}
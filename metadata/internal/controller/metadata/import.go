@@ -0,0 +1,113 @@
+package metadata
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"movieexample.com/metadata/pkg/model"
+)
+
+const (
+	// DefaultImportBatchSize is the number of records written per repository call during an import.
+	DefaultImportBatchSize = 500
+	// MaxImportErrors caps the number of row errors kept in an import summary.
+	MaxImportErrors = 1000
+)
+
+// Importer validates records one at a time and writes the valid ones in batches through
+// Repository.PutMany. Invalid rows and rows of failed batches are reported in the summary
+// instead of aborting the import. Every written record then gets its slug as in Put.
+// An Importer is not safe for concurrent use.
+type Importer struct {
+	ctrl      *Controller
+	batchSize int
+	batch     []importRow
+	ids       map[string]bool
+	summary   model.ImportSummary
+}
+
+type importRow struct {
+	row int64
+	m   *model.Metadata
+}
+
+// NewImporter starts a bulk import. A batch size of zero or less uses DefaultImportBatchSize.
+func (c *Controller) NewImporter(batchSize int) *Importer {
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	return &Importer{
+		ctrl:      c,
+		batchSize: batchSize,
+		ids:       map[string]bool{},
+	}
+}
+
+// Add validates a record and queues it for writing, flushing the batch once it is full.
+// Row identifies the record in the source and is used in error reports.
+func (i *Importer) Add(ctx context.Context, row int64, m *model.Metadata) {
+	i.summary.Received++
+
+	n, err := validate(m)
+	if err != nil {
+		var id string
+		if m != nil {
+			id = m.ID
+		}
+		i.fail(row, id, err.Error())
+		return
+	}
+
+	// a batch is written with a single upsert, so a repeated ID starts a new batch
+	// to keep the later row winning
+	if i.ids[n.ID] {
+		i.flush(ctx)
+	}
+	i.batch = append(i.batch, importRow{row: row, m: n})
+	i.ids[n.ID] = true
+
+	if len(i.batch) >= i.batchSize {
+		i.flush(ctx)
+	}
+}
+
+// Close writes the remaining records and returns the summary of the import.
+func (i *Importer) Close(ctx context.Context) model.ImportSummary {
+	i.flush(ctx)
+
+	return i.summary
+}
+
+func (i *Importer) flush(ctx context.Context) {
+	if len(i.batch) == 0 {
+		return
+	}
+
+	ctx, span := otel.Tracer("").Start(ctx, "ImportBatchController")
+	defer span.End()
+	span.SetAttributes(attribute.Int("batch_size", len(i.batch)))
+
+	ms := make([]*model.Metadata, 0, len(i.batch))
+	for _, r := range i.batch {
+		ms = append(ms, r.m)
+	}
+
+	if err := i.ctrl.repo.PutMany(ctx, ms); err != nil {
+		for _, r := range i.batch {
+			i.fail(r.row, r.m.ID, err.Error())
+		}
+	} else {
+		i.summary.Imported += int64(len(i.batch))
+	}
+
+	i.batch = i.batch[:0]
+	clear(i.ids)
+}
+
+func (i *Importer) fail(row int64, id, message string) {
+	i.summary.Failed++
+	if len(i.summary.Errors) < MaxImportErrors {
+		i.summary.Errors = append(i.summary.Errors, model.ImportError{Row: row, MovieID: id, Message: message})
+	}
+}
@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"movieexample.com/metadata/pkg/model"
+)
+
+// ErrInvalidPageToken is returned when a page token cannot be decoded or was
+// issued for a different filter or sort order.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// pageToken is the decoded form of the opaque token handed out to clients.
+// Query fingerprints the parameters of the request so a token cannot be replayed
+// against a different listing.
+type pageToken struct {
+	Offset int    `json:"o"`
+	Query  string `json:"q"`
+}
+
+// listQuery holds the parameters of a List call that a page token is bound to.
+type listQuery struct {
+	Filter model.Filter    `json:"f"`
+	Sort   model.SortOrder `json:"s"`
+}
+
+// searchQuery holds the parameters of a Search call that a page token is bound to.
+type searchQuery struct {
+	Query string `json:"q"`
+}
+
+// revisionsQuery holds the parameters of a ListRevisions call that a page token is bound to.
+type revisionsQuery struct {
+	MovieID string `json:"m"`
+}
+
+func queryFingerprint(query any) string {
+	b, _ := json.Marshal(query)
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodePageToken(offset int, query any) string {
+	b, _ := json.Marshal(pageToken{Offset: offset, Query: queryFingerprint(query)})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken returns the offset stored in token. An empty token starts at the first page.
+func decodePageToken(token string, query any) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	var t pageToken
+	if err := json.Unmarshal(b, &t); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	if t.Offset < 0 || t.Query != queryFingerprint(query) {
+		return 0, ErrInvalidPageToken
+	}
+
+	return t.Offset, nil
+}
@@ -0,0 +1,143 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// ErrInvalidRelation is returned for relations of an unknown type, without both IDs or from a
+// movie to itself.
+var ErrInvalidRelation = errors.New("invalid relation")
+
+// ErrRelationCycle is returned when a relation would close a loop of relations of its type, such
+// as a movie being a sequel of its own sequel.
+var ErrRelationCycle = errors.New("relation would create a cycle")
+
+var knownRelationTypes = []model.RelationType{model.RelationSequelOf, model.RelationRemakeOf, model.RelationPartOfCollection}
+
+// PutRelation links two live movies. It returns ErrNotFound if either of them does not exist and
+// ErrRelationCycle if the related movie already leads back to the movie through relations of the
+// same type. The repository checks and writes atomically, so concurrent puts cannot close a loop.
+func (c *Controller) PutRelation(ctx context.Context, rel model.Relation) error {
+	ctx, span := otel.Tracer("").Start(ctx, "PutRelationController")
+	defer span.End()
+
+	if err := validateRelation(rel); err != nil {
+		return err
+	}
+	span.SetAttributes(
+		attribute.String("movie_id", rel.MovieID),
+		attribute.String("relation_type", string(rel.Type)),
+		attribute.String("related_id", rel.RelatedID),
+	)
+
+	for _, id := range []string{rel.MovieID, rel.RelatedID} {
+		if _, err := c.Get(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	err := c.repo.PutRelation(ctx, rel)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil && errors.Is(err, repository.ErrRelationCycle) {
+		return fmt.Errorf("%w: %s already leads to %s through %s", ErrRelationCycle, rel.RelatedID, rel.MovieID, rel.Type)
+	}
+
+	return err
+}
+
+// DeleteRelation removes a link between two movies.
+// It returns ErrNotFound if there is no such relation.
+func (c *Controller) DeleteRelation(ctx context.Context, rel model.Relation) error {
+	ctx, span := otel.Tracer("").Start(ctx, "DeleteRelationController")
+	defer span.End()
+
+	if err := validateRelation(rel); err != nil {
+		return err
+	}
+
+	err := c.repo.DeleteRelation(ctx, rel)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// GetRelated returns the live movies linked to a movie in either direction, with the relation
+// that links them, ordered by relation type. Only relations of the given types are followed, or
+// all of them if there are none. The related movies are translated for the preferred locales as
+// Localize does. It returns ErrNotFound if the movie does not exist.
+func (c *Controller) GetRelated(ctx context.Context, id string, types []model.RelationType, locales []string) ([]model.RelatedMovie, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "GetRelatedController")
+	defer span.End()
+
+	for _, t := range types {
+		if !slices.Contains(knownRelationTypes, t) {
+			return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidRelation, t)
+		}
+	}
+	span.SetAttributes(attribute.String("movie_id", id))
+
+	if _, err := c.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	relations, err := c.repo.ListRelations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []model.Relation
+	var ids []string
+	for _, rel := range relations {
+		if len(types) > 0 && !slices.Contains(types, rel.Type) {
+			continue
+		}
+		matching = append(matching, rel)
+		ids = append(ids, rel.Other(id))
+	}
+
+	ms, err := c.getMany(ctx, ids, locales)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.Metadata, len(ms))
+	for _, m := range ms {
+		byID[m.ID] = m
+	}
+
+	var res []model.RelatedMovie
+	for _, rel := range matching {
+		m, ok := byID[rel.Other(id)]
+		if !ok {
+			// soft deleted movies keep their relations but are not shown
+			continue
+		}
+		res = append(res, model.RelatedMovie{Relation: rel, Metadata: m})
+	}
+
+	return res, nil
+}
+
+// validateRelation returns ErrInvalidRelation with the reason if rel cannot be stored.
+func validateRelation(rel model.Relation) error {
+	switch {
+	case !slices.Contains(knownRelationTypes, rel.Type):
+		return fmt.Errorf("%w: unknown type %q, want sequel_of, remake_of or part_of_collection", ErrInvalidRelation, rel.Type)
+	case rel.MovieID == "" || rel.RelatedID == "":
+		return fmt.Errorf("%w: movie_id and related_id are required", ErrInvalidRelation)
+	case rel.MovieID == rel.RelatedID:
+		return fmt.Errorf("%w: a movie cannot be related to itself", ErrInvalidRelation)
+	}
+
+	return nil
+}
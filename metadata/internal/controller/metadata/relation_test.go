@@ -0,0 +1,130 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"movieexample.com/metadata/internal/repository/memory"
+	"movieexample.com/metadata/pkg/model"
+)
+
+func TestControllerRelations(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New())
+	for _, m := range []*model.Metadata{
+		{ID: "alien", Title: "Alien"},
+		{ID: "aliens", Title: "Aliens"},
+		{ID: "alien3", Title: "Alien 3"},
+		{ID: "quadrilogy", Title: "Alien Quadrilogy"},
+	} {
+		require.NoError(t, c.Put(ctx, m))
+	}
+
+	for _, rel := range []model.Relation{
+		{MovieID: "aliens", Type: model.RelationSequelOf, RelatedID: "alien"},
+		{MovieID: "alien3", Type: model.RelationSequelOf, RelatedID: "aliens"},
+		{MovieID: "alien", Type: model.RelationPartOfCollection, RelatedID: "quadrilogy"},
+		{MovieID: "aliens", Type: model.RelationPartOfCollection, RelatedID: "quadrilogy"},
+	} {
+		require.NoError(t, c.PutRelation(ctx, rel))
+	}
+	require.NoError(t, c.PutRelation(ctx, model.Relation{MovieID: "aliens", Type: model.RelationSequelOf, RelatedID: "alien"}), "putting a relation twice is a no-op")
+
+	tests := []struct {
+		name string
+		rel  model.Relation
+		want error
+	}{
+		{name: "unknown type", rel: model.Relation{MovieID: "alien3", Type: "prequel_of", RelatedID: "alien"}, want: ErrInvalidRelation},
+		{name: "missing id", rel: model.Relation{MovieID: "alien3", Type: model.RelationSequelOf}, want: ErrInvalidRelation},
+		{name: "self", rel: model.Relation{MovieID: "alien", Type: model.RelationRemakeOf, RelatedID: "alien"}, want: ErrInvalidRelation},
+		{name: "direct cycle", rel: model.Relation{MovieID: "alien", Type: model.RelationSequelOf, RelatedID: "aliens"}, want: ErrRelationCycle},
+		{name: "transitive cycle", rel: model.Relation{MovieID: "alien", Type: model.RelationSequelOf, RelatedID: "alien3"}, want: ErrRelationCycle},
+		{name: "unknown movie", rel: model.Relation{MovieID: "alien", Type: model.RelationRemakeOf, RelatedID: "prometheus"}, want: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, c.PutRelation(ctx, tt.rel), tt.want)
+		})
+	}
+	require.NoError(t, c.PutRelation(ctx, model.Relation{MovieID: "alien", Type: model.RelationRemakeOf, RelatedID: "alien3"}),
+		"a loop through relations of another type is not a cycle")
+
+	related, err := c.GetRelated(ctx, "aliens", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Relation{
+		{MovieID: "aliens", Type: model.RelationPartOfCollection, RelatedID: "quadrilogy"},
+		{MovieID: "alien3", Type: model.RelationSequelOf, RelatedID: "aliens"},
+		{MovieID: "aliens", Type: model.RelationSequelOf, RelatedID: "alien"},
+	}, relations(related))
+	assert.Equal(t, "Alien Quadrilogy", related[0].Metadata.Title)
+	assert.Equal(t, "Alien 3", related[1].Metadata.Title)
+
+	related, err = c.GetRelated(ctx, "quadrilogy", []model.RelationType{model.RelationPartOfCollection}, nil)
+	require.NoError(t, err)
+	assert.Len(t, related, 2)
+
+	require.NoError(t, c.PutTranslation(ctx, "alien", &model.Translation{Locale: "fr", Title: "Alien, le huitième passager"}))
+	related, err = c.GetRelated(ctx, "aliens", []model.RelationType{model.RelationSequelOf}, []string{"fr"})
+	require.NoError(t, err)
+	require.Len(t, related, 2)
+	assert.Equal(t, "Alien 3", related[0].Metadata.Title, "movies without a translation keep their title")
+	assert.Equal(t, "Alien, le huitième passager", related[1].Metadata.Title)
+
+	require.NoError(t, c.Delete(ctx, "alien", false))
+	related, err = c.GetRelated(ctx, "aliens", []model.RelationType{model.RelationSequelOf}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Relation{{MovieID: "alien3", Type: model.RelationSequelOf, RelatedID: "aliens"}}, relations(related),
+		"deleted movies are left out")
+
+	_, err = c.GetRelated(ctx, "alien", nil, nil)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = c.GetRelated(ctx, "aliens", []model.RelationType{"spinoff_of"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidRelation)
+
+	require.NoError(t, c.DeleteRelation(ctx, model.Relation{MovieID: "alien3", Type: model.RelationSequelOf, RelatedID: "aliens"}))
+	assert.ErrorIs(t, c.DeleteRelation(ctx, model.Relation{MovieID: "alien3", Type: model.RelationSequelOf, RelatedID: "aliens"}), ErrNotFound)
+}
+
+func TestControllerRelationsConcurrentCycle(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New())
+	for _, m := range []*model.Metadata{{ID: "alien", Title: "Alien"}, {ID: "aliens", Title: "Aliens"}} {
+		require.NoError(t, c.Put(ctx, m))
+	}
+
+	rels := []model.Relation{
+		{MovieID: "aliens", Type: model.RelationSequelOf, RelatedID: "alien"},
+		{MovieID: "alien", Type: model.RelationSequelOf, RelatedID: "aliens"},
+	}
+	errs := make([]error, len(rels))
+	var wg sync.WaitGroup
+	for i, rel := range rels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.PutRelation(ctx, rel)
+		}()
+	}
+	wg.Wait()
+
+	var cycles int
+	for _, err := range errs {
+		if err != nil {
+			require.ErrorIs(t, err, ErrRelationCycle)
+			cycles++
+		}
+	}
+	assert.Equal(t, 1, cycles, "exactly one of two puts closing a loop wins")
+}
+
+func relations(related []model.RelatedMovie) []model.Relation {
+	var res []model.Relation
+	for _, r := range related {
+		res = append(res, r.Relation)
+	}
+	return res
+}
@@ -0,0 +1,29 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"movieexample.com/metadata/internal/repository"
+)
+
+// ResolveSlug returns the ID of the movie that has slug as its current or a former slug, so that
+// links made before a title change keep working. The current slug is in the Slug field of the
+// record. It returns ErrNotFound if no movie ever had the slug.
+func (c *Controller) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "ResolveSlugController")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("slug", slug))
+
+	id, err := c.repo.ResolveSlug(ctx, slug)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,107 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"movieexample.com/metadata/internal/repository/memory"
+	"movieexample.com/metadata/pkg/model"
+)
+
+func TestSlugify(t *testing.T) {
+	released := time.Date(2008, time.July, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		title string
+		date  time.Time
+		want  string
+	}{
+		{title: "The Dark Knight", date: released, want: "the-dark-knight-2008"},
+		{title: "  Amélie: Le Fabuleux Destin!  ", want: "amelie-le-fabuleux-destin"},
+		{title: "千と千尋の神隠し", date: released, want: "movie-2008"},
+		{title: strings.Repeat("word ", 30), want: strings.TrimSuffix(strings.Repeat("word-", 16), "-")},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, model.Slugify(tt.title, tt.date), "Slugify(%q)", tt.title)
+	}
+}
+
+func TestControllerSlugs(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New())
+	released := time.Date(1995, time.December, 15, 0, 0, 0, 0, time.UTC)
+
+	heat := &model.Metadata{ID: "1", Title: "Heat", ReleaseDate: released}
+	require.NoError(t, c.Put(ctx, heat))
+	assert.Equal(t, "heat-1995", heat.Slug)
+
+	// another movie with the same title and year gets a suffix
+	other := &model.Metadata{ID: "2", Title: "Heat", ReleaseDate: released, Slug: "ignored"}
+	require.NoError(t, c.Put(ctx, other))
+	assert.Equal(t, "heat-1995-2", other.Slug)
+
+	// rewriting a movie without changing its title keeps its slug
+	other.Description = "A different heist."
+	require.NoError(t, c.Put(ctx, other))
+	assert.Equal(t, "heat-1995-2", other.Slug)
+
+	// renaming keeps the former slug as a redirect
+	renamed, err := c.Update(ctx, "1", &model.Metadata{Title: "Heat (Director's Cut)"}, []model.Field{model.FieldTitle}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "heat-director-s-cut-1995", renamed.Slug)
+	got, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "heat-director-s-cut-1995", got.Slug)
+
+	for slug, wantID := range map[string]string{
+		"heat-1995":                "1",
+		"heat-director-s-cut-1995": "1",
+		"heat-1995-2":              "2",
+	} {
+		id, err := c.ResolveSlug(ctx, slug)
+		require.NoError(t, err)
+		assert.Equal(t, wantID, id, "ResolveSlug(%q)", slug)
+	}
+	_, err = c.ResolveSlug(ctx, "heat")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// a former slug stays taken and the original title takes it back
+	third := &model.Metadata{ID: "3", Title: "Heat", ReleaseDate: released}
+	require.NoError(t, c.Put(ctx, third))
+	assert.Equal(t, "heat-1995-3", third.Slug)
+	reverted, err := c.Update(ctx, "1", &model.Metadata{Title: "Heat"}, []model.Field{model.FieldTitle}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "heat-1995", reverted.Slug)
+
+	// removing the release date drops the year rather than keeping it as if it were a suffix
+	undated, err := c.Update(ctx, "1", &model.Metadata{}, []model.Field{model.FieldReleaseDate}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "heat", undated.Slug)
+	id, err := c.ResolveSlug(ctx, "heat-1995")
+	require.NoError(t, err)
+	assert.Equal(t, "1", id)
+}
+
+func TestIsSlugOf(t *testing.T) {
+	tests := []struct {
+		slug, base string
+		want       bool
+	}{
+		{slug: "heat", base: "heat", want: true},
+		{slug: "heat-2", base: "heat", want: true},
+		{slug: "heat-100", base: "heat", want: true},
+		{slug: "heat-1995-3", base: "heat-1995", want: true},
+		{slug: "heat-1", base: "heat"},
+		{slug: "heat-02", base: "heat"},
+		{slug: "heat-101", base: "heat"},
+		{slug: "heat-1995", base: "heat"},
+		{slug: "heat-1995-2", base: "heat"},
+		{slug: "heater", base: "heat"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, model.IsSlugOf(tt.slug, tt.base), "IsSlugOf(%q, %q)", tt.slug, tt.base)
+	}
+}
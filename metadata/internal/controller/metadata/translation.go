@@ -0,0 +1,169 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// ErrInvalidLocale is returned when a requested locale is not a BCP 47 language tag.
+var ErrInvalidLocale = model.ErrInvalidLocale
+
+// PutTranslation adds or replaces the translation of a movie. The translation is trimmed,
+// validated and its locale canonicalized in place; an invalid one yields a *ValidationError.
+func (c *Controller) PutTranslation(ctx context.Context, movieID string, t *model.Translation) error {
+	ctx, span := otel.Tracer("").Start(ctx, "PutTranslationController")
+	defer span.End()
+
+	if err := validateTranslation(t); err != nil {
+		return err
+	}
+
+	err := c.repo.PutTranslation(ctx, movieID, *t)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// DeleteTranslation removes the translation of a movie into locale.
+// It returns ErrNotFound if there is no such translation.
+func (c *Controller) DeleteTranslation(ctx context.Context, movieID, locale string) error {
+	ctx, span := otel.Tracer("").Start(ctx, "DeleteTranslationController")
+	defer span.End()
+
+	locale, err := model.ParseLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	err = c.repo.DeleteTranslation(ctx, movieID, locale)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// ListTranslations returns the translations of a movie ordered by locale.
+// It returns ErrNotFound if the movie does not exist.
+func (c *Controller) ListTranslations(ctx context.Context, movieID string) ([]model.Translation, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "ListTranslationsController")
+	defer span.End()
+
+	if _, err := c.Get(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	return c.repo.ListTranslations(ctx, movieID)
+}
+
+// Localize returns a copy of m with the title and description translated for the preferred
+// locales, most preferred first. Each field is taken from the first locale of the fallback chain
+// of the preferences that has it, see model.LocaleChain, and otherwise left as is. It also
+// returns the locale the title was taken from, or "" for the default one.
+func (c *Controller) Localize(ctx context.Context, m *model.Metadata, locales []string) (*model.Metadata, string, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "LocalizeController")
+	defer span.End()
+
+	chain, err := model.LocaleChain(locales...)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(chain) == 0 {
+		return m, "", nil
+	}
+	span.SetAttributes(attribute.StringSlice("locales", chain))
+
+	translations, err := c.repo.ListTranslations(ctx, m.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	res, locale := localize(m, chain, translations)
+
+	return res, locale, nil
+}
+
+// GetMany returns the live records among ids, ordered by ID and translated for the preferred
+// locales as Localize does. Unknown and deleted IDs are skipped. The records and their
+// translations are read in one batch rather than one by one. It returns ErrBatchTooLarge for
+// more than MaxBatchSize IDs.
+func (c *Controller) GetMany(ctx context.Context, ids []string, locales []string) ([]*model.Metadata, error) {
+	if len(ids) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d records, at most %d", ErrBatchTooLarge, len(ids), MaxBatchSize)
+	}
+
+	return c.getMany(ctx, ids, locales)
+}
+
+// getMany is GetMany without a cap on the number of IDs.
+func (c *Controller) getMany(ctx context.Context, ids []string, locales []string) ([]*model.Metadata, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "GetManyController")
+	defer span.End()
+
+	chain, err := model.LocaleChain(locales...)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("ids", len(ids)), attribute.StringSlice("locales", chain))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	ms, err := c.repo.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 || len(ms) == 0 {
+		return ms, nil
+	}
+
+	found := make([]string, 0, len(ms))
+	for _, m := range ms {
+		found = append(found, m.ID)
+	}
+	translations, err := c.repo.ListTranslationsForMovies(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*model.Metadata, 0, len(ms))
+	for _, m := range ms {
+		l, _ := localize(m, chain, translations[m.ID])
+		res = append(res, l)
+	}
+
+	return res, nil
+}
+
+// localize returns a copy of m with the title and description taken from the translations along
+// chain, and the locale the title was taken from.
+func localize(m *model.Metadata, chain []string, translations []model.Translation) (*model.Metadata, string) {
+	byLocale := make(map[string]model.Translation, len(translations))
+	for _, t := range translations {
+		byLocale[t.Locale] = t
+	}
+
+	res := *m
+	var locale string
+	var title, description bool
+	for _, l := range chain {
+		t, ok := byLocale[l]
+		if !ok {
+			continue
+		}
+		if !title && t.Title != "" {
+			res.Title, locale, title = t.Title, l, true
+		}
+		if !description && t.Description != "" {
+			res.Description, description = t.Description, true
+		}
+	}
+
+	return &res, locale
+}
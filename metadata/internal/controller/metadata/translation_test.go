@@ -0,0 +1,75 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"movieexample.com/metadata/internal/repository/memory"
+	"movieexample.com/metadata/pkg/model"
+)
+
+func TestControllerLocalize(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New())
+	m := &model.Metadata{ID: "1", Title: "City of God", Description: "Two boys grow up in Rio."}
+	require.NoError(t, c.Put(ctx, m))
+
+	for _, tr := range []*model.Translation{
+		{Locale: "pt", Title: "Cidade de Deus", Description: "Dois garotos crescem no Rio."},
+		{Locale: "pt_br", Title: " Cidade de Deus (BR) "},
+		{Locale: "fr", Title: "La Cité de Dieu"},
+	} {
+		require.NoError(t, c.PutTranslation(ctx, "1", tr))
+	}
+
+	translations, err := c.ListTranslations(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Translation{
+		{Locale: "fr", Title: "La Cité de Dieu"},
+		{Locale: "pt", Title: "Cidade de Deus", Description: "Dois garotos crescem no Rio."},
+		{Locale: "pt-BR", Title: "Cidade de Deus (BR)"},
+	}, translations)
+
+	tests := []struct {
+		name            string
+		locales         []string
+		wantTitle       string
+		wantDescription string
+		wantLocale      string
+	}{
+		{name: "no preference", wantTitle: "City of God", wantDescription: "Two boys grow up in Rio."},
+		{name: "exact", locales: []string{"pt"}, wantTitle: "Cidade de Deus", wantDescription: "Dois garotos crescem no Rio.", wantLocale: "pt"},
+		{name: "description from parent", locales: []string{"pt-BR"}, wantTitle: "Cidade de Deus (BR)", wantDescription: "Dois garotos crescem no Rio.", wantLocale: "pt-BR"},
+		{name: "parent", locales: []string{"pt-PT"}, wantTitle: "Cidade de Deus", wantDescription: "Dois garotos crescem no Rio.", wantLocale: "pt"},
+		{name: "description from default", locales: []string{"fr-CA"}, wantTitle: "La Cité de Dieu", wantDescription: "Two boys grow up in Rio.", wantLocale: "fr"},
+		{name: "second preference", locales: []string{"de", "fr"}, wantTitle: "La Cité de Dieu", wantDescription: "Two boys grow up in Rio.", wantLocale: "fr"},
+		{name: "untranslated", locales: []string{"ja"}, wantTitle: "City of God", wantDescription: "Two boys grow up in Rio."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, locale, err := c.Localize(ctx, m, tt.locales)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, got.Title)
+			assert.Equal(t, tt.wantDescription, got.Description)
+			assert.Equal(t, tt.wantLocale, locale)
+		})
+	}
+	assert.Equal(t, "City of God", m.Title, "Localize must not modify its argument")
+
+	_, _, err = c.Localize(ctx, m, []string{"not a locale!"})
+	assert.ErrorIs(t, err, ErrInvalidLocale)
+
+	err = c.PutTranslation(ctx, "1", &model.Translation{Locale: "??", Title: ""})
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Violations, 2)
+	assert.ErrorIs(t, c.PutTranslation(ctx, "2", &model.Translation{Locale: "pt", Title: "t"}), ErrNotFound)
+
+	require.NoError(t, c.DeleteTranslation(ctx, "1", "PT-br"))
+	assert.ErrorIs(t, c.DeleteTranslation(ctx, "1", "pt-BR"), ErrNotFound)
+	_, locale, err := c.Localize(ctx, m, []string{"pt-BR"})
+	require.NoError(t, err)
+	assert.Equal(t, "pt", locale)
+}
@@ -0,0 +1,296 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"movieexample.com/metadata/pkg/model"
+)
+
+// ErrInvalidMetadata is returned, wrapped in a *ValidationError, when a metadata record fails validation.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// Limits enforced on metadata records. Lengths are counted in characters and follow the column
+// sizes of the movie tables where there is one.
+const (
+	MaxIDLength          = 128
+	MaxTitleLength       = 255
+	MaxDescriptionLength = 10000
+	MaxNameLength        = 255
+	MaxGenres            = 20
+	MaxGenreLength       = 64
+	MaxCredits           = 500
+	MaxRuntimeMinutes    = 10000
+	MaxLanguageLength    = 35
+	MaxCountryLength     = 64
+)
+
+// idPattern lists the characters allowed in IDs, which end up in URLs and file names.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~:-]*$`)
+
+// knownCreditRoles are the roles a credit may have.
+var knownCreditRoles = map[model.CreditRole]bool{
+	model.CreditRoleActor:    true,
+	model.CreditRoleDirector: true,
+	model.CreditRoleWriter:   true,
+	model.CreditRoleProducer: true,
+	model.CreditRoleComposer: true,
+}
+
+// externalIDPatterns lists the known external ID providers and the form of their IDs.
+var externalIDPatterns = map[model.ExternalIDProvider]*regexp.Regexp{
+	model.ExternalIDProviderIMDb: regexp.MustCompile(`^tt[0-9]{7,10}$`),
+	model.ExternalIDProviderTMDb: regexp.MustCompile(`^[1-9][0-9]{0,9}$`),
+}
+
+// FieldViolation describes why a single field of a record is invalid. Field is the path of the
+// field using the JSON names of Metadata, such as "title" or "credits[2].name".
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ValidationError lists every problem found in a record. It wraps ErrInvalidMetadata.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidMetadata, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidMetadata
+}
+
+// validator collects the violations found while checking a record.
+type validator struct {
+	violations []FieldViolation
+}
+
+func (v *validator) addf(field, format string, args ...any) {
+	v.violations = append(v.violations, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+// validate checks the whole record, including its ID, and returns a copy of it with the
+// whitespace around its text fields trimmed. The record itself is left untouched, as it may be
+// shared with a repository. The slug is assigned by the service, so a slug sent along with the
+// record is dropped.
+func validate(m *model.Metadata) (*model.Metadata, error) {
+	if m == nil {
+		return nil, &ValidationError{Violations: []FieldViolation{{Field: "metadata", Description: "is required"}}}
+	}
+
+	res := m.Clone()
+	res.ID = strings.TrimSpace(res.ID)
+	res.Slug = ""
+	v := &validator{}
+	v.checkID(res.ID)
+	v.checkFields(res, model.Fields)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateFields checks only the given fields of a partial update and returns a trimmed copy
+// of it, as validate does.
+func validateFields(m *model.Metadata, fields []model.Field) (*model.Metadata, error) {
+	v := &validator{}
+	if m == nil {
+		v.addf("metadata", "is required")
+		return nil, v.err()
+	}
+	res := m.Clone()
+	v.checkFields(res, fields)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// validateTranslation trims a translation and canonicalizes its locale in place, and checks it.
+func validateTranslation(t *model.Translation) error {
+	v := &validator{}
+	if t == nil {
+		v.addf("translation", "is required")
+		return v.err()
+	}
+
+	locale, err := model.ParseLocale(strings.TrimSpace(t.Locale))
+	if err != nil {
+		v.addf("locale", "must be a BCP 47 language tag such as pt-BR")
+	} else if len(locale) > MaxLanguageLength {
+		v.addf("locale", "must be at most %d characters", MaxLanguageLength)
+	} else {
+		t.Locale = locale
+	}
+	t.Title = strings.TrimSpace(t.Title)
+	if t.Title == "" {
+		v.addf("title", "is required")
+	}
+	v.checkText("title", t.Title, MaxTitleLength, false)
+	t.Description = strings.TrimSpace(t.Description)
+	v.checkText("description", t.Description, MaxDescriptionLength, true)
+
+	return v.err()
+}
+
+func (v *validator) checkID(id string) {
+	switch {
+	case id == "":
+		v.addf("id", "is required")
+	case len(id) > MaxIDLength:
+		v.addf("id", "must be at most %d characters", MaxIDLength)
+	case !idPattern.MatchString(id):
+		v.addf("id", "may only contain letters, digits and . _ ~ : -, and must start with a letter or digit")
+	}
+}
+
+// checkFields trims the given fields of m in place and checks them. m must not be shared.
+func (v *validator) checkFields(m *model.Metadata, fields []model.Field) {
+	for _, f := range fields {
+		switch f {
+		case model.FieldTitle:
+			m.Title = strings.TrimSpace(m.Title)
+			if m.Title == "" {
+				v.addf(string(f), "is required")
+			}
+			v.checkText(string(f), m.Title, MaxTitleLength, false)
+		case model.FieldDescription:
+			m.Description = strings.TrimSpace(m.Description)
+			v.checkText(string(f), m.Description, MaxDescriptionLength, true)
+		case model.FieldDirector:
+			m.Director = strings.TrimSpace(m.Director)
+			v.checkText(string(f), m.Director, MaxNameLength, false)
+		case model.FieldGenres:
+			v.checkGenres(m)
+		case model.FieldCredits:
+			v.checkCredits(m)
+		case model.FieldRuntimeMinutes:
+			if m.RuntimeMinutes < 0 || m.RuntimeMinutes > MaxRuntimeMinutes {
+				v.addf(string(f), "must be between 0 and %d", MaxRuntimeMinutes)
+			}
+		case model.FieldOriginalLanguage:
+			m.OriginalLanguage = strings.TrimSpace(m.OriginalLanguage)
+			v.checkText(string(f), m.OriginalLanguage, MaxLanguageLength, false)
+		case model.FieldCountry:
+			m.Country = strings.TrimSpace(m.Country)
+			v.checkText(string(f), m.Country, MaxCountryLength, false)
+		case model.FieldExternalIDs:
+			v.checkExternalIDs(m)
+		}
+	}
+}
+
+func (v *validator) checkGenres(m *model.Metadata) {
+	if len(m.Genres) > MaxGenres {
+		v.addf("genres", "must have at most %d entries", MaxGenres)
+	}
+	for i := range m.Genres {
+		field := fmt.Sprintf("genres[%d]", i)
+		m.Genres[i] = strings.TrimSpace(m.Genres[i])
+		if m.Genres[i] == "" {
+			v.addf(field, "must not be empty")
+		}
+		v.checkText(field, m.Genres[i], MaxGenreLength, false)
+	}
+}
+
+func (v *validator) checkCredits(m *model.Metadata) {
+	if len(m.Credits) > MaxCredits {
+		v.addf("credits", "must have at most %d entries", MaxCredits)
+	}
+
+	type creditKey struct {
+		name string
+		role model.CreditRole
+	}
+	seen := make(map[creditKey]bool, len(m.Credits))
+	for i := range m.Credits {
+		c := &m.Credits[i]
+		field := fmt.Sprintf("credits[%d]", i)
+		c.Name = strings.TrimSpace(c.Name)
+		c.Character = strings.TrimSpace(c.Character)
+
+		if c.Name == "" {
+			v.addf(field+".name", "is required")
+		}
+		v.checkText(field+".name", c.Name, MaxNameLength, false)
+		v.checkText(field+".character", c.Character, MaxNameLength, false)
+		if !knownCreditRoles[c.Role] {
+			v.addf(field+".role", "must be one of actor, director, writer, producer or composer")
+		}
+		if c.Order < 0 {
+			v.addf(field+".order", "must not be negative")
+		}
+
+		k := creditKey{c.Name, c.Role}
+		if c.Name != "" && seen[k] {
+			v.addf(field, "duplicates an earlier credit of %s as %s", c.Name, c.Role)
+		}
+		seen[k] = true
+	}
+}
+
+func (v *validator) checkExternalIDs(m *model.Metadata) {
+	seen := make(map[model.ExternalIDProvider]bool, len(m.ExternalIDs))
+	for i := range m.ExternalIDs {
+		e := &m.ExternalIDs[i]
+		field := fmt.Sprintf("external_ids[%d]", i)
+		e.Provider, e.ID = normalizeExternalID(e.Provider, e.ID)
+
+		pattern, ok := externalIDPatterns[e.Provider]
+		switch {
+		case !ok:
+			v.addf(field+".provider", "must be one of imdb or tmdb")
+		case !pattern.MatchString(e.ID):
+			v.addf(field+".id", "is not a valid %s ID", e.Provider)
+		}
+
+		if ok && seen[e.Provider] {
+			v.addf(field, "duplicates an earlier %s ID", e.Provider)
+		}
+		seen[e.Provider] = true
+	}
+}
+
+// normalizeExternalID trims an external ID and lower cases its provider.
+func normalizeExternalID(provider model.ExternalIDProvider, id string) (model.ExternalIDProvider, string) {
+	return model.ExternalIDProvider(strings.ToLower(strings.TrimSpace(string(provider)))), strings.TrimSpace(id)
+}
+
+// checkText checks the length and characters of a text field. Control characters are rejected,
+// except line breaks and tabs in multiline fields.
+func (v *validator) checkText(field, s string, maxLength int, multiline bool) {
+	if !utf8.ValidString(s) {
+		v.addf(field, "must be valid UTF-8")
+		return
+	}
+	if n := utf8.RuneCountInString(s); n > maxLength {
+		v.addf(field, "must be at most %d characters, got %d", maxLength, n)
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) && !(multiline && (r == '\n' || r == '\r' || r == '\t')) {
+			v.addf(field, "must not contain control characters")
+			return
+		}
+	}
+}
@@ -3,14 +3,18 @@ package grpc
 import (
 	"context"
 	"errors"
+	"io"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"movieexample.com/gen"
 	"movieexample.com/metadata/internal/controller/metadata"
 	"movieexample.com/metadata/pkg/model"
+	"movieexample.com/pkg/utilities"
 )
 
 // Handler is the GRPC server implementation for the Metadata service.
@@ -31,7 +35,9 @@ func New(ctrl *metadata.Controller) *Handler {
 
 // GetMetadata is the GRPC handler for the GetMetadata RPC. It retrieves the metadata for the
 // specified movie ID, or returns an error if the metadata is not found or an internal error
-// occurs.
+// occurs. With as_of set it returns the metadata as it was at that time. With locale set the title and
+// description are translated, falling back along the parents of the locale to the default ones.
+// The movie may be named by a current or former slug instead of its ID.
 func (h *Handler) GetMetadata(ctx context.Context, req *gen.GetMetadataRequest) (*gen.GetMetadataResponse, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "GetMetadata")
 	defer span.End()
@@ -45,27 +51,93 @@ func (h *Handler) GetMetadata(ctx context.Context, req *gen.GetMetadataRequest)
 
 	counter.Add(ctx, 1, nil)
 
-	if req == nil || req.MovieId == "" {
+	if req == nil || (req.MovieId == "" && req.Slug == "") {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
+	if req.MovieId != "" && req.Slug != "" {
+		return nil, status.Error(codes.InvalidArgument, "only one of movie_id and slug may be set")
+	}
 
-	span.SetAttributes(attribute.String("movie_id", req.MovieId))
+	id := req.MovieId
+	if req.Slug != "" {
+		id, err = h.ctrl.ResolveSlug(ctx, req.Slug)
+		if err != nil && errors.Is(err, metadata.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "metadata not found")
+		} else if err != nil {
+			return nil, status.Error(codes.Internal, "failed to resolve slug")
+		}
+	}
+
+	span.SetAttributes(attribute.String("movie_id", id))
 
-	m, err := h.ctrl.Get(ctx, req.MovieId)
+	var m *model.Metadata
+	var asOf time.Time
+	if req.AsOf != nil {
+		asOf = req.AsOf.AsTime()
+		m, err = h.ctrl.GetAsOf(ctx, id, asOf)
+	} else {
+		m, err = h.ctrl.Get(ctx, id)
+	}
 	if err != nil && errors.Is(err, metadata.ErrNotFound) {
 		return nil, status.Error(codes.NotFound, "metadata not found")
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, "failed to get metadata")
 	}
 
+	var locale string
+	if locales := model.PreferredLocales(req.Locale, req.FallbackLocales...); len(locales) > 0 {
+		m, locale, err = h.ctrl.Localize(ctx, m, locales)
+	}
+	if err != nil && errors.Is(err, metadata.ErrInvalidLocale) {
+		return nil, status.Error(codes.InvalidArgument, "locale must be a BCP 47 language tag")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to localize metadata")
+	}
+
+	images, err := h.ctrl.ListImages(ctx, id, asOf)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list images")
+	}
+
 	return &gen.GetMetadataResponse{
 		Metadata: model.MetadataToProto(m),
+		Images:   model.ImagesToProto(images),
+		Locale:   locale,
 	}, nil
 }
 
+// BatchGetMetadata is the handler for the BatchGetMetadata RPC. It returns the live movies among the
+// requested ones, ordered by ID and translated as in GetMetadata, with one read of the repository.
+func (h *Handler) BatchGetMetadata(ctx context.Context, req *gen.BatchGetMetadataRequest) (*gen.BatchGetMetadataResponse, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "BatchGetMetadata")
+	defer span.End()
+
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "nil req")
+	}
+
+	span.SetAttributes(attribute.Int("movie_ids", len(req.MovieIds)))
+
+	ms, err := h.ctrl.GetMany(ctx, req.MovieIds, model.PreferredLocales(req.Locale, req.FallbackLocales...))
+	if err != nil && errors.Is(err, metadata.ErrBatchTooLarge) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, metadata.ErrInvalidLocale) {
+		return nil, status.Error(codes.InvalidArgument, "locale must be a BCP 47 language tag")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get metadata")
+	}
+
+	res := make([]*gen.Metadata, 0, len(ms))
+	for _, m := range ms {
+		res = append(res, model.MetadataToProto(m))
+	}
+
+	return &gen.BatchGetMetadataResponse{Metadata: res}, nil
+}
+
 // PutMetadata is the handler for the PutMetadata RPC. It stores the metadata for the specified movie ID,
-//
-//	or returns an error.
+// and returns the new version of the record. If an expected version is given and does not match the
+// stored one, it returns codes.Aborted so the client can re-read and retry.
 func (h *Handler) PutMetadata(ctx context.Context, req *gen.PutMetadataRequest) (*gen.PutMetadataResponse, error) {
 	ctx, span := otel.Tracer("metadata").Start(ctx, "PutMetadata")
 	defer span.End()
@@ -84,10 +156,585 @@ func (h *Handler) PutMetadata(ctx context.Context, req *gen.PutMetadataRequest)
 
 	span.SetAttributes(attribute.String("movie_id", m.ID))
 
-	err := h.ctrl.Put(ctx, m)
-	if err != nil {
+	var err error
+	if req.ExpectedVersion != nil {
+		err = h.ctrl.CompareAndPut(ctx, m, *req.ExpectedVersion)
+	} else {
+		err = h.ctrl.Put(ctx, m)
+	}
+	if err != nil && errors.Is(err, metadata.ErrInvalidMetadata) {
+		return nil, invalidArgument(err, "metadata.")
+	} else if err != nil && errors.Is(err, metadata.ErrVersionMismatch) {
+		return nil, status.Error(codes.Aborted, "metadata was modified concurrently")
+	} else if err != nil && errors.Is(err, metadata.ErrExternalIDConflict) {
+		return nil, status.Error(codes.AlreadyExists, "an external id belongs to another movie")
+	} else if err != nil {
 		return nil, status.Error(codes.Internal, "failed to put metadata")
 	}
 
-	return &gen.PutMetadataResponse{}, nil
+	return &gen.PutMetadataResponse{Version: m.Version}, nil
+}
+
+// UpdateMetadata is the handler for the UpdateMetadata RPC. It copies the fields named in the update
+// mask from the request metadata to the stored record and returns the updated record. Mask paths are
+// validated against the fields of gen.Metadata; unknown, nested and read-only paths are rejected.
+func (h *Handler) UpdateMetadata(ctx context.Context, req *gen.UpdateMetadataRequest) (*gen.UpdateMetadataResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "UpdateMetadata")
+	defer span.End()
+
+	if req == nil || req.Metadata == nil {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+
+	if req.Metadata.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if req.UpdateMask == nil {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.Metadata.Id))
+
+	fields, err := model.FieldsFromMask(req.UpdateMask)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var expectedVersion int64
+	if req.ExpectedVersion != nil {
+		if *req.ExpectedVersion <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "expected_version must be positive")
+		}
+		expectedVersion = *req.ExpectedVersion
+	}
+
+	m, err := h.ctrl.Update(ctx, req.Metadata.Id, model.MetadataFromProto(req.Metadata), fields, expectedVersion)
+	if err != nil && errors.Is(err, metadata.ErrInvalidMetadata) {
+		return nil, invalidArgument(err, "metadata.")
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil && errors.Is(err, metadata.ErrVersionMismatch) {
+		return nil, status.Error(codes.Aborted, "metadata was modified concurrently")
+	} else if err != nil && errors.Is(err, metadata.ErrExternalIDConflict) {
+		return nil, status.Error(codes.AlreadyExists, "an external id belongs to another movie")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to update metadata")
+	}
+
+	return &gen.UpdateMetadataResponse{Metadata: model.MetadataToProto(m)}, nil
+}
+
+// invalidArgument converts a validation error to a codes.InvalidArgument status carrying a
+// google.rpc.BadRequest with one field violation per problem. Field paths are prefixed with prefix
+// so they point into the request message.
+func invalidArgument(err error, prefix string) error {
+	var verr *metadata.ValidationError
+	if !errors.As(err, &verr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	br := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       prefix + v.Field,
+			Description: v.Description,
+		})
+	}
+
+	st, detailErr := status.New(codes.InvalidArgument, "invalid metadata").WithDetails(br)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return st.Err()
+}
+
+// ListMetadata is the handler for the ListMetadata RPC. It returns a page of metadata records
+// matching the request filter, and a token for the next page if there are more records.
+func (h *Handler) ListMetadata(ctx context.Context, req *gen.ListMetadataRequest) (*gen.ListMetadataResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "ListMetadata")
+	defer span.End()
+
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	filter := model.FilterFromProto(req.Filter)
+	if filter.ReleaseYearFrom != 0 && filter.ReleaseYearTo != 0 && filter.ReleaseYearFrom > filter.ReleaseYearTo {
+		return nil, status.Error(codes.InvalidArgument, "release year range is empty")
+	}
+
+	res, next, err := h.ctrl.List(ctx, filter, model.SortOrderFromProto(req.SortOrder), int(req.PageSize), req.PageToken)
+	if err != nil && (errors.Is(err, metadata.ErrInvalidPageToken) || errors.Is(err, metadata.ErrInvalidPageSize)) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list metadata")
+	}
+
+	resp := &gen.ListMetadataResponse{NextPageToken: next}
+	for _, m := range res {
+		resp.Metadata = append(resp.Metadata, model.MetadataToProto(m))
+	}
+
+	return resp, nil
+}
+
+// SearchMetadata is the handler for the SearchMetadata RPC. It runs a full-text search over
+// the catalog and returns ranked results, best match first.
+func (h *Handler) SearchMetadata(ctx context.Context, req *gen.SearchMetadataRequest) (*gen.SearchMetadataResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "SearchMetadata")
+	defer span.End()
+
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	span.SetAttributes(attribute.String("query", req.Query))
+
+	res, next, err := h.ctrl.Search(ctx, req.Query, int(req.PageSize), req.PageToken)
+	if err != nil && (errors.Is(err, metadata.ErrEmptyQuery) ||
+		errors.Is(err, metadata.ErrInvalidPageToken) || errors.Is(err, metadata.ErrInvalidPageSize)) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to search metadata")
+	}
+
+	resp := &gen.SearchMetadataResponse{NextPageToken: next}
+	for _, r := range res {
+		resp.Results = append(resp.Results, model.SearchResultToProto(r))
+	}
+
+	return resp, nil
+}
+
+// DeleteMetadata is the handler for the DeleteMetadata RPC. It soft deletes the metadata of the
+// specified movie ID, or removes it permanently when a hard delete is requested by an admin.
+func (h *Handler) DeleteMetadata(ctx context.Context, req *gen.DeleteMetadataRequest) (*gen.DeleteMetadataResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "DeleteMetadata")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if req.Hard && !utilities.IsAdmin(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "hard delete requires admin access")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId), attribute.Bool("hard", req.Hard))
+
+	err := h.ctrl.Delete(ctx, req.MovieId, req.Hard)
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to delete metadata")
+	}
+
+	return &gen.DeleteMetadataResponse{}, nil
+}
+
+// UndeleteMetadata is the handler for the UndeleteMetadata RPC. It restores soft deleted metadata
+// for the specified movie ID, or returns NotFound if there is nothing to restore.
+func (h *Handler) UndeleteMetadata(ctx context.Context, req *gen.UndeleteMetadataRequest) (*gen.UndeleteMetadataResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "UndeleteMetadata")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId))
+
+	err := h.ctrl.Undelete(ctx, req.MovieId)
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "deleted metadata not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to undelete metadata")
+	}
+
+	return &gen.UndeleteMetadataResponse{}, nil
+}
+
+// PutMetadataTranslation is the handler for the PutMetadataTranslation RPC. It adds or replaces the
+// translation of a metadata record into one locale and returns it with its locale canonicalized.
+func (h *Handler) PutMetadataTranslation(ctx context.Context, req *gen.PutMetadataTranslationRequest) (*gen.PutMetadataTranslationResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "PutMetadataTranslation")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if req.Translation == nil {
+		return nil, status.Error(codes.InvalidArgument, "translation is required")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId))
+
+	t := model.TranslationFromProto(req.Translation)
+	err := h.ctrl.PutTranslation(ctx, req.MovieId, t)
+	if err != nil && errors.Is(err, metadata.ErrInvalidMetadata) {
+		return nil, invalidArgument(err, "translation.")
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to put translation")
+	}
+
+	return &gen.PutMetadataTranslationResponse{Translation: model.TranslationToProto(*t)}, nil
+}
+
+// DeleteMetadataTranslation is the handler for the DeleteMetadataTranslation RPC. It removes the
+// translation of a metadata record into one locale.
+func (h *Handler) DeleteMetadataTranslation(ctx context.Context, req *gen.DeleteMetadataTranslationRequest) (*gen.DeleteMetadataTranslationResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "DeleteMetadataTranslation")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId), attribute.String("locale", req.Locale))
+
+	err := h.ctrl.DeleteTranslation(ctx, req.MovieId, req.Locale)
+	if err != nil && errors.Is(err, metadata.ErrInvalidLocale) {
+		return nil, status.Error(codes.InvalidArgument, "locale must be a BCP 47 language tag")
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "translation not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to delete translation")
+	}
+
+	return &gen.DeleteMetadataTranslationResponse{}, nil
+}
+
+// ListMetadataTranslations is the handler for the ListMetadataTranslations RPC. It returns every
+// translation of a metadata record ordered by locale.
+func (h *Handler) ListMetadataTranslations(ctx context.Context, req *gen.ListMetadataTranslationsRequest) (*gen.ListMetadataTranslationsResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "ListMetadataTranslations")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId))
+
+	translations, err := h.ctrl.ListTranslations(ctx, req.MovieId)
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list translations")
+	}
+
+	res := &gen.ListMetadataTranslationsResponse{}
+	for _, t := range translations {
+		res.Translations = append(res.Translations, model.TranslationToProto(t))
+	}
+
+	return res, nil
+}
+
+// LookupMetadataByExternalID is the handler for the LookupMetadataByExternalID RPC. It returns
+// the live metadata record that has the given IMDb or TMDb ID.
+func (h *Handler) LookupMetadataByExternalID(ctx context.Context, req *gen.LookupMetadataByExternalIDRequest) (*gen.LookupMetadataByExternalIDResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "LookupMetadataByExternalID")
+	defer span.End()
+
+	if req == nil || req.Provider == "" || req.ExternalId == "" {
+		return nil, status.Error(codes.InvalidArgument, "provider and external_id are required")
+	}
+
+	span.SetAttributes(attribute.String("provider", req.Provider), attribute.String("external_id", req.ExternalId))
+
+	m, err := h.ctrl.GetByExternalID(ctx, model.ExternalIDProvider(req.Provider), req.ExternalId)
+	if err != nil && errors.Is(err, metadata.ErrInvalidExternalID) {
+		return nil, status.Error(codes.InvalidArgument, "provider must be imdb or tmdb with an ID of that provider")
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to look up metadata")
+	}
+
+	return &gen.LookupMetadataByExternalIDResponse{Metadata: model.MetadataToProto(m)}, nil
+}
+
+// PutMetadataRelation is the handler for the PutMetadataRelation RPC. It links two metadata records
+// with a typed relation. A relation that would close a loop of relations of its type, such as a
+// movie being a sequel of its own sequel, returns codes.FailedPrecondition.
+func (h *Handler) PutMetadataRelation(ctx context.Context, req *gen.PutMetadataRelationRequest) (*gen.PutMetadataRelationResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "PutMetadataRelation")
+	defer span.End()
+
+	if req == nil || req.Relation == nil {
+		return nil, status.Error(codes.InvalidArgument, "relation is required")
+	}
+
+	err := h.ctrl.PutRelation(ctx, model.RelationFromProto(req.Relation))
+	if err != nil && errors.Is(err, metadata.ErrInvalidRelation) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, metadata.ErrRelationCycle) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to put relation")
+	}
+
+	return &gen.PutMetadataRelationResponse{}, nil
+}
+
+// DeleteMetadataRelation is the handler for the DeleteMetadataRelation RPC. It removes a relation
+// between two metadata records.
+func (h *Handler) DeleteMetadataRelation(ctx context.Context, req *gen.DeleteMetadataRelationRequest) (*gen.DeleteMetadataRelationResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "DeleteMetadataRelation")
+	defer span.End()
+
+	if req == nil || req.Relation == nil {
+		return nil, status.Error(codes.InvalidArgument, "relation is required")
+	}
+
+	err := h.ctrl.DeleteRelation(ctx, model.RelationFromProto(req.Relation))
+	if err != nil && errors.Is(err, metadata.ErrInvalidRelation) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "relation not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to delete relation")
+	}
+
+	return &gen.DeleteMetadataRelationResponse{}, nil
+}
+
+// GetRelatedMovies is the handler for the GetRelatedMovies RPC. It returns the live movies related
+// to a metadata record in either direction, optionally restricted to some relation types and
+// translated into a locale.
+func (h *Handler) GetRelatedMovies(ctx context.Context, req *gen.GetRelatedMoviesRequest) (*gen.GetRelatedMoviesResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "GetRelatedMovies")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId))
+
+	var types []model.RelationType
+	for _, t := range req.Types {
+		types = append(types, model.RelationType(t))
+	}
+	related, err := h.ctrl.GetRelated(ctx, req.MovieId, types, model.PreferredLocales(req.Locale, req.FallbackLocales...))
+	if err != nil && errors.Is(err, metadata.ErrInvalidRelation) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, metadata.ErrInvalidLocale) {
+		return nil, status.Error(codes.InvalidArgument, "locale must be a BCP 47 language tag")
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get related movies")
+	}
+
+	return &gen.GetRelatedMoviesResponse{Related: model.RelatedMoviesToProto(related)}, nil
+}
+
+// ListMetadataRevisions is the handler for the ListMetadataRevisions RPC. It returns a page of the
+// revisions of a metadata record, newest first, and a token for the next page if there are more.
+func (h *Handler) ListMetadataRevisions(ctx context.Context, req *gen.ListMetadataRevisionsRequest) (*gen.ListMetadataRevisionsResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "ListMetadataRevisions")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId))
+
+	res, next, err := h.ctrl.ListRevisions(ctx, req.MovieId, int(req.PageSize), req.PageToken)
+	if err != nil && (errors.Is(err, metadata.ErrInvalidPageToken) || errors.Is(err, metadata.ErrInvalidPageSize)) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list revisions")
+	}
+
+	revisions := make([]*gen.MetadataRevision, 0, len(res))
+	for _, r := range res {
+		revisions = append(revisions, model.RevisionToProto(r))
+	}
+
+	return &gen.ListMetadataRevisionsResponse{Revisions: revisions, NextPageToken: next}, nil
+}
+
+// RevertMetadata is the handler for the RevertMetadata RPC. It writes the content of an earlier
+// revision back as a new revision and returns the reverted metadata. If an expected version is given
+// and does not match the stored one, it returns codes.Aborted.
+func (h *Handler) RevertMetadata(ctx context.Context, req *gen.RevertMetadataRequest) (*gen.RevertMetadataResponse, error) {
+	ctx, span := otel.Tracer("metadata").Start(ctx, "RevertMetadata")
+	defer span.End()
+
+	if req == nil || req.MovieId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if req.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "version must be positive")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", req.MovieId))
+
+	var expectedVersion int64
+	if req.ExpectedVersion != nil {
+		if *req.ExpectedVersion <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "expected_version must be positive")
+		}
+		expectedVersion = *req.ExpectedVersion
+	}
+
+	m, err := h.ctrl.Revert(ctx, req.MovieId, req.Version, expectedVersion)
+	if err != nil && errors.Is(err, metadata.ErrInvalidMetadata) {
+		return nil, invalidArgument(err, "")
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "revision not found")
+	} else if err != nil && errors.Is(err, metadata.ErrVersionMismatch) {
+		return nil, status.Error(codes.Aborted, "metadata was modified concurrently")
+	} else if err != nil && errors.Is(err, metadata.ErrExternalIDConflict) {
+		return nil, status.Error(codes.AlreadyExists, "an external id belongs to another movie")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "failed to revert metadata")
+	}
+
+	return &gen.RevertMetadataResponse{Metadata: model.MetadataToProto(m)}, nil
+}
+
+// ImportMetadata is the handler for the client-streaming ImportMetadata RPC. It validates every
+// streamed record and writes the valid ones in batches. Rows that fail are reported in the summary
+// sent once the client closes the stream; they do not abort the import.
+func (h *Handler) ImportMetadata(stream gen.MetadataService_ImportMetadataServer) error {
+	ctx, span := otel.Tracer("metadata").Start(stream.Context(), "ImportMetadata")
+	defer span.End()
+
+	importer := h.ctrl.NewImporter(metadata.DefaultImportBatchSize)
+	var received int64
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+		received++
+
+		row := req.Row
+		if row == 0 {
+			row = received
+		}
+		var m *model.Metadata
+		if req.Metadata != nil {
+			m = model.MetadataFromProto(req.Metadata)
+		}
+		importer.Add(ctx, row, m)
+	}
+
+	summary := importer.Close(ctx)
+	span.SetAttributes(
+		attribute.Int64("imported", summary.Imported),
+		attribute.Int64("failed", summary.Failed),
+	)
+
+	return stream.SendAndClose(model.ImportSummaryToProto(summary))
+}
+
+// ExportMetadata is the handler for the server-streaming ExportMetadata RPC. It streams every live
+// metadata record in ID order, one message per batch, reading the catalog a batch at a time.
+func (h *Handler) ExportMetadata(req *gen.ExportMetadataRequest, stream gen.MetadataService_ExportMetadataServer) error {
+	ctx, span := otel.Tracer("metadata").Start(stream.Context(), "ExportMetadata")
+	defer span.End()
+
+	var exported int
+	err := h.ctrl.Export(ctx, int(req.GetBatchSize()), func(batch []*model.Metadata) error {
+		res := &gen.ExportMetadataResponse{Metadata: make([]*gen.Metadata, 0, len(batch))}
+		for _, m := range batch {
+			res.Metadata = append(res.Metadata, model.MetadataToProto(m))
+		}
+		exported += len(batch)
+		return stream.Send(res)
+	})
+	span.SetAttributes(attribute.Int("exported", exported))
+	if err != nil && errors.Is(err, metadata.ErrInvalidPageSize) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, "failed to export metadata")
+	}
+
+	return nil
+}
+
+// errUnexpectedInfo is returned when an upload stream carries its info anywhere but first.
+var errUnexpectedInfo = errors.New("info must only be sent in the first message")
+
+// UploadImage is the handler for the client-streaming UploadImage RPC. The first message names the
+// movie and the kind of image, the following ones carry the image data in chunks. It returns the
+// stored image with the URLs of the original and its thumbnails.
+func (h *Handler) UploadImage(stream gen.MetadataService_UploadImageServer) error {
+	ctx, span := otel.Tracer("metadata").Start(stream.Context(), "UploadImage")
+	defer span.End()
+
+	req, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "info is required")
+	} else if err != nil {
+		return err
+	}
+
+	info := req.GetInfo()
+	if info == nil || info.MovieId == "" {
+		return status.Error(codes.InvalidArgument, "info with a movie id is required in the first message")
+	}
+
+	span.SetAttributes(attribute.String("movie_id", info.MovieId))
+
+	img, err := h.ctrl.UploadImage(ctx, info.MovieId, model.ImageKind(info.Kind), &chunkReader{stream: stream})
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return status.Error(codes.NotFound, "metadata not found")
+	} else if err != nil && errors.Is(err, metadata.ErrImagesDisabled) {
+		return status.Error(codes.Unimplemented, err.Error())
+	} else if err != nil && (errors.Is(err, metadata.ErrInvalidImageKind) ||
+		errors.Is(err, metadata.ErrUnsupportedImage) ||
+		errors.Is(err, metadata.ErrImageTooLarge) ||
+		errors.Is(err, errUnexpectedInfo)) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && status.Code(err) != codes.Unknown {
+		// the stream itself failed, such as when the client cancelled the upload
+		return err
+	} else if err != nil {
+		return status.Error(codes.Internal, "failed to upload image")
+	}
+
+	return stream.SendAndClose(&gen.UploadImageResponse{Image: model.ImageToProto(img)})
+}
+
+// chunkReader reads the image data of an upload stream.
+type chunkReader struct {
+	stream gen.MetadataService_UploadImageServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetInfo() != nil {
+			return 0, errUnexpectedInfo
+		}
+		r.buf = req.GetChunk()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
 }
@@ -1,15 +1,29 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
+	"image"
+	"image/jpeg"
+	"io"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"movieexample.com/gen"
+	"movieexample.com/metadata/internal/blob/local"
 	"movieexample.com/metadata/internal/controller/metadata"
 	"movieexample.com/metadata/internal/repository/memory"
 	"movieexample.com/metadata/pkg/model"
+	"movieexample.com/pkg/utilities"
 )
 
 var (
@@ -46,6 +60,8 @@ func TestHandler_GetMetadata(t *testing.T) {
 					Title:       "The Matrix",
 					Director:    "The Wachowskis",
 					Description: "A computer hacker.",
+					Slug:        "the-matrix",
+					Version:     1,
 				},
 			},
 			wantErr: false,
@@ -100,3 +116,441 @@ func TestHandler_PutMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_PutMetadataDetails(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+	want := &gen.Metadata{
+		Id:          "2",
+		Title:       "Interstellar",
+		Description: "Explorers travel through a wormhole.",
+		Director:    "Christopher Nolan",
+		Genres:      []string{"drama", "sci-fi"},
+		Credits: []*gen.Credit{
+			{Name: "Christopher Nolan", Role: string(model.CreditRoleDirector)},
+			{Name: "Matthew McConaughey", Role: string(model.CreditRoleActor), Character: "Cooper", Order: 1},
+		},
+		RuntimeMinutes:   169,
+		ReleaseDate:      timestamppb.New(time.Date(2014, time.November, 7, 0, 0, 0, 0, time.UTC)),
+		OriginalLanguage: "en",
+		Country:          "US",
+	}
+
+	putRes, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: want})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), putRes.Version)
+	want.Version = putRes.Version
+	want.Slug = "interstellar-2014"
+
+	got, err := h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: want.Id})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(want, got.Metadata), "got %v, want %v", got.Metadata, want)
+}
+
+func TestHandler_DeleteMetadata(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+	put := func(id string) {
+		if _, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: id, Title: "title"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("1")
+
+	_, err := h.DeleteMetadata(ctx, &gen.DeleteMetadataRequest{MovieId: "1"})
+	assert.NoError(t, err)
+	_, err = h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = h.UndeleteMetadata(ctx, &gen.UndeleteMetadataRequest{MovieId: "1"})
+	assert.NoError(t, err)
+	_, err = h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1"})
+	assert.NoError(t, err)
+
+	_, err = h.DeleteMetadata(ctx, &gen.DeleteMetadataRequest{MovieId: "1", Hard: true})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = h.DeleteMetadata(utilities.WithAdmin(ctx), &gen.DeleteMetadataRequest{MovieId: "1", Hard: true})
+	assert.NoError(t, err)
+	_, err = h.UndeleteMetadata(ctx, &gen.UndeleteMetadataRequest{MovieId: "1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = h.DeleteMetadata(ctx, &gen.DeleteMetadataRequest{MovieId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestHandler_PutMetadataExpectedVersion(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+	version := func(v int64) *int64 { return &v }
+
+	res, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "title"}, ExpectedVersion: version(0)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.Version)
+
+	_, err = h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "title"}, ExpectedVersion: version(0)})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	res, err = h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "title"}, ExpectedVersion: version(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Version)
+
+	_, err = h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "title"}, ExpectedVersion: version(1)})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	res, err = h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "title"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), res.Version)
+}
+
+func TestHandler_PutMetadataInvalid(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+
+	_, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{
+		Id:      "bad id",
+		Title:   "   ",
+		Genres:  []string{"Drama", ""},
+		Credits: []*gen.Credit{{Name: "Jane", Role: "grip"}},
+	}})
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+	assert.Equal(t, []string{
+		"metadata.id",
+		"metadata.title",
+		"metadata.genres[1]",
+		"metadata.credits[0].role",
+	}, fields)
+
+	_, err = h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: " 1 ", Title: "  title\t"}})
+	assert.NoError(t, err)
+	res, err := h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "title", res.Metadata.Title)
+}
+
+func TestHandler_UpdateMetadata(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+	_, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{
+		Id:          "1",
+		Title:       "title",
+		Description: "description",
+		Genres:      []string{"Drama"},
+	}})
+	assert.NoError(t, err)
+
+	res, err := h.UpdateMetadata(ctx, &gen.UpdateMetadataRequest{
+		Metadata:   &gen.Metadata{Id: "1", Title: "new title", Description: "ignored", RuntimeMinutes: 120},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title", "runtime_minutes"}},
+	})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&gen.Metadata{
+		Id:             "1",
+		Title:          "new title",
+		Description:    "description",
+		Genres:         []string{"Drama"},
+		RuntimeMinutes: 120,
+		Slug:           "new-title",
+		Version:        2,
+	}, res.Metadata), "got %v", res.Metadata)
+
+	version := int64(1)
+	_, err = h.UpdateMetadata(ctx, &gen.UpdateMetadataRequest{
+		Metadata:        &gen.Metadata{Id: "1", Title: "stale title"},
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		ExpectedVersion: &version,
+	})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	invalid := []*gen.UpdateMetadataRequest{
+		{Metadata: &gen.Metadata{Id: "1"}},
+		{Metadata: &gen.Metadata{Id: "1"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"rating"}}},
+		{Metadata: &gen.Metadata{Id: "1"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"version"}}},
+		{Metadata: &gen.Metadata{Id: "1"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"credits.name"}}},
+	}
+	for _, req := range invalid {
+		_, err := h.UpdateMetadata(ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "mask %v", req.UpdateMask)
+	}
+
+	_, err = h.UpdateMetadata(ctx, &gen.UpdateMetadataRequest{
+		Metadata:   &gen.Metadata{Id: "2", Title: "title"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestHandler_RevertMetadata(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := utilities.WithAuthor(context.Background(), "editor")
+
+	for _, title := range []string{"original", "vandalized"} {
+		_, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: title}})
+		assert.NoError(t, err)
+	}
+
+	res, err := h.RevertMetadata(ctx, &gen.RevertMetadataRequest{MovieId: "1", Version: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "original", res.Metadata.Title)
+	assert.Equal(t, int64(3), res.Metadata.Version)
+
+	revs, err := h.ListMetadataRevisions(ctx, &gen.ListMetadataRevisionsRequest{MovieId: "1", PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, revs.Revisions, 2)
+	assert.Equal(t, int64(3), revs.Revisions[0].Version)
+	assert.Equal(t, "editor", revs.Revisions[0].Author)
+	assert.Equal(t, []*gen.FieldChange{{Field: "title", OldValue: `"vandalized"`, NewValue: `"original"`}}, revs.Revisions[0].Changes)
+	assert.NotEmpty(t, revs.NextPageToken)
+
+	revs, err = h.ListMetadataRevisions(ctx, &gen.ListMetadataRevisionsRequest{MovieId: "1", PageSize: 2, PageToken: revs.NextPageToken})
+	assert.NoError(t, err)
+	assert.Len(t, revs.Revisions, 1)
+	assert.Empty(t, revs.NextPageToken)
+
+	expected := int64(2)
+	_, err = h.RevertMetadata(ctx, &gen.RevertMetadataRequest{MovieId: "1", Version: 1, ExpectedVersion: &expected})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = h.RevertMetadata(ctx, &gen.RevertMetadataRequest{MovieId: "1", Version: 9})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	got, err := h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1", AsOf: timestamppb.New(time.Now().Add(-time.Hour))})
+	assert.Equal(t, codes.NotFound, status.Code(err), "got %v", got)
+}
+
+// uploadStream is a fake UploadImage stream that receives the given messages.
+type uploadStream struct {
+	grpc.ServerStream
+	reqs []*gen.UploadImageRequest
+	res  *gen.UploadImageResponse
+}
+
+func (s *uploadStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *uploadStream) Recv() (*gen.UploadImageRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *uploadStream) SendAndClose(res *gen.UploadImageResponse) error {
+	s.res = res
+	return nil
+}
+
+func TestHandler_UploadImage(t *testing.T) {
+	store, err := local.New(t.TempDir(), "/images")
+	assert.NoError(t, err)
+	h := New(metadata.New(memory.New(), metadata.WithImageStore(store)))
+	ctx := context.Background()
+
+	_, err = h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "title"}})
+	assert.NoError(t, err)
+
+	img := image.NewGray(image.Rect(0, 0, 500, 200))
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, img, nil))
+	data := buf.Bytes()
+
+	info := &gen.UploadImageRequest{Data: &gen.UploadImageRequest_Info{Info: &gen.UploadImageInfo{MovieId: "1", Kind: "still"}}}
+	stream := &uploadStream{reqs: []*gen.UploadImageRequest{
+		info,
+		{Data: &gen.UploadImageRequest_Chunk{Chunk: data[:100]}},
+		{Data: &gen.UploadImageRequest_Chunk{Chunk: data[100:]}},
+	}}
+	assert.NoError(t, h.UploadImage(stream))
+	assert.Equal(t, "image/jpeg", stream.res.Image.ContentType)
+	assert.Equal(t, int32(500), stream.res.Image.Width)
+	assert.Len(t, stream.res.Image.Variants, 2)
+
+	res, err := h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1"})
+	assert.NoError(t, err)
+	assert.Len(t, res.Images, 1)
+	assert.Equal(t, "/images/movies/1/"+res.Images[0].Id+"/original.jpg", res.Images[0].Url)
+
+	invalid := [][]*gen.UploadImageRequest{
+		nil,
+		{{Data: &gen.UploadImageRequest_Chunk{Chunk: data}}},
+		{info, info},
+		{info, {Data: &gen.UploadImageRequest_Chunk{Chunk: []byte("plain text")}}},
+	}
+	for _, reqs := range invalid {
+		err := h.UploadImage(&uploadStream{reqs: reqs})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "%v", err)
+	}
+}
+
+func TestHandler_BatchGetMetadata(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+	for _, m := range []*gen.Metadata{{Id: "1", Title: "Spirited Away"}, {Id: "2", Title: "Ponyo"}, {Id: "3", Title: "Porco Rosso"}} {
+		_, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: m})
+		assert.NoError(t, err)
+	}
+	_, err := h.PutMetadataTranslation(ctx, &gen.PutMetadataTranslationRequest{
+		MovieId:     "2",
+		Translation: &gen.Translation{Locale: "de", Title: "Ponyo – Das große Abenteuer am Meer"},
+	})
+	assert.NoError(t, err)
+	_, err = h.DeleteMetadata(ctx, &gen.DeleteMetadataRequest{MovieId: "3"})
+	assert.NoError(t, err)
+
+	res, err := h.BatchGetMetadata(ctx, &gen.BatchGetMetadataRequest{MovieIds: []string{"3", "2", "missing", "1"}, Locale: "de"})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Metadata, 2, "unknown and deleted movies are left out") {
+		assert.Equal(t, "Spirited Away", res.Metadata[0].Title)
+		assert.Equal(t, "Ponyo – Das große Abenteuer am Meer", res.Metadata[1].Title)
+	}
+
+	_, err = h.BatchGetMetadata(ctx, &gen.BatchGetMetadataRequest{MovieIds: make([]string, metadata.MaxBatchSize+1)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHandler_MetadataTranslations(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+
+	_, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "Spirited Away"}})
+	assert.NoError(t, err)
+
+	put, err := h.PutMetadataTranslation(ctx, &gen.PutMetadataTranslationRequest{
+		MovieId:     "1",
+		Translation: &gen.Translation{Locale: "de_de", Title: "Chihiros Reise ins Zauberland"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "de-DE", put.Translation.Locale)
+
+	res, err := h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1", Locale: "de-DE"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Chihiros Reise ins Zauberland", res.Metadata.Title)
+	assert.Equal(t, "de-DE", res.Locale)
+
+	res, err = h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1", Locale: "de"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Spirited Away", res.Metadata.Title)
+	assert.Empty(t, res.Locale)
+
+	res, err = h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1", Locale: "fr", FallbackLocales: []string{"de-DE"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Chihiros Reise ins Zauberland", res.Metadata.Title)
+	assert.Equal(t, "de-DE", res.Locale)
+
+	_, err = h.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: "1", Locale: "-"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.PutMetadataTranslation(ctx, &gen.PutMetadataTranslationRequest{
+		MovieId:     "1",
+		Translation: &gen.Translation{Locale: "-", Title: "title"},
+	})
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Len(t, st.Details(), 1)
+	if br, ok := st.Details()[0].(*errdetails.BadRequest); assert.True(t, ok) {
+		assert.Equal(t, "translation.locale", br.FieldViolations[0].Field)
+	}
+
+	list, err := h.ListMetadataTranslations(ctx, &gen.ListMetadataTranslationsRequest{MovieId: "1"})
+	assert.NoError(t, err)
+	assert.Len(t, list.Translations, 1)
+
+	_, err = h.DeleteMetadataTranslation(ctx, &gen.DeleteMetadataTranslationRequest{MovieId: "1", Locale: "de-DE"})
+	assert.NoError(t, err)
+	_, err = h.DeleteMetadataTranslation(ctx, &gen.DeleteMetadataTranslationRequest{MovieId: "1", Locale: "de-DE"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = h.ListMetadataTranslations(ctx, &gen.ListMetadataTranslationsRequest{MovieId: "2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestHandler_LookupMetadataByExternalID(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+
+	_, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{
+		Id:          "1",
+		Title:       "The Godfather",
+		ExternalIds: []*gen.ExternalID{{Provider: "imdb", Id: "tt0068646"}, {Provider: "tmdb", Id: "238"}},
+	}})
+	assert.NoError(t, err)
+
+	res, err := h.LookupMetadataByExternalID(ctx, &gen.LookupMetadataByExternalIDRequest{Provider: "tmdb", ExternalId: "238"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.Metadata.Id)
+	assert.Len(t, res.Metadata.ExternalIds, 2)
+
+	_, err = h.LookupMetadataByExternalID(ctx, &gen.LookupMetadataByExternalIDRequest{Provider: "imdb", ExternalId: "tt0071562"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = h.LookupMetadataByExternalID(ctx, &gen.LookupMetadataByExternalIDRequest{Provider: "imdb"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = h.LookupMetadataByExternalID(ctx, &gen.LookupMetadataByExternalIDRequest{Provider: "netflix", ExternalId: "1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{
+		Id:          "2",
+		Title:       "The Godfather Part II",
+		ExternalIds: []*gen.ExternalID{{Provider: "imdb", Id: "tt0068646"}},
+	}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = h.UpdateMetadata(ctx, &gen.UpdateMetadataRequest{
+		Metadata:   &gen.Metadata{Id: "1", ExternalIds: []*gen.ExternalID{{Provider: "imdb", Id: "0068646"}}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"external_ids"}},
+	})
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	if br, ok := st.Details()[0].(*errdetails.BadRequest); assert.True(t, ok) {
+		assert.Equal(t, "metadata.external_ids[0].id", br.FieldViolations[0].Field)
+	}
+}
+
+func TestHandler_GetRelatedMovies(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	ctx := context.Background()
+
+	for _, m := range []*gen.Metadata{{Id: "1", Title: "Toy Story"}, {Id: "2", Title: "Toy Story 2"}} {
+		_, err := h.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: m})
+		assert.NoError(t, err)
+	}
+
+	sequel := &gen.MetadataRelation{MovieId: "2", Type: "sequel_of", RelatedId: "1"}
+	_, err := h.PutMetadataRelation(ctx, &gen.PutMetadataRelationRequest{Relation: sequel})
+	assert.NoError(t, err)
+
+	res, err := h.GetRelatedMovies(ctx, &gen.GetRelatedMoviesRequest{MovieId: "1"})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Related, 1) {
+		assert.True(t, proto.Equal(sequel, res.Related[0].Relation))
+		assert.Equal(t, "Toy Story 2", res.Related[0].Metadata.Title)
+	}
+	res, err = h.GetRelatedMovies(ctx, &gen.GetRelatedMoviesRequest{MovieId: "1", Types: []string{"remake_of"}})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Related)
+
+	_, err = h.PutMetadataRelation(ctx, &gen.PutMetadataRelationRequest{Relation: &gen.MetadataRelation{MovieId: "1", Type: "sequel_of", RelatedId: "2"}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = h.PutMetadataRelation(ctx, &gen.PutMetadataRelationRequest{Relation: &gen.MetadataRelation{MovieId: "1", Type: "spinoff_of", RelatedId: "2"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = h.PutMetadataRelation(ctx, &gen.PutMetadataRelationRequest{Relation: &gen.MetadataRelation{MovieId: "3", Type: "remake_of", RelatedId: "1"}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = h.GetRelatedMovies(ctx, &gen.GetRelatedMoviesRequest{MovieId: "3"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = h.DeleteMetadataRelation(ctx, &gen.DeleteMetadataRelationRequest{Relation: sequel})
+	assert.NoError(t, err)
+	_, err = h.DeleteMetadataRelation(ctx, &gen.DeleteMetadataRelationRequest{Relation: sequel})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
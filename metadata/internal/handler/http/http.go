@@ -1,15 +1,26 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"movieexample.com/metadata/internal/controller/metadata"
 	"movieexample.com/metadata/pkg/model"
+	"movieexample.com/pkg/utilities"
 )
 
+// AuthorHeader names who makes the changes of a write request. It is recorded in the revision history.
+const AuthorHeader = "X-Author"
+
 type Handler struct {
 	// ctrl is a pointer to a metadata.Controller instance, which is used to handle
 	// metadata-related operations in the HTTP handler.
@@ -30,10 +41,16 @@ func New(ctrl *metadata.Controller) *Handler {
 // it returns a 400 Bad Request response. If the metadata is not found, it returns a 404 Not Found
 // response. If there is an error encoding the metadata, it returns a 500 Internal Server Error
 // response. Otherwise, it encodes the metadata as JSON and writes it to the response.
+// An RFC 3339 as_of parameter returns the metadata as it was at that time. The images of the movie
+// are listed alongside the metadata. The title and description are translated into the locale
+// parameter, or else the languages of the Accept-Language header, and the locale of the title is
+// set as Content-Language. Instead of the id parameter, the movie may be named by a slug parameter;
+// a former slug is answered with a 301 Moved Permanently redirect to the current one.
 func (h *Handler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 	id := r.FormValue("id")
+	slug := r.FormValue("slug")
 
-	if id == "" {
+	if (id == "") == (slug == "") {
 		w.WriteHeader(http.StatusBadRequest)
 
 		return
@@ -41,15 +58,141 @@ func (h *Handler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	m, err := h.ctrl.Get(ctx, id)
+	if slug != "" {
+		var err error
+		id, err = h.ctrl.ResolveSlug(ctx, slug)
+		if err != nil && errors.Is(err, metadata.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		} else if err != nil {
+			log.Printf("Failed to resolve slug: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	var m *model.Metadata
+	var asOf time.Time
+	var err error
+	if v := r.FormValue("as_of"); v != "" {
+		var perr error
+		asOf, perr = time.Parse(time.RFC3339, v)
+		if perr != nil {
+			http.Error(w, "invalid as_of", http.StatusBadRequest)
+
+			return
+		}
+		m, err = h.ctrl.GetAsOf(ctx, id, asOf)
+	} else {
+		m, err = h.ctrl.Get(ctx, id)
+	}
 	if err != nil && errors.Is(err, metadata.ErrNotFound) {
 		log.Printf("Repository got err: %v", err)
 		w.WriteHeader(http.StatusNotFound)
 
+		return
+	} else if err != nil {
+		log.Printf("Failed to get metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
 		return
 	}
 
-	if err := json.NewEncoder(w).Encode(m); err != nil {
+	if slug != "" && asOf.IsZero() && m.Slug != slug {
+		redirectToSlug(w, r, m.Slug)
+
+		return
+	}
+
+	m, locale, err := h.ctrl.Localize(ctx, m, requestLocales(r))
+	if err != nil && errors.Is(err, metadata.ErrInvalidLocale) {
+		http.Error(w, "invalid locale", http.StatusBadRequest)
+
+		return
+	} else if err != nil {
+		log.Printf("Failed to localize metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	images, err := h.ctrl.ListImages(ctx, id, asOf)
+	if err != nil {
+		log.Printf("Failed to list images: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+	if locale != "" {
+		w.Header().Set("Content-Language", locale)
+	}
+	w.Header().Set("ETag", etag(m.Version))
+
+	if err := json.NewEncoder(w).Encode(metadataResponse{Metadata: m, Images: images}); err != nil {
+		log.Printf("Failed to encode metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+}
+
+// redirectToSlug permanently redirects a request made with a former slug to the same URL with the
+// current slug.
+func redirectToSlug(w http.ResponseWriter, r *http.Request, slug string) {
+	u := *r.URL
+	q := u.Query()
+	q.Set("slug", slug)
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+}
+
+// requestLocales returns the locales a request prefers, most preferred first: the locale
+// parameter if set, or else the languages of the Accept-Language header.
+func requestLocales(r *http.Request) []string {
+	if locale := r.FormValue("locale"); locale != "" {
+		return []string{locale}
+	}
+
+	return model.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// batchResponse is the body written by BatchGetMetadata.
+type batchResponse struct {
+	Metadata []*model.Metadata `json:"metadata"`
+}
+
+// BatchGetMetadata is an HTTP handler that retrieves the live movies named by the repeated id
+// parameter, ordered by ID and translated as in GetMetadata. Unknown and deleted movies are left
+// out. More than metadata.MaxBatchSize IDs or an invalid locale return a 400 Bad Request response.
+func (h *Handler) BatchGetMetadata(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ms, err := h.ctrl.GetMany(r.Context(), r.Form["id"], requestLocales(r))
+	if err != nil && (errors.Is(err, metadata.ErrBatchTooLarge) || errors.Is(err, metadata.ErrInvalidLocale)) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	} else if err != nil {
+		log.Printf("Failed to get metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if ms == nil {
+		ms = []*model.Metadata{}
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+	if err := json.NewEncoder(w).Encode(batchResponse{Metadata: ms}); err != nil {
 		log.Printf("Failed to encode metadata: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 
@@ -57,24 +200,387 @@ func (h *Handler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// metadataResponse is the body written by GetMetadata: the metadata with its images alongside.
+type metadataResponse struct {
+	*model.Metadata
+	Images []model.Image `json:"images,omitempty"`
+}
+
 // PutMetadata is an HTTP handler that updates the metadata for a given ID. It decodes the request body
 // into a metadata.Metadata struct, and then calls the Put method on the metadata.Controller to update
-// the metadata. If there is an error decoding the request body, it returns a 400 Bad Request response.
-// If there is an error updating the metadata, it returns a 500 Internal Server Error response.
+// the metadata. If the request body is missing or cannot be decoded, or the metadata fails validation,
+// it returns a 400 Bad Request response with a problem details body listing the invalid fields.
+// The write is made conditional with an If-Match header carrying the ETag returned by GetMetadata, or
+// with "If-None-Match: *" to only create new records; if the condition fails it returns a 412
+// Precondition Failed response. If there is an error updating the metadata, it returns a 500 Internal
+// Server Error response. On success the new ETag is set on the response.
 func (h *Handler) PutMetadata(w http.ResponseWriter, r *http.Request) {
-	var m *model.Metadata
+	var m model.Metadata
 
 	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
 		log.Printf("Failed to decode metadata: %v", err)
+		writeProblem(w, http.StatusBadRequest, "Malformed request body", "the request body must be a JSON metadata object", nil)
+
+		return
+	}
+
+	expectedVersion, conditional, err := precondition(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := writeContext(r)
+
+	if conditional {
+		err = h.ctrl.CompareAndPut(ctx, &m, expectedVersion)
+	} else {
+		err = h.ctrl.Put(ctx, &m)
+	}
+	if err != nil && errors.Is(err, metadata.ErrInvalidMetadata) {
+		writeValidationProblem(w, err)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrVersionMismatch) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrExternalIDConflict) {
+		writeProblem(w, http.StatusConflict, "External ID conflict", "an external id belongs to another movie", nil)
+
+		return
+	} else if err != nil {
+		log.Printf("Failed to put metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("ETag", etag(m.Version))
+}
+
+// PatchMetadata is an HTTP handler that partially updates the metadata for the given ID with a JSON
+// merge patch (RFC 7396). Only the members present in the patch are changed; a null member clears the
+// field. Members are validated against the fields of gen.Metadata, so unknown and read-only fields are
+// rejected with a 400 Bad Request response, as is a body that is not a JSON object or values that fail
+// validation, which are reported in a problem details body. The update is made
+// conditional with an If-Match header; if the condition fails it returns a 412 Precondition Failed
+// response. On success it writes the updated metadata as JSON along with its new ETag.
+func (h *Handler) PatchMetadata(w http.ResponseWriter, r *http.Request) {
+	id := r.FormValue("id")
+
+	if id == "" {
 		w.WriteHeader(http.StatusBadRequest)
 
 		return
 	}
 
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/merge-patch+json" && mediaType != "application/json") {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+
+			return
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Failed to read patch: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		http.Error(w, "patch must be a JSON object", http.StatusBadRequest)
+
+		return
+	}
+
+	paths := make([]string, 0, len(members))
+	for k := range members {
+		paths = append(paths, k)
+	}
+	slices.Sort(paths)
+
+	fields, err := model.FieldsFromPaths(paths)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	var patch model.Metadata
+	if err := json.Unmarshal(body, &patch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	expectedVersion, _, err := ifMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	m, err := h.ctrl.Update(writeContext(r), id, &patch, fields, expectedVersion)
+	if err != nil && errors.Is(err, metadata.ErrInvalidMetadata) {
+		writeValidationProblem(w, err)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrVersionMismatch) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrExternalIDConflict) {
+		writeProblem(w, http.StatusConflict, "External ID conflict", "an external id belongs to another movie", nil)
+
+		return
+	} else if err != nil {
+		log.Printf("Failed to patch metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("ETag", etag(m.Version))
+
+	if err := json.NewEncoder(w).Encode(m); err != nil {
+		log.Printf("Failed to encode metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+}
+
+// maxUploadOverhead is the room left for the multipart framing around an uploaded image.
+const maxUploadOverhead = 1 << 20
+
+// UploadImage is an HTTP handler that uploads an image of the movie with the given ID from a
+// multipart/form-data body. The image is read from the "image" file part, and the kind of image
+// from the kind parameter. The id and kind parameters are read from the URL so that the body can
+// be streamed. It responds with 201 Created and the stored image as JSON. Failures are reported
+// as problem details: 404 for an unknown movie, 413 for an image that is too large, 415 for a
+// body that is not multipart or an image format that is not supported, and 400 otherwise.
+func (h *Handler) UploadImage(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	kind := model.ImageKind(r.URL.Query().Get("kind"))
+
+	if id == "" {
+		writeProblem(w, http.StatusBadRequest, "Missing movie", "the id parameter is required", nil)
+
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, metadata.MaxImageSize+maxUploadOverhead)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		writeProblem(w, http.StatusUnsupportedMediaType, "Unsupported media type", "the body must be multipart/form-data", nil)
+
+		return
+	}
+
+	var part *multipart.Part
+	for {
+		part, err = mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			writeProblem(w, http.StatusBadRequest, "Missing image", "the image part is required", nil)
+
+			return
+		} else if err != nil {
+			writeProblem(w, http.StatusBadRequest, "Malformed request body", err.Error(), nil)
+
+			return
+		}
+		if part.FormName() == "image" {
+			break
+		}
+	}
+
+	img, err := h.ctrl.UploadImage(writeContext(r), id, kind, part)
+	var maxBytesErr *http.MaxBytesError
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		writeProblem(w, http.StatusNotFound, "Not found", "metadata not found", nil)
+
+		return
+	} else if err != nil && (errors.Is(err, metadata.ErrImageTooLarge) || errors.As(err, &maxBytesErr)) {
+		writeProblem(w, http.StatusRequestEntityTooLarge, "Image too large", err.Error(), nil)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrUnsupportedImage) {
+		writeProblem(w, http.StatusUnsupportedMediaType, "Unsupported image", "images must be JPEG, PNG, GIF or WebP", nil)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrInvalidImageKind) {
+		writeProblem(w, http.StatusBadRequest, "Invalid image kind", err.Error(), nil)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrImagesDisabled) {
+		writeProblem(w, http.StatusNotImplemented, "Not implemented", err.Error(), nil)
+
+		return
+	} else if err != nil {
+		log.Printf("Failed to upload image: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(img); err != nil {
+		log.Printf("Failed to encode image: %v", err)
+
+		return
+	}
+}
+
+// writeContext returns the request context with the author of the request recorded on it.
+func writeContext(r *http.Request) context.Context {
 	ctx := r.Context()
+	if author := r.Header.Get(AuthorHeader); author != "" {
+		ctx = utilities.WithAuthor(ctx, author)
+	}
 
-	if err := h.ctrl.Put(ctx, m); err != nil {
-		log.Printf("Failed to put metadata: %v", err)
+	return ctx
+}
+
+// etag formats a record version as a strong entity tag.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// precondition reads the expected version of a conditional write from the If-Match or
+// If-None-Match headers. It reports false if the request is unconditional.
+func precondition(r *http.Request) (int64, bool, error) {
+	if r.Header.Get("If-None-Match") == "*" {
+		return 0, true, nil
+	}
+
+	return ifMatch(r)
+}
+
+// ifMatch reads the expected version from the If-Match header.
+// It reports false if the header is not set.
+func ifMatch(r *http.Request) (int64, bool, error) {
+	v := r.Header.Get("If-Match")
+	if v == "" {
+		return 0, false, nil
+	}
+
+	unquoted, err := strconv.Unquote(v)
+	if err != nil {
+		return 0, false, errors.New("invalid If-Match header")
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false, errors.New("invalid If-Match header")
+	}
+
+	return version, true, nil
+}
+
+// searchResponse is the body written by SearchMetadata.
+type searchResponse struct {
+	Results       []model.SearchResult `json:"results"`
+	NextPageToken string               `json:"next_page_token,omitempty"`
+}
+
+// SearchMetadata is an HTTP handler that runs a full-text search over the catalog. The query is
+// read from the q parameter, paging from page_size and page_token. If the query is empty or the
+// paging parameters are invalid, it returns a 400 Bad Request response. Otherwise it writes the
+// ranked results as JSON.
+func (h *Handler) SearchMetadata(w http.ResponseWriter, r *http.Request) {
+	pageSize := 0
+	if v := r.FormValue("page_size"); v != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "invalid page size", http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	res, next, err := h.ctrl.Search(r.Context(), r.FormValue("q"), pageSize, r.FormValue("page_token"))
+	if err != nil && (errors.Is(err, metadata.ErrEmptyQuery) ||
+		errors.Is(err, metadata.ErrInvalidPageToken) || errors.Is(err, metadata.ErrInvalidPageSize)) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	} else if err != nil {
+		log.Printf("Failed to search metadata: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if res == nil {
+		res = []model.SearchResult{}
+	}
+
+	if err := json.NewEncoder(w).Encode(searchResponse{Results: res, NextPageToken: next}); err != nil {
+		log.Printf("Failed to encode search results: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+}
+
+// relatedResponse is the body written by GetRelatedMovies.
+type relatedResponse struct {
+	Related []model.RelatedMovie `json:"related"`
+}
+
+// GetRelatedMovies is an HTTP handler that lists the live movies related to the movie named by the
+// id parameter, in either direction. Repeated type parameters restrict the relations followed,
+// such as type=sequel_of. The related movies are translated as in GetMetadata. If the ID is
+// empty or a type or locale is invalid, it returns a 400 Bad Request response, and if the movie
+// does not exist a 404 Not Found response.
+func (h *Handler) GetRelatedMovies(w http.ResponseWriter, r *http.Request) {
+	id := r.FormValue("id")
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var types []model.RelationType
+	for _, t := range r.Form["type"] {
+		types = append(types, model.RelationType(t))
+	}
+
+	related, err := h.ctrl.GetRelated(r.Context(), id, types, requestLocales(r))
+	if err != nil && (errors.Is(err, metadata.ErrInvalidRelation) || errors.Is(err, metadata.ErrInvalidLocale)) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	} else if err != nil {
+		log.Printf("Failed to get related movies: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if related == nil {
+		related = []model.RelatedMovie{}
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+	if err := json.NewEncoder(w).Encode(relatedResponse{Related: related}); err != nil {
+		log.Printf("Failed to encode related movies: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 
 		return
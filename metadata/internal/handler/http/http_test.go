@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"image"
+	"image/png"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
+	"movieexample.com/metadata/internal/blob/local"
 	"movieexample.com/metadata/internal/controller/metadata"
 	"movieexample.com/metadata/internal/repository/memory"
 	"movieexample.com/metadata/pkg/model"
@@ -117,3 +123,378 @@ func TestHandler_PutMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_SearchMetadata(t *testing.T) {
+	repo := memory.New()
+	h := New(metadata.New(repo))
+	if err := repo.Put(context.Background(), "1", &model.Metadata{
+		ID:          "1",
+		Title:       "Interstellar",
+		Director:    "Christopher Nolan",
+		Description: "Explorers travel through space.",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		wantCode int
+		wantIDs  []string
+	}{
+		{name: "match", target: "/search?q=nolan+space", wantCode: http.StatusOK, wantIDs: []string{"1"}},
+		{name: "no match", target: "/search?q=zombie", wantCode: http.StatusOK, wantIDs: []string{}},
+		{name: "empty query", target: "/search?q=", wantCode: http.StatusBadRequest},
+		{name: "bad page size", target: "/search?q=nolan&page_size=x", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			h.SearchMetadata(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var body searchResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, r := range body.Results {
+				ids = append(ids, r.Metadata.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("results = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestHandler_PutMetadataPrecondition(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+	put := func(header, value string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(&model.Metadata{ID: "1", Title: "title"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodPut, "/metadata", bytes.NewReader(body))
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+		recorder := httptest.NewRecorder()
+		h.PutMetadata(recorder, r)
+		return recorder
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		wantCode int
+		wantETag string
+	}{
+		{name: "create only", header: "If-None-Match", value: "*", wantCode: http.StatusOK, wantETag: `"1"`},
+		{name: "create only on existing", header: "If-None-Match", value: "*", wantCode: http.StatusPreconditionFailed},
+		{name: "matching version", header: "If-Match", value: `"1"`, wantCode: http.StatusOK, wantETag: `"2"`},
+		{name: "stale version", header: "If-Match", value: `"1"`, wantCode: http.StatusPreconditionFailed},
+		{name: "malformed", header: "If-Match", value: "1", wantCode: http.StatusBadRequest},
+		{name: "unconditional", wantCode: http.StatusOK, wantETag: `"3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := put(tt.header, tt.value)
+			if recorder.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			if got := recorder.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func TestHandler_PutMetadataInvalid(t *testing.T) {
+	h := New(metadata.New(memory.New()))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string
+	}{
+		{name: "empty body", body: ""},
+		{name: "malformed", body: "{"},
+		{name: "null", body: "null", wantFields: []string{"id", "title"}},
+		{name: "invalid fields", body: `{"id":"a/b","title":" ","runtime_minutes":-1}`, wantFields: []string{"id", "title", "runtime_minutes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			h.PutMetadata(recorder, httptest.NewRequest(http.MethodPut, "/metadata", strings.NewReader(tt.body)))
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			if ct := recorder.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q, want application/problem+json", ct)
+			}
+
+			var p problem
+			if err := json.NewDecoder(recorder.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+			var fields []string
+			for _, v := range p.InvalidParams {
+				fields = append(fields, v.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("invalid fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestHandler_PatchMetadata(t *testing.T) {
+	repo := memory.New()
+	h := New(metadata.New(repo))
+	err := repo.Put(context.Background(), "1", &model.Metadata{
+		ID:          "1",
+		Title:       "title",
+		Description: "description",
+		Director:    "director",
+		Genres:      []string{"Drama"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patch := func(id, body, ifMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPatch, "/metadata?id="+id, bytes.NewBufferString(body))
+		r.Header.Set("Content-Type", "application/merge-patch+json")
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		recorder := httptest.NewRecorder()
+		h.PatchMetadata(recorder, r)
+		return recorder
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		body     string
+		ifMatch  string
+		wantCode int
+		want     *model.Metadata
+	}{
+		{
+			name:     "set and clear",
+			id:       "1",
+			body:     `{"title": "new title", "description": null}`,
+			wantCode: http.StatusOK,
+			want:     &model.Metadata{ID: "1", Title: "new title", Director: "director", Genres: []string{"Drama"}, Slug: "new-title", Version: 2},
+		},
+		{
+			name:     "replace list",
+			id:       "1",
+			body:     `{"genres": ["Thriller", "Crime"]}`,
+			ifMatch:  `"2"`,
+			wantCode: http.StatusOK,
+			want:     &model.Metadata{ID: "1", Title: "new title", Director: "director", Genres: []string{"Crime", "Thriller"}, Slug: "new-title", Version: 3},
+		},
+		{name: "stale version", id: "1", body: `{"title": "x"}`, ifMatch: `"2"`, wantCode: http.StatusPreconditionFailed},
+		{name: "unknown field", id: "1", body: `{"rating": 5}`, wantCode: http.StatusBadRequest},
+		{name: "read-only field", id: "1", body: `{"version": 7}`, wantCode: http.StatusBadRequest},
+		{name: "not an object", id: "1", body: `["title"]`, wantCode: http.StatusBadRequest},
+		{name: "missing record", id: "2", body: `{"title": "x"}`, wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := patch(tt.id, tt.body, tt.ifMatch)
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			if tt.want == nil {
+				return
+			}
+			var got model.Metadata
+			if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&got, tt.want) {
+				t.Errorf("PatchMetadata() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_UploadImage(t *testing.T) {
+	store, err := local.New(t.TempDir(), "/images")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memory.New()
+	h := New(metadata.New(repo, metadata.WithImageStore(store)))
+	if err := repo.Put(context.Background(), "1", &model.Metadata{ID: "1", Title: "title"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, 300, 450))); err != nil {
+		t.Fatal(err)
+	}
+
+	upload := func(query, field string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, err := mw.CreateFormFile(field, "poster.bin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(data)
+		mw.Close()
+
+		r := httptest.NewRequest(http.MethodPost, "/metadata/images?"+query, &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		h.UploadImage(recorder, r)
+		return recorder
+	}
+
+	recorder := upload("id=1&kind=poster", "image", img.Bytes())
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body)
+	}
+	var got model.Image
+	if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ContentType != "image/png" || got.Width != 300 || got.Height != 450 || len(got.Variants) != 1 {
+		t.Errorf("unexpected image %+v", got)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.GetMetadata(recorder, httptest.NewRequest(http.MethodGet, "/metadata?id=1", nil))
+	var res metadataResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Title != "title" || len(res.Images) != 1 || res.Images[0].URL != got.URL {
+		t.Errorf("unexpected metadata %+v", res)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		field    string
+		data     []byte
+		wantCode int
+	}{
+		{name: "unknown movie", query: "id=2&kind=poster", field: "image", data: img.Bytes(), wantCode: http.StatusNotFound},
+		{name: "missing id", query: "kind=poster", field: "image", data: img.Bytes(), wantCode: http.StatusBadRequest},
+		{name: "invalid kind", query: "id=1&kind=banner", field: "image", data: img.Bytes(), wantCode: http.StatusBadRequest},
+		{name: "missing image", query: "id=1&kind=poster", field: "file", data: img.Bytes(), wantCode: http.StatusBadRequest},
+		{name: "not an image", query: "id=1&kind=poster", field: "image", data: []byte("hello"), wantCode: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if recorder := upload(tt.query, tt.field, tt.data); recorder.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandler_GetMetadataLocalized(t *testing.T) {
+	ctx := context.Background()
+	ctrl := metadata.New(memory.New())
+	h := New(ctrl)
+	if err := ctrl.Put(ctx, &model.Metadata{ID: "1", Title: "Amélie"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.PutTranslation(ctx, "1", &model.Translation{Locale: "en", Title: "Amelie"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		acceptLanguage      string
+		wantCode            int
+		wantTitle           string
+		wantContentLanguage string
+	}{
+		{name: "default", wantCode: http.StatusOK, wantTitle: "Amélie"},
+		{name: "accept language", acceptLanguage: "de;q=0.9, en-GB;q=0.8, *;q=0.1", wantCode: http.StatusOK, wantTitle: "Amelie", wantContentLanguage: "en"},
+		{name: "locale overrides header", query: "&locale=fr", acceptLanguage: "en", wantCode: http.StatusOK, wantTitle: "Amélie"},
+		{name: "malformed header", acceptLanguage: ";;;", wantCode: http.StatusOK, wantTitle: "Amélie"},
+		{name: "invalid locale", query: "&locale=-", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/metadata?id=1"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			recorder := httptest.NewRecorder()
+			h.GetMetadata(recorder, r)
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var res metadataResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", res.Title, tt.wantTitle)
+			}
+			if got := recorder.Header().Get("Content-Language"); got != tt.wantContentLanguage {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantContentLanguage)
+			}
+		})
+	}
+}
+
+func TestHandler_GetMetadataBySlug(t *testing.T) {
+	ctx := context.Background()
+	ctrl := metadata.New(memory.New())
+	h := New(ctrl)
+	if err := ctrl.Put(ctx, &model.Metadata{ID: "1", Title: "Batman Begins 2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctrl.Update(ctx, "1", &model.Metadata{Title: "The Dark Knight"}, []model.Field{model.FieldTitle}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		wantCode     int
+		wantLocation string
+	}{
+		{name: "current slug", query: "slug=the-dark-knight", wantCode: http.StatusOK},
+		{name: "former slug", query: "slug=batman-begins-2&locale=en", wantCode: http.StatusMovedPermanently, wantLocation: "/metadata?locale=en&slug=the-dark-knight"},
+		{name: "unknown slug", query: "slug=batman", wantCode: http.StatusNotFound},
+		{name: "id and slug", query: "id=1&slug=the-dark-knight", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			h.GetMetadata(recorder, httptest.NewRequest(http.MethodGet, "/metadata?"+tt.query, nil))
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			if got := recorder.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
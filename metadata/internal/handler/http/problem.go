@@ -0,0 +1,49 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"movieexample.com/metadata/internal/controller/metadata"
+)
+
+// problem is a problem details body as defined by RFC 9457. InvalidParams lists the fields
+// that failed validation.
+type problem struct {
+	Type          string                    `json:"type"`
+	Title         string                    `json:"title"`
+	Status        int                       `json:"status"`
+	Detail        string                    `json:"detail,omitempty"`
+	InvalidParams []metadata.FieldViolation `json:"invalid_params,omitempty"`
+}
+
+// writeProblem writes an application/problem+json response with the given status.
+func writeProblem(w http.ResponseWriter, status int, title, detail string, violations []metadata.FieldViolation) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(problem{
+		Type:          "about:blank",
+		Title:         title,
+		Status:        status,
+		Detail:        detail,
+		InvalidParams: violations,
+	}); err != nil {
+		log.Printf("Failed to encode problem: %v", err)
+	}
+}
+
+// writeValidationProblem writes a 400 Bad Request problem listing the fields of a
+// *metadata.ValidationError.
+func writeValidationProblem(w http.ResponseWriter, err error) {
+	var verr *metadata.ValidationError
+	if !errors.As(err, &verr) {
+		writeProblem(w, http.StatusBadRequest, "Invalid metadata", err.Error(), nil)
+
+		return
+	}
+
+	writeProblem(w, http.StatusBadRequest, "Invalid metadata", "one or more fields are invalid", verr.Violations)
+}
@@ -0,0 +1,105 @@
+// Package imaging sniffs, decodes and scales the images uploaded to the metadata service.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// ErrUnsupportedFormat is returned for data that is not a JPEG, PNG, GIF or WebP image.
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// Content types of the supported image formats.
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	GIF  = "image/gif"
+	WebP = "image/webp"
+)
+
+// jpegQuality is the quality thumbnails are encoded with.
+const jpegQuality = 85
+
+var extensions = map[string]string{
+	JPEG: ".jpg",
+	PNG:  ".png",
+	GIF:  ".gif",
+	WebP: ".webp",
+}
+
+// Sniff detects the content type of image data from its first bytes, ignoring whatever type
+// the client claims. It returns ErrUnsupportedFormat for anything but the supported formats.
+func Sniff(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := extensions[contentType]; !ok {
+		return "", ErrUnsupportedFormat
+	}
+
+	return contentType, nil
+}
+
+// Extension returns the file extension of a supported content type, including the dot.
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// DecodeConfig returns the dimensions of an image without decoding it.
+func DecodeConfig(data []byte) (image.Config, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return image.Config{}, errors.Join(ErrUnsupportedFormat, err)
+	}
+
+	return cfg, nil
+}
+
+// Decode decodes an image of any supported format.
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Join(ErrUnsupportedFormat, err)
+	}
+
+	return img, nil
+}
+
+// Resize scales an image down to the given width, keeping its aspect ratio.
+func Resize(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	height := max(1, b.Dy()*width/b.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+
+	return dst
+}
+
+// ThumbnailType returns the content type thumbnails of an image are encoded as: PNG for the
+// formats that may carry transparency, JPEG otherwise.
+func ThumbnailType(contentType string) string {
+	if contentType == PNG || contentType == GIF {
+		return PNG
+	}
+
+	return JPEG
+}
+
+// Encode writes an image as JPEG or PNG.
+func Encode(w io.Writer, img image.Image, contentType string) error {
+	switch contentType {
+	case JPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case PNG:
+		return png.Encode(w, img)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
@@ -3,3 +3,16 @@ package repository
 import "errors"
 
 var ErrNotFound = errors.New("not found")
+
+// ErrVersionMismatch is returned by conditional writes when the stored version
+// differs from the expected one.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// ErrExternalIDConflict is returned by writes that would give an external ID to a second record.
+var ErrExternalIDConflict = errors.New("external id already in use")
+
+// ErrSlugTaken is returned when a slug is the current or a former slug of another record.
+var ErrSlugTaken = errors.New("slug already taken")
+
+// ErrRelationCycle is returned by relation writes that would close a loop of relations of one type.
+var ErrRelationCycle = errors.New("relation cycle")
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"movieexample.com/metadata/pkg/model"
+)
+
+// Field weights mirror the setweight classes of the postgres search vector:
+// title is class A, director class B and description class C.
+const (
+	titleWeight       = 1.0
+	directorWeight    = 0.4
+	descriptionWeight = 0.2
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "it": true, "its": true,
+	"of": true, "on": true, "or": true, "the": true, "this": true, "that": true, "to": true,
+	"was": true, "with": true,
+}
+
+// searchIndex is an inverted index over the searchable fields of metadata records.
+// It is not safe for concurrent use; the Repository lock guards it.
+type searchIndex struct {
+	// postings maps a term to the weighted frequency of the term in every record containing it.
+	postings map[string]map[string]float64
+	// terms maps a record ID to its distinct terms so the record can be removed again.
+	terms map[string][]string
+}
+
+type scoredID struct {
+	id    string
+	score float64
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: map[string]map[string]float64{},
+		terms:    map[string][]string{},
+	}
+}
+
+// add indexes m, replacing any previous version of the record.
+func (ix *searchIndex) add(m *model.Metadata) {
+	ix.remove(m.ID)
+
+	freq := map[string]float64{}
+	for _, f := range []struct {
+		text   string
+		weight float64
+	}{
+		{m.Title, titleWeight},
+		{m.Director, directorWeight},
+		{m.Description, descriptionWeight},
+	} {
+		for _, t := range tokenize(f.text) {
+			freq[t] += f.weight
+		}
+	}
+
+	terms := make([]string, 0, len(freq))
+	for t, tf := range freq {
+		if ix.postings[t] == nil {
+			ix.postings[t] = map[string]float64{}
+		}
+		ix.postings[t][m.ID] = tf
+		terms = append(terms, t)
+	}
+	ix.terms[m.ID] = terms
+}
+
+func (ix *searchIndex) remove(id string) {
+	for _, t := range ix.terms[id] {
+		delete(ix.postings[t], id)
+		if len(ix.postings[t]) == 0 {
+			delete(ix.postings, t)
+		}
+	}
+	delete(ix.terms, id)
+}
+
+// search returns the IDs of records containing every term of the query, ranked by TF-IDF
+// with ties broken by ID.
+func (ix *searchIndex) search(query string) []scoredID {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	slices.Sort(terms)
+	terms = slices.Compact(terms)
+
+	n := float64(len(ix.terms))
+	scores := map[string]float64{}
+	for i, t := range terms {
+		docs := ix.postings[t]
+		if len(docs) == 0 {
+			return nil
+		}
+		idf := math.Log(1 + n/float64(len(docs)))
+		next := map[string]float64{}
+		for id, tf := range docs {
+			score, ok := scores[id]
+			if i > 0 && !ok {
+				continue
+			}
+			next[id] = score + math.Log1p(tf)*idf
+		}
+		scores = next
+	}
+
+	res := make([]scoredID, 0, len(scores))
+	for id, score := range scores {
+		res = append(res, scoredID{id: id, score: score})
+	}
+	slices.SortFunc(res, func(a, b scoredID) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), strings.Compare(a.id, b.id))
+	})
+
+	return res
+}
+
+// tokenize splits s into lower-cased, stemmed terms, dropping stop words.
+func tokenize(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	res := make([]string, 0, len(words))
+	for _, w := range words {
+		if stopWords[w] {
+			continue
+		}
+		res = append(res, stem(w))
+	}
+
+	return res
+}
+
+// stem strips a few common English suffixes so that e.g. "spies" matches "spy"
+// and "dreaming" matches "dream". It is deliberately much simpler than a real stemmer.
+func stem(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case len(w) > 5 && strings.HasSuffix(w, "ing"):
+		return w[:len(w)-3]
+	case len(w) > 4 && strings.HasSuffix(w, "ed"):
+		return w[:len(w)-2]
+	case len(w) > 3 && strings.HasSuffix(w, "s") &&
+		!strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		return w[:len(w)-1]
+	default:
+		return w
+	}
+}
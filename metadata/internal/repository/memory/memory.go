@@ -1,23 +1,51 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"movieexample.com/metadata/internal/repository"
 	"movieexample.com/metadata/pkg/model"
+	"movieexample.com/pkg/utilities"
 )
 
 type Repository struct {
 	sync.RWMutex
-	data map[string]*model.Metadata
+	data  map[string]*model.Metadata
+	index *searchIndex
+	// deleted holds the tombstones of soft deleted records, keyed by ID.
+	deleted map[string]time.Time
+	// revisions holds the history of every record, oldest first, keyed by ID.
+	revisions map[string][]*model.Revision
+	// images holds the images of every movie, oldest first, keyed by movie ID.
+	images map[string][]model.Image
+	// translations holds the translations of every movie, keyed by movie ID and locale.
+	translations map[string]map[string]model.Translation
+	// externalIDs indexes the records by their external IDs, including soft deleted ones.
+	externalIDs map[model.ExternalID]string
+	// slugs maps the current and former slugs of the records to their IDs.
+	slugs map[string]string
+	// relations holds the links between the records.
+	relations map[model.Relation]struct{}
 }
 
 // New returns a new in-memory repository for storing Metadata.
 func New() *Repository {
 	return &Repository{
-		data: map[string]*model.Metadata{},
+		data:         map[string]*model.Metadata{},
+		index:        newSearchIndex(),
+		deleted:      map[string]time.Time{},
+		revisions:    map[string][]*model.Revision{},
+		images:       map[string][]model.Image{},
+		translations: map[string]map[string]model.Translation{},
+		externalIDs:  map[model.ExternalID]string{},
+		slugs:        map[string]string{},
+		relations:    map[model.Relation]struct{}{},
 	}
 }
 
@@ -35,19 +63,652 @@ func (r *Repository) Get(ctx context.Context, id string) (*model.Metadata, error
 
 	m, ok := r.data[id]
 
-	if !ok {
+	if !ok || r.isDeleted(id) {
 		return nil, repository.ErrNotFound
 	}
 
 	return m, nil
 }
 
+// GetMany returns the live records among ids, ordered by ID. Unknown and deleted IDs are skipped.
+func (r *Repository) GetMany(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	_, span := otel.Tracer("").Start(ctx, "GetManyMemoryRepo")
+	defer span.End()
+	r.RLock()
+	defer r.RUnlock()
+
+	var res []*model.Metadata
+	for _, id := range repository.UniqueSorted(ids) {
+		if m, ok := r.data[id]; ok && !r.isDeleted(id) {
+			res = append(res, m)
+		}
+	}
+
+	return res, nil
+}
+
 // Put stores the given Metadata in the in-memory repository, keyed by the Metadata's ID.
-// If the Metadata already exists, it will be overwritten.
-func (r *Repository) Put(_ context.Context, id string, m *model.Metadata) error {
+// If the Metadata already exists, it will be overwritten and its version bumped.
+// The new version and slug are written back to m.Version and m.Slug.
+func (r *Repository) Put(ctx context.Context, id string, m *model.Metadata) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.checkExternalIDs(id, m.ExternalIDs); err != nil {
+		return err
+	}
+	slug, err := r.slugFor(id, m, nil)
+	if err != nil {
+		return err
+	}
+	r.store(ctx, id, m, slug)
+
+	return nil
+}
+
+// PutMany stores a batch of Metadata under a single lock, as Put would one by one.
+func (r *Repository) PutMany(ctx context.Context, ms []*model.Metadata) error {
+	r.Lock()
+	defer r.Unlock()
+
+	claimed := map[model.ExternalID]string{}
+	claimedSlugs := map[string]string{}
+	slugs := make([]string, len(ms))
+	for i, m := range ms {
+		if err := r.checkExternalIDs(m.ID, m.ExternalIDs); err != nil {
+			return err
+		}
+		for _, e := range m.ExternalIDs {
+			if owner, ok := claimed[e]; ok && owner != m.ID {
+				return repository.ErrExternalIDConflict
+			}
+			claimed[e] = m.ID
+		}
+		slug, err := r.slugFor(m.ID, m, claimedSlugs)
+		if err != nil {
+			return err
+		}
+		slugs[i] = slug
+		claimedSlugs[slug] = m.ID
+	}
+	for i, m := range ms {
+		r.store(ctx, m.ID, m, slugs[i])
+	}
+
+	return nil
+}
+
+// CompareAndPut stores the given Metadata only if the stored version equals expectedVersion,
+// where 0 means the record must not exist yet. Otherwise it returns repository.ErrVersionMismatch.
+func (r *Repository) CompareAndPut(ctx context.Context, id string, m *model.Metadata, expectedVersion int64) error {
+	r.Lock()
+	defer r.Unlock()
+
+	var current int64
+	if old, ok := r.data[id]; ok {
+		current = old.Version
+	}
+	if current != expectedVersion {
+		return repository.ErrVersionMismatch
+	}
+	if err := r.checkExternalIDs(id, m.ExternalIDs); err != nil {
+		return err
+	}
+	slug, err := r.slugFor(id, m, nil)
+	if err != nil {
+		return err
+	}
+	r.store(ctx, id, m, slug)
+
+	return nil
+}
+
+// Update copies the given fields from patch onto the stored record and stores it with the next version.
+// A non-zero expectedVersion must match the stored version, otherwise it returns repository.ErrVersionMismatch.
+func (r *Repository) Update(ctx context.Context, id string, patch *model.Metadata, fields []model.Field, expectedVersion int64) (*model.Metadata, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	old, ok := r.data[id]
+	if !ok || r.isDeleted(id) {
+		return nil, repository.ErrNotFound
+	}
+	if expectedVersion != 0 && old.Version != expectedVersion {
+		return nil, repository.ErrVersionMismatch
+	}
+
+	m := *old
+	model.ApplyFields(&m, patch, fields)
+	if err := r.checkExternalIDs(id, m.ExternalIDs); err != nil {
+		return nil, err
+	}
+	slug, err := r.slugFor(id, &m, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, id, &m, slug)
+
+	return r.data[id], nil
+}
+
+// slugFor returns the slug m is to be stored with, see repository.AssignSlug. Slugs in claimed
+// are taken by the records they map to, as if those were stored already.
+// The caller must hold the write lock.
+func (r *Repository) slugFor(id string, m *model.Metadata, claimed map[string]string) (string, error) {
+	var current string
+	if old, ok := r.data[id]; ok {
+		current = old.Slug
+	}
+
+	return repository.AssignSlug(current, m.Title, m.ReleaseDate, func(slug string) (bool, error) {
+		owner, ok := r.slugs[slug]
+		if !ok {
+			owner, ok = claimed[slug]
+		}
+		return !ok || owner == id, nil
+	})
+}
+
+// store writes m with the next version and the given slug, keeping the former slug as a
+// redirect, and records the write as a revision. The caller must hold the write lock.
+func (r *Repository) store(ctx context.Context, id string, m *model.Metadata, slug string) {
+	var version int64 = 1
+	if old, ok := r.data[id]; ok {
+		version = old.Version + 1
+	}
+	m.Version = version
+	m.Slug = slug
+
+	if old, ok := r.data[id]; ok {
+		r.unindexExternalIDs(id, old)
+	}
+	r.slugs[slug] = id
+	r.data[id] = repository.Normalize(m)
+	r.index.add(r.data[id])
+	for _, e := range r.data[id].ExternalIDs {
+		r.externalIDs[e] = id
+	}
+	delete(r.deleted, id)
+
+	var prev *model.Metadata
+	if history := r.revisions[id]; len(history) > 0 {
+		prev = history[len(history)-1].Snapshot
+	}
+	r.revisions[id] = append(r.revisions[id], &model.Revision{
+		MovieID:   id,
+		Version:   version,
+		Author:    utilities.AuthorFromContext(ctx),
+		CreatedAt: time.Now().UTC(),
+		Changes:   model.Diff(prev, r.data[id]),
+		Snapshot:  r.data[id],
+	})
+}
+
+// GetAsOf returns the snapshot of the last revision of the record made at or before asOf.
+func (r *Repository) GetAsOf(_ context.Context, id string, asOf time.Time) (*model.Metadata, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	history := r.revisions[id]
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].CreatedAt.After(asOf) {
+			return history[i].Snapshot, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+// GetRevision returns the revision of the record that produced the given version.
+func (r *Repository) GetRevision(_ context.Context, id string, version int64) (*model.Revision, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, rev := range r.revisions[id] {
+		if rev.Version == version {
+			return rev, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+// ListRevisions returns at most limit revisions of the record, newest first, skipping the first offset ones.
+func (r *Repository) ListRevisions(_ context.Context, id string, offset, limit int) ([]*model.Revision, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	history := r.revisions[id]
+	if offset >= len(history) {
+		return nil, nil
+	}
+	res := slices.Clone(history[:len(history)-offset])
+	slices.Reverse(res)
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// PutImage records an image of a movie. It returns repository.ErrNotFound if the movie does not exist.
+func (r *Repository) PutImage(_ context.Context, img *model.Image) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.data[img.MovieID]; !ok {
+		return repository.ErrNotFound
+	}
+	img.CreatedAt = time.Now().UTC()
+	stored := *img
+	stored.Variants = slices.Clone(img.Variants)
+	r.images[img.MovieID] = append(r.images[img.MovieID], stored)
+
+	return nil
+}
+
+// ListImages returns the images of a movie, oldest first.
+func (r *Repository) ListImages(_ context.Context, movieID string) ([]model.Image, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	res := slices.Clone(r.images[movieID])
+	for i := range res {
+		res[i].Variants = slices.Clone(res[i].Variants)
+	}
+
+	return res, nil
+}
+
+// PutTranslation adds or replaces the translation of a movie into t.Locale.
+// It returns repository.ErrNotFound if the movie does not exist.
+func (r *Repository) PutTranslation(_ context.Context, movieID string, t model.Translation) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.data[movieID]; !ok {
+		return repository.ErrNotFound
+	}
+	if r.translations[movieID] == nil {
+		r.translations[movieID] = map[string]model.Translation{}
+	}
+	r.translations[movieID][t.Locale] = t
+
+	return nil
+}
+
+// DeleteTranslation removes the translation of a movie into locale.
+// It returns repository.ErrNotFound if there is no such translation.
+func (r *Repository) DeleteTranslation(_ context.Context, movieID, locale string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.translations[movieID][locale]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.translations[movieID], locale)
+
+	return nil
+}
+
+// ListTranslations returns the translations of a movie ordered by locale.
+func (r *Repository) ListTranslations(_ context.Context, movieID string) ([]model.Translation, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	res := make([]model.Translation, 0, len(r.translations[movieID]))
+	for _, t := range r.translations[movieID] {
+		res = append(res, t)
+	}
+	slices.SortFunc(res, func(a, b model.Translation) int {
+		return strings.Compare(a.Locale, b.Locale)
+	})
+
+	return res, nil
+}
+
+// ListTranslationsForMovies returns the translations of each of the movies that has any, keyed by
+// movie ID and ordered by locale.
+func (r *Repository) ListTranslationsForMovies(ctx context.Context, movieIDs []string) (map[string][]model.Translation, error) {
+	res := make(map[string][]model.Translation)
+	for _, id := range movieIDs {
+		translations, err := r.ListTranslations(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(translations) > 0 {
+			res[id] = translations
+		}
+	}
+
+	return res, nil
+}
+
+// Delete soft deletes the record with the given id, leaving a tombstone behind.
+// It returns repository.ErrNotFound if there is no live record with that id.
+func (r *Repository) Delete(_ context.Context, id string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.data[id]; !ok || r.isDeleted(id) {
+		return repository.ErrNotFound
+	}
+	r.deleted[id] = time.Now()
+	r.index.remove(id)
+
+	return nil
+}
+
+// Undelete removes the tombstone of a soft deleted record.
+// It returns repository.ErrNotFound if there is no deleted record with that id.
+func (r *Repository) Undelete(_ context.Context, id string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.isDeleted(id) {
+		return repository.ErrNotFound
+	}
+	delete(r.deleted, id)
+	r.index.add(r.data[id])
+
+	return nil
+}
+
+// Purge permanently removes the record with the given id, including its tombstone and history.
+// It returns repository.ErrNotFound if there is no record with that id.
+func (r *Repository) Purge(_ context.Context, id string) error {
 	r.Lock()
 	defer r.Unlock()
-	r.data[id] = m
+
+	m, ok := r.data[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	r.unindexExternalIDs(id, m)
+	for slug, owner := range r.slugs {
+		if owner == id {
+			delete(r.slugs, slug)
+		}
+	}
+	for rel := range r.relations {
+		if rel.MovieID == id || rel.RelatedID == id {
+			delete(r.relations, rel)
+		}
+	}
+	delete(r.data, id)
+	delete(r.deleted, id)
+	delete(r.revisions, id)
+	delete(r.images, id)
+	delete(r.translations, id)
+	r.index.remove(id)
 
 	return nil
 }
+
+// GetByExternalID retrieves the live record that has the given external ID.
+func (r *Repository) GetByExternalID(ctx context.Context, e model.ExternalID) (*model.Metadata, error) {
+	_, span := otel.Tracer("").Start(ctx, "GetByExternalIDMemoryRepo")
+	defer span.End()
+	r.RLock()
+	defer r.RUnlock()
+
+	id, ok := r.externalIDs[e]
+	if !ok || r.isDeleted(id) {
+		return nil, repository.ErrNotFound
+	}
+
+	return r.data[id], nil
+}
+
+// ResolveSlug returns the ID of the record that has slug as its current or a former slug.
+func (r *Repository) ResolveSlug(_ context.Context, slug string) (string, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	id, ok := r.slugs[slug]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+
+	return id, nil
+}
+
+// PutRelation links two records. It returns repository.ErrNotFound if either does not exist and
+// repository.ErrRelationCycle if the related record already leads back to the record through
+// relations of the same type.
+func (r *Repository) PutRelation(_ context.Context, rel model.Relation) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.data[rel.MovieID]; !ok {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.data[rel.RelatedID]; !ok {
+		return repository.ErrNotFound
+	}
+	if r.reaches(rel.RelatedID, rel.MovieID, rel.Type) {
+		return repository.ErrRelationCycle
+	}
+	r.relations[rel] = struct{}{}
+
+	return nil
+}
+
+// reaches reports whether to can be reached from from by following relations of type t forward.
+// The caller must hold the lock.
+func (r *Repository) reaches(from, to string, t model.RelationType) bool {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			return true
+		}
+		for rel := range r.relations {
+			if rel.Type != t || rel.MovieID != id || seen[rel.RelatedID] {
+				continue
+			}
+			seen[rel.RelatedID] = true
+			queue = append(queue, rel.RelatedID)
+		}
+	}
+
+	return false
+}
+
+// DeleteRelation removes a link between two records.
+// It returns repository.ErrNotFound if there is no such relation.
+func (r *Repository) DeleteRelation(_ context.Context, rel model.Relation) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.relations[rel]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.relations, rel)
+
+	return nil
+}
+
+// ListRelations returns the relations from and to a record, ordered by type, movie ID and related ID.
+func (r *Repository) ListRelations(_ context.Context, id string) ([]model.Relation, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	var res []model.Relation
+	for rel := range r.relations {
+		if rel.MovieID == id || rel.RelatedID == id {
+			res = append(res, rel)
+		}
+	}
+	slices.SortFunc(res, func(a, b model.Relation) int {
+		return cmp.Or(
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.MovieID, b.MovieID),
+			cmp.Compare(a.RelatedID, b.RelatedID),
+		)
+	})
+
+	return res, nil
+}
+
+// checkExternalIDs returns repository.ErrExternalIDConflict if any of ids belongs to a record
+// other than id. The caller must hold the lock.
+func (r *Repository) checkExternalIDs(id string, ids []model.ExternalID) error {
+	for _, e := range ids {
+		if owner, ok := r.externalIDs[e]; ok && owner != id {
+			return repository.ErrExternalIDConflict
+		}
+	}
+
+	return nil
+}
+
+// unindexExternalIDs removes the external IDs of the record m stored under id from the index.
+// The caller must hold the write lock.
+func (r *Repository) unindexExternalIDs(id string, m *model.Metadata) {
+	for _, e := range m.ExternalIDs {
+		if r.externalIDs[e] == id {
+			delete(r.externalIDs, e)
+		}
+	}
+}
+
+func (r *Repository) isDeleted(id string) bool {
+	_, ok := r.deleted[id]
+	return ok
+}
+
+// List returns at most limit records matching filter, skipping the first offset ones.
+// Records are ordered by sort with ties broken by ID, so the order is stable across calls.
+func (r *Repository) List(ctx context.Context, filter model.Filter, sort model.SortOrder, offset, limit int) ([]*model.Metadata, error) {
+	_, span := otel.Tracer("").Start(ctx, "ListMemoryRepo")
+	defer span.End()
+	r.RLock()
+	defer r.RUnlock()
+
+	var res []*model.Metadata
+	for id, m := range r.data {
+		if !r.isDeleted(id) && matches(m, filter) {
+			res = append(res, m)
+		}
+	}
+
+	slices.SortFunc(res, func(a, b *model.Metadata) int {
+		return cmp.Or(compareBy(a, b, sort), strings.Compare(a.ID, b.ID))
+	})
+
+	if offset >= len(res) {
+		return nil, nil
+	}
+	res = res[offset:]
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// ListAfter returns at most limit live records whose ID sorts after afterID, ordered by ID.
+func (r *Repository) ListAfter(ctx context.Context, afterID string, limit int) ([]*model.Metadata, error) {
+	_, span := otel.Tracer("").Start(ctx, "ListAfterMemoryRepo")
+	defer span.End()
+	r.RLock()
+	defer r.RUnlock()
+
+	var res []*model.Metadata
+	for id, m := range r.data {
+		if id > afterID && !r.isDeleted(id) {
+			res = append(res, m)
+		}
+	}
+	slices.SortFunc(res, func(a, b *model.Metadata) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// Search returns the records containing every term of query, ranked by TF-IDF over the
+// title, director and description, skipping the first offset results.
+func (r *Repository) Search(ctx context.Context, query string, offset, limit int) ([]model.SearchResult, error) {
+	_, span := otel.Tracer("").Start(ctx, "SearchMemoryRepo")
+	defer span.End()
+	r.RLock()
+	defer r.RUnlock()
+
+	hits := r.index.search(query)
+	if offset >= len(hits) {
+		return nil, nil
+	}
+	hits = hits[offset:]
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	res := make([]model.SearchResult, 0, len(hits))
+	for _, h := range hits {
+		res = append(res, model.SearchResult{Metadata: r.data[h.id], Score: h.score})
+	}
+
+	return res, nil
+}
+
+func matches(m *model.Metadata, f model.Filter) bool {
+	if f.Director != "" && !strings.EqualFold(m.Director, f.Director) {
+		return false
+	}
+	if f.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(m.Title), strings.ToLower(f.TitlePrefix)) {
+		return false
+	}
+	if f.Genre != "" && !slices.Contains(m.Genres, f.Genre) {
+		return false
+	}
+	if f.ReleaseYearFrom != 0 && (m.ReleaseDate.IsZero() || m.ReleaseDate.Year() < f.ReleaseYearFrom) {
+		return false
+	}
+	if f.ReleaseYearTo != 0 && (m.ReleaseDate.IsZero() || m.ReleaseDate.Year() > f.ReleaseYearTo) {
+		return false
+	}
+
+	return true
+}
+
+// compareBy orders two records by the sort key, placing records without a value last
+// like postgres does with NULLS LAST.
+func compareBy(a, b *model.Metadata, sort model.SortOrder) int {
+	switch sort {
+	case model.SortByTitle, model.SortByTitleDesc:
+		if c := compareMissing(a.Title == "", b.Title == ""); c != 0 || a.Title == "" {
+			return c
+		}
+		if sort == model.SortByTitleDesc {
+			return strings.Compare(b.Title, a.Title)
+		}
+		return strings.Compare(a.Title, b.Title)
+	case model.SortByReleaseDate, model.SortByReleaseDateDesc:
+		if c := compareMissing(a.ReleaseDate.IsZero(), b.ReleaseDate.IsZero()); c != 0 || a.ReleaseDate.IsZero() {
+			return c
+		}
+		if sort == model.SortByReleaseDateDesc {
+			return b.ReleaseDate.Compare(a.ReleaseDate)
+		}
+		return a.ReleaseDate.Compare(b.ReleaseDate)
+	default:
+		return 0
+	}
+}
+
+func compareMissing(aMissing, bMissing bool) int {
+	switch {
+	case aMissing == bMissing:
+		return 0
+	case aMissing:
+		return 1
+	default:
+		return -1
+	}
+}
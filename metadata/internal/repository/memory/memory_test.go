@@ -2,10 +2,16 @@ package memory
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"movieexample.com/metadata/internal/repository"
 	"movieexample.com/metadata/pkg/model"
+	"movieexample.com/pkg/utilities"
 )
 
 func TestRepository_Get(t *testing.T) {
@@ -51,7 +57,15 @@ func TestRepository_Put(t *testing.T) {
 		{
 			name: "test1",
 			r: &Repository{
-				data: map[string]*model.Metadata{},
+				data:         map[string]*model.Metadata{},
+				index:        newSearchIndex(),
+				deleted:      map[string]time.Time{},
+				revisions:    map[string][]*model.Revision{},
+				images:       map[string][]model.Image{},
+				translations: map[string]map[string]model.Translation{},
+				externalIDs:  map[model.ExternalID]string{},
+				slugs:        map[string]string{},
+				relations:    map[model.Relation]struct{}{},
 			},
 			args: args{
 				in0: context.Background(),
@@ -65,7 +79,15 @@ func TestRepository_Put(t *testing.T) {
 		{
 			name: "test2",
 			r: &Repository{
-				data: map[string]*model.Metadata{},
+				data:         map[string]*model.Metadata{},
+				index:        newSearchIndex(),
+				deleted:      map[string]time.Time{},
+				revisions:    map[string][]*model.Revision{},
+				images:       map[string][]model.Image{},
+				translations: map[string]map[string]model.Translation{},
+				externalIDs:  map[model.ExternalID]string{},
+				slugs:        map[string]string{},
+				relations:    map[model.Relation]struct{}{},
 			},
 			args: args{
 				in0: context.Background(),
@@ -135,3 +157,452 @@ func TestRepository_PutAndGet(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_PutNormalizes(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	m := &model.Metadata{ID: "1", Title: "test-movie", Genres: []string{"drama", "comedy", "drama"}}
+	if err := r.Put(ctx, m.ID, m); err != nil {
+		t.Fatalf("Repository.Put() error = %v", err)
+	}
+	got, err := r.Get(ctx, m.ID)
+	if err != nil {
+		t.Fatalf("Repository.Get() error = %v", err)
+	}
+	if want := []string{"comedy", "drama"}; !reflect.DeepEqual(got.Genres, want) {
+		t.Errorf("Repository.Get() genres = %v, want %v", got.Genres, want)
+	}
+}
+
+func TestRepository_Slugs(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+
+	const writers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := string(rune('1' + i))
+			if err := r.Put(ctx, id, &model.Metadata{ID: id, Title: "Heat"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for i := 0; i < writers; i++ {
+		m, err := r.Get(ctx, string(rune('1'+i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[m.Slug] {
+			t.Errorf("slug %q was given to two records", m.Slug)
+		}
+		seen[m.Slug] = true
+	}
+
+	batch := []*model.Metadata{{ID: "a", Title: "Ronin"}, {ID: "b", Title: "Ronin"}}
+	if err := r.PutMany(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].Slug != "ronin" || batch[1].Slug != "ronin-2" {
+		t.Errorf("Repository.PutMany() slugs = %q and %q, want ronin and ronin-2", batch[0].Slug, batch[1].Slug)
+	}
+
+	// a new title moves the record to a new slug and keeps the old one as a redirect
+	m := &model.Metadata{ID: "a", Title: "Ronin Returns"}
+	if err := r.Put(ctx, m.ID, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Slug != "ronin-returns" {
+		t.Errorf("Repository.Put() slug = %q, want ronin-returns", m.Slug)
+	}
+	if id, err := r.ResolveSlug(ctx, "ronin"); err != nil || id != "a" {
+		t.Errorf("Repository.ResolveSlug() = %q, %v, want a", id, err)
+	}
+}
+
+func TestRepository_List(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	date := func(y int) time.Time { return time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC) }
+	for _, m := range []*model.Metadata{
+		{ID: "1", Title: "Memento", Director: "Christopher Nolan", Genres: []string{"thriller"}, ReleaseDate: date(2000)},
+		{ID: "2", Title: "Inception", Director: "Christopher Nolan", Genres: []string{"sci-fi"}, ReleaseDate: date(2010)},
+		{ID: "3", Title: "Interstellar", Director: "Christopher Nolan", Genres: []string{"sci-fi"}, ReleaseDate: date(2014)},
+		{ID: "4", Title: "Arrival", Director: "Denis Villeneuve", Genres: []string{"sci-fi"}},
+		{ID: "5", Title: "Inception", Director: "Someone Else"},
+	} {
+		if err := r.Put(ctx, m.ID, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(res []*model.Metadata) []string {
+		var out []string
+		for _, m := range res {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		filter model.Filter
+		sort   model.SortOrder
+		offset int
+		limit  int
+		want   []string
+	}{
+		{name: "all by id", sort: model.SortByID, limit: 10, want: []string{"1", "2", "3", "4", "5"}},
+		{name: "title ties broken by id", sort: model.SortByTitle, limit: 10, want: []string{"4", "2", "5", "3", "1"}},
+		{name: "title desc", sort: model.SortByTitleDesc, limit: 10, want: []string{"1", "3", "2", "5", "4"}},
+		{name: "release date missing last", sort: model.SortByReleaseDateDesc, limit: 10, want: []string{"3", "2", "1", "4", "5"}},
+		{name: "paged", sort: model.SortByID, offset: 2, limit: 2, want: []string{"3", "4"}},
+		{name: "past the end", sort: model.SortByID, offset: 10, limit: 2, want: nil},
+		{name: "director", filter: model.Filter{Director: "christopher nolan"}, sort: model.SortByID, limit: 10, want: []string{"1", "2", "3"}},
+		{name: "title prefix", filter: model.Filter{TitlePrefix: "in"}, sort: model.SortByID, limit: 10, want: []string{"2", "3", "5"}},
+		{name: "genre", filter: model.Filter{Genre: "sci-fi"}, sort: model.SortByID, limit: 10, want: []string{"2", "3", "4"}},
+		{name: "release years", filter: model.Filter{ReleaseYearFrom: 2005, ReleaseYearTo: 2012}, sort: model.SortByID, limit: 10, want: []string{"2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := r.List(ctx, tt.filter, tt.sort, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatalf("Repository.List() error = %v", err)
+			}
+			if got := ids(res); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repository.List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_Search(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	for _, m := range []*model.Metadata{
+		{ID: "1", Title: "Interstellar", Director: "Christopher Nolan", Description: "Explorers travel through space to save humanity."},
+		{ID: "2", Title: "Inception", Director: "Christopher Nolan", Description: "A thief steals secrets through dreams."},
+		{ID: "3", Title: "Space Odyssey", Director: "Stanley Kubrick", Description: "Humanity finds a mysterious monolith in space."},
+		{ID: "4", Title: "Spies Like Us", Director: "John Landis", Description: "Two bumbling spies."},
+	} {
+		if err := r.Put(ctx, m.ID, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(res []model.SearchResult) []string {
+		var out []string
+		for _, sr := range res {
+			out = append(out, sr.Metadata.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "all terms must match", query: "nolan space", want: []string{"1"}},
+		{name: "title ranks above description", query: "space", want: []string{"3", "1"}},
+		{name: "stemming", query: "spy", want: []string{"4"}},
+		{name: "case and punctuation", query: "INCEPTION!", want: []string{"2"}},
+		{name: "stop words only", query: "the of", want: nil},
+		{name: "no match", query: "zombie", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := r.Search(ctx, tt.query, 0, 10)
+			if err != nil {
+				t.Fatalf("Repository.Search() error = %v", err)
+			}
+			if got := ids(res); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repository.Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// re-putting a record replaces its terms in the index
+	if err := r.Put(ctx, "4", &model.Metadata{ID: "4", Title: "Tootsie"}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := r.Search(ctx, "spies", 0, 10)
+	if err != nil || len(res) != 0 {
+		t.Errorf("Repository.Search() = %v, %v after re-put, want no results", ids(res), err)
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	m := &model.Metadata{ID: "1", Title: "Memento"}
+	if err := r.Put(ctx, m.ID, m); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Repository.Delete() error = %v", err)
+	}
+	if _, err := r.Get(ctx, "1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.Get() error = %v after delete, want %v", err, repository.ErrNotFound)
+	}
+	if res, _ := r.List(ctx, model.Filter{}, model.SortByID, 0, 10); len(res) != 0 {
+		t.Errorf("Repository.List() = %v after delete, want none", res)
+	}
+	if res, _ := r.Search(ctx, "memento", 0, 10); len(res) != 0 {
+		t.Errorf("Repository.Search() = %v after delete, want none", res)
+	}
+	if err := r.Delete(ctx, "1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.Delete() error = %v on deleted record, want %v", err, repository.ErrNotFound)
+	}
+
+	if err := r.Undelete(ctx, "1"); err != nil {
+		t.Fatalf("Repository.Undelete() error = %v", err)
+	}
+	if got, err := r.Get(ctx, "1"); err != nil || !reflect.DeepEqual(got, m) {
+		t.Errorf("Repository.Get() = %v, %v after undelete, want %v", got, err, m)
+	}
+	if res, _ := r.Search(ctx, "memento", 0, 10); len(res) != 1 {
+		t.Errorf("Repository.Search() = %v after undelete, want one result", res)
+	}
+	if err := r.Undelete(ctx, "1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.Undelete() error = %v on live record, want %v", err, repository.ErrNotFound)
+	}
+
+	if err := r.Delete(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Purge(ctx, "1"); err != nil {
+		t.Fatalf("Repository.Purge() error = %v", err)
+	}
+	if err := r.Undelete(ctx, "1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.Undelete() error = %v after purge, want %v", err, repository.ErrNotFound)
+	}
+	if err := r.Purge(ctx, "1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.Purge() error = %v on missing record, want %v", err, repository.ErrNotFound)
+	}
+}
+
+func TestRepository_CompareAndPut(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+
+	m := &model.Metadata{ID: "1", Title: "first"}
+	if err := r.CompareAndPut(ctx, m.ID, m, 0); err != nil {
+		t.Fatalf("Repository.CompareAndPut() error = %v", err)
+	}
+	if m.Version != 1 {
+		t.Errorf("Version = %d after create, want 1", m.Version)
+	}
+	if err := r.CompareAndPut(ctx, m.ID, &model.Metadata{ID: "1"}, 0); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("Repository.CompareAndPut() error = %v on existing record, want %v", err, repository.ErrVersionMismatch)
+	}
+	if err := r.CompareAndPut(ctx, m.ID, &model.Metadata{ID: "1"}, 2); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("Repository.CompareAndPut() error = %v with future version, want %v", err, repository.ErrVersionMismatch)
+	}
+
+	// an unconditional put still bumps the version
+	if err := r.Put(ctx, m.ID, &model.Metadata{ID: "1", Title: "second"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Get(ctx, "1")
+	if err != nil || got.Version != 2 || got.Title != "second" {
+		t.Errorf("Repository.Get() = %v, %v, want version 2", got, err)
+	}
+}
+
+// Racing writers each read, modify and conditionally write the same record, retrying on conflict.
+// No increment may be lost and every successful write must produce a new version.
+func TestRepository_CompareAndPutRace(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	if err := r.Put(ctx, "1", &model.Metadata{ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				cur, err := r.Get(ctx, "1")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				next := *cur
+				next.RuntimeMinutes++
+				err = r.CompareAndPut(ctx, "1", &next, cur.Version)
+				if errors.Is(err, repository.ErrVersionMismatch) {
+					continue
+				}
+				if err != nil {
+					t.Error(err)
+				}
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := r.Get(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RuntimeMinutes != writers || got.Version != writers+1 {
+		t.Errorf("got runtime %d at version %d, want %d at version %d", got.RuntimeMinutes, got.Version, writers, writers+1)
+	}
+}
+
+// Only one of several writers expecting the same version can win.
+func TestRepository_CompareAndPutSingleWinner(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	if err := r.Put(ctx, "1", &model.Metadata{ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wins := 0
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := r.CompareAndPut(ctx, "1", &model.Metadata{ID: "1"}, 1)
+			if err == nil {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			} else if !errors.Is(err, repository.ErrVersionMismatch) {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if wins != 1 {
+		t.Errorf("%d writers won, want exactly 1", wins)
+	}
+}
+
+func TestRepository_Update(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+
+	if err := r.Put(ctx, "1", &model.Metadata{ID: "1", Title: "title", Director: "director", Genres: []string{"Drama"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	patch := &model.Metadata{Title: "new title", Director: "ignored", Genres: []string{"War", "Crime"}}
+	got, err := r.Update(ctx, "1", patch, []model.Field{model.FieldTitle, model.FieldGenres}, 0)
+	if err != nil {
+		t.Fatalf("Repository.Update() error = %v", err)
+	}
+	want := &model.Metadata{ID: "1", Title: "new title", Director: "director", Genres: []string{"Crime", "War"}, Slug: "new-title", Version: 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.Update() = %+v, want %+v", got, want)
+	}
+
+	if _, err := r.Update(ctx, "1", patch, []model.Field{model.FieldTitle}, 1); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("Repository.Update() error = %v with stale version, want %v", err, repository.ErrVersionMismatch)
+	}
+	if _, err := r.Update(ctx, "1", patch, []model.Field{model.FieldTitle}, 2); err != nil {
+		t.Errorf("Repository.Update() error = %v with current version", err)
+	}
+
+	if err := r.Delete(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Update(ctx, "1", patch, []model.Field{model.FieldTitle}, 0); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.Update() error = %v on deleted record, want %v", err, repository.ErrNotFound)
+	}
+}
+
+func TestRepository_Revisions(t *testing.T) {
+	r := New()
+	ctx := utilities.WithAuthor(context.Background(), "alice")
+
+	if err := r.Put(ctx, "1", &model.Metadata{ID: "1", Title: "title", Director: "director"}); err != nil {
+		t.Fatal(err)
+	}
+	between := time.Now()
+	time.Sleep(time.Millisecond)
+	if _, err := r.Update(utilities.WithAuthor(ctx, "bob"), "1", &model.Metadata{Title: "vandalized"}, []model.Field{model.FieldTitle}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := r.ListRevisions(ctx, "1", 0, 10)
+	if err != nil {
+		t.Fatalf("Repository.ListRevisions() error = %v", err)
+	}
+	if len(revs) != 2 || revs[0].Version != 2 || revs[1].Version != 1 {
+		t.Fatalf("Repository.ListRevisions() = %v, want versions 2 and 1", revs)
+	}
+	wantChanges := []model.Change{{Field: model.FieldTitle, Old: json.RawMessage(`"title"`), New: json.RawMessage(`"vandalized"`)}}
+	if revs[0].Author != "bob" || !reflect.DeepEqual(revs[0].Changes, wantChanges) {
+		t.Errorf("latest revision = %+v, want title change by bob", revs[0])
+	}
+	if revs[1].Author != "alice" || len(revs[1].Changes) != 2 {
+		t.Errorf("first revision = %+v, want title and director set by alice", revs[1])
+	}
+
+	if page, _ := r.ListRevisions(ctx, "1", 1, 10); len(page) != 1 || page[0].Version != 1 {
+		t.Errorf("Repository.ListRevisions() with offset = %v, want version 1", page)
+	}
+
+	got, err := r.GetAsOf(ctx, "1", between)
+	if err != nil || got.Title != "title" {
+		t.Errorf("Repository.GetAsOf() = %v, %v, want the first revision", got, err)
+	}
+	if _, err := r.GetAsOf(ctx, "1", between.Add(-time.Hour)); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.GetAsOf() error = %v before creation, want %v", err, repository.ErrNotFound)
+	}
+
+	if err := r.Purge(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetRevision(ctx, "1", 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Repository.GetRevision() error = %v after purge, want %v", err, repository.ErrNotFound)
+	}
+}
+
+func TestRepository_ListAfter(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	for _, id := range []string{"c", "a", "d", "b"} {
+		if err := r.Put(ctx, id, &model.Metadata{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Delete(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	after := ""
+	for {
+		page, err := r.ListAfter(ctx, after, 2)
+		if err != nil {
+			t.Fatalf("Repository.ListAfter() error = %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, m := range page {
+			ids = append(ids, m.ID)
+		}
+		after = page[len(page)-1].ID
+	}
+	if want := []string{"a", "b", "d"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Repository.ListAfter() walked %v, want %v", ids, want)
+	}
+}
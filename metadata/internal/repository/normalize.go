@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"movieexample.com/metadata/pkg/model"
+)
+
+// Normalize returns a copy of m shaped the way the postgres repository reads it back: genres
+// sorted and deduplicated, credits in billing order, external IDs by provider and the release
+// date truncated to a day. Repositories store records normalized so that they all return the
+// same records for the same writes.
+func Normalize(m *model.Metadata) *model.Metadata {
+	res := *m
+	res.Genres = nil
+	if len(m.Genres) > 0 {
+		res.Genres = UniqueSorted(m.Genres)
+	}
+	res.Credits = nil
+	if len(m.Credits) > 0 {
+		res.Credits = slices.Clone(m.Credits)
+		slices.SortFunc(res.Credits, func(a, b model.Credit) int {
+			return cmp.Or(cmp.Compare(a.Order, b.Order), strings.Compare(a.Name, b.Name))
+		})
+	}
+	res.ExternalIDs = nil
+	if len(m.ExternalIDs) > 0 {
+		res.ExternalIDs = slices.Clone(m.ExternalIDs)
+		slices.SortFunc(res.ExternalIDs, func(a, b model.ExternalID) int {
+			return strings.Compare(string(a.Provider), string(b.Provider))
+		})
+	}
+	if !m.ReleaseDate.IsZero() {
+		y, mo, d := m.ReleaseDate.Date()
+		res.ReleaseDate = time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	return &res
+}
+
+// UniqueSorted returns the values sorted, without duplicates.
+func UniqueSorted(values []string) []string {
+	res := slices.Clone(values)
+	slices.Sort(res)
+	return slices.Compact(res)
+}
@@ -2,15 +2,23 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib" // Import the PostgreSQL driver
 	dbGen "movieexample.com/gen/db"
 	config "movieexample.com/metadata/configs"
 	"movieexample.com/metadata/internal/controller/metadata"
+	"movieexample.com/metadata/internal/repository"
 	"movieexample.com/metadata/pkg/model"
+	"movieexample.com/pkg/utilities"
 )
 
 type repo struct {
@@ -59,26 +67,632 @@ func CloseDB(r metadata.Repository) {
 // If the movie is not found, it returns repository.ErrNotFound.
 // If there is an error retrieving the metadata, it returns the error.
 func (r *repo) Get(ctx context.Context, id string) (*model.Metadata, error) {
-	mv, err := r.q.GetMovie(ctx, id)
+	return get(ctx, &r.q, id)
+}
+
+// get reads a movie with its genres, credits, external IDs and current slug through q, which may be bound to a transaction.
+func get(ctx context.Context, q *dbGen.Queries, id string) (*model.Metadata, error) {
+	mv, err := q.GetMovie(ctx, id)
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	genres, err := q.ListMovieGenres(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.Metadata{
-		ID:          id,
-		Title:       mv.Title.String,
-		Description: mv.Description.String,
-		Director:    mv.Director.String,
-	}, nil
+	credits, err := q.ListMovieCredits(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	externalIDs, err := listExternalIDs(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	slug, err := currentSlug(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &model.Metadata{
+		ID:               id,
+		Title:            mv.Title.String,
+		Description:      mv.Description.String,
+		Director:         mv.Director.String,
+		Genres:           genres,
+		RuntimeMinutes:   mv.RuntimeMinutes.Int32,
+		OriginalLanguage: mv.OriginalLanguage.String,
+		Country:          mv.Country.String,
+		ExternalIDs:      externalIDs,
+		Slug:             slug,
+		Version:          mv.Version,
+	}
+	if mv.ReleaseDate.Valid {
+		m.ReleaseDate = mv.ReleaseDate.Time
+	}
+	for _, c := range credits {
+		m.Credits = append(m.Credits, model.Credit{
+			Name:      c.PersonName,
+			Role:      model.CreditRole(c.Role),
+			Character: c.CharacterName.String,
+			Order:     c.BillingOrder,
+		})
+	}
+
+	return m, nil
 }
 
-// Put adds movie metadata for a given movie id.
+// Put upserts movie metadata for a given movie id, bumping its version.
+// The movie row, its genres and its credits are written in a single transaction.
+// The new version is written back to metadata.Version.
 func (r *repo) Put(ctx context.Context, id string, metadata *model.Metadata) error {
-	err := r.q.InsertMovie(ctx, dbGen.InsertMovieParams{
-		ID:          id,
-		Title:       pgtype.Text{String: metadata.Title, Valid: metadata.Title != ""},
-		Description: pgtype.Text{String: metadata.Description, Valid: metadata.Description != ""},
-		Director:    pgtype.Text{String: metadata.Director, Valid: metadata.Director != ""},
+	return r.put(ctx, id, metadata, func(q *dbGen.Queries, p dbGen.UpsertMovieParams) (int64, error) {
+		return q.UpsertMovie(ctx, p)
 	})
-	return err
+}
+
+// CompareAndPut writes movie metadata only if the stored version equals expectedVersion,
+// where 0 means the movie must not exist yet. Otherwise it returns repository.ErrVersionMismatch.
+func (r *repo) CompareAndPut(ctx context.Context, id string, metadata *model.Metadata, expectedVersion int64) error {
+	return r.put(ctx, id, metadata, func(q *dbGen.Queries, p dbGen.UpsertMovieParams) (int64, error) {
+		var version int64
+		var err error
+		if expectedVersion == 0 {
+			version, err = q.InsertMovieIfAbsent(ctx, dbGen.InsertMovieIfAbsentParams(p))
+		} else {
+			version, err = q.UpdateMovieIfVersion(ctx, dbGen.UpdateMovieIfVersionParams{
+				ID:               p.ID,
+				Title:            p.Title,
+				Description:      p.Description,
+				Director:         p.Director,
+				RuntimeMinutes:   p.RuntimeMinutes,
+				ReleaseDate:      p.ReleaseDate,
+				OriginalLanguage: p.OriginalLanguage,
+				Country:          p.Country,
+				ExpectedVersion:  expectedVersion,
+			})
+		}
+		if err != nil && errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrVersionMismatch
+		}
+		return version, err
+	})
+}
+
+// Update writes only the masked columns of the movie row and, when masked, replaces its genres,
+// credits or external IDs, all in one transaction. A new title or release date assigns the slug
+// in the same transaction. It returns the movie as stored after the update.
+func (r *repo) Update(ctx context.Context, id string, patch *model.Metadata, fields []model.Field, expectedVersion int64) (*model.Metadata, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op once committed
+
+	q := r.q.WithTx(tx)
+	_, err = q.UpdateMovieFields(ctx, dbGen.UpdateMovieFieldsParams{
+		ID:                  id,
+		SetTitle:            slices.Contains(fields, model.FieldTitle),
+		Title:               pgtype.Text{String: patch.Title, Valid: patch.Title != ""},
+		SetDescription:      slices.Contains(fields, model.FieldDescription),
+		Description:         pgtype.Text{String: patch.Description, Valid: patch.Description != ""},
+		SetDirector:         slices.Contains(fields, model.FieldDirector),
+		Director:            pgtype.Text{String: patch.Director, Valid: patch.Director != ""},
+		SetRuntimeMinutes:   slices.Contains(fields, model.FieldRuntimeMinutes),
+		RuntimeMinutes:      pgtype.Int4{Int32: patch.RuntimeMinutes, Valid: patch.RuntimeMinutes != 0},
+		SetReleaseDate:      slices.Contains(fields, model.FieldReleaseDate),
+		ReleaseDate:         dateToPg(patch.ReleaseDate),
+		SetOriginalLanguage: slices.Contains(fields, model.FieldOriginalLanguage),
+		OriginalLanguage:    pgtype.Text{String: patch.OriginalLanguage, Valid: patch.OriginalLanguage != ""},
+		SetCountry:          slices.Contains(fields, model.FieldCountry),
+		Country:             pgtype.Text{String: patch.Country, Valid: patch.Country != ""},
+		ExpectedVersion:     expectedVersion,
+	})
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		// tell a missing movie apart from a version conflict
+		if _, err := q.GetMovie(ctx, id); err != nil && errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		return nil, repository.ErrVersionMismatch
+	} else if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(fields, model.FieldGenres) {
+		if err := putGenres(ctx, q, id, patch.Genres); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(fields, model.FieldCredits) {
+		if err := putCredits(ctx, q, id, patch.Credits); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(fields, model.FieldExternalIDs) {
+		if err := putExternalIDs(ctx, q, id, patch.ExternalIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(fields, model.FieldTitle) || slices.Contains(fields, model.FieldReleaseDate) {
+		mv, err := q.GetMovie(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := assignSlug(ctx, q, id, mv.Title.String, mv.ReleaseDate.Time); err != nil {
+			return nil, err
+		}
+	}
+
+	m, err := recordRevision(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// put writes the movie row with write, then replaces its genres, credits and external IDs,
+// assigns its slug and records the revision, all in one transaction. The new version and slug are
+// written back to metadata.
+func (r *repo) put(ctx context.Context, id string, metadata *model.Metadata, write func(*dbGen.Queries, dbGen.UpsertMovieParams) (int64, error)) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op once committed
+
+	q := r.q.WithTx(tx)
+	version, err := write(q, dbGen.UpsertMovieParams{
+		ID:               id,
+		Title:            pgtype.Text{String: metadata.Title, Valid: metadata.Title != ""},
+		Description:      pgtype.Text{String: metadata.Description, Valid: metadata.Description != ""},
+		Director:         pgtype.Text{String: metadata.Director, Valid: metadata.Director != ""},
+		RuntimeMinutes:   pgtype.Int4{Int32: metadata.RuntimeMinutes, Valid: metadata.RuntimeMinutes != 0},
+		ReleaseDate:      dateToPg(metadata.ReleaseDate),
+		OriginalLanguage: pgtype.Text{String: metadata.OriginalLanguage, Valid: metadata.OriginalLanguage != ""},
+		Country:          pgtype.Text{String: metadata.Country, Valid: metadata.Country != ""},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := putGenres(ctx, q, id, metadata.Genres); err != nil {
+		return err
+	}
+
+	if err := putCredits(ctx, q, id, metadata.Credits); err != nil {
+		return err
+	}
+
+	if err := putExternalIDs(ctx, q, id, metadata.ExternalIDs); err != nil {
+		return err
+	}
+
+	slug, err := assignSlug(ctx, q, id, metadata.Title, metadata.ReleaseDate)
+	if err != nil {
+		return err
+	}
+
+	if _, err := recordRevision(ctx, q, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	metadata.Version = version
+	metadata.Slug = slug
+
+	return nil
+}
+
+// recordRevision reads back the movie as just written in the transaction of q and stores it as a
+// new revision, along with its diff against the previous revision. It returns the movie as read.
+func recordRevision(ctx context.Context, q *dbGen.Queries, id string) (*model.Metadata, error) {
+	m, err := get(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *model.Metadata
+	latest, err := q.GetLatestRevision(ctx, id)
+	if err == nil {
+		if err := json.Unmarshal(latest.Snapshot, &prev); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	p, err := newRevision(ctx, prev, m)
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.InsertRevision(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// newRevision builds the revision row of m, attributed to the author of ctx and diffed against prev.
+func newRevision(ctx context.Context, prev, m *model.Metadata) (dbGen.InsertRevisionParams, error) {
+	changes := model.Diff(prev, m)
+	if changes == nil {
+		changes = []model.Change{}
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return dbGen.InsertRevisionParams{}, err
+	}
+	snapshot, err := json.Marshal(m)
+	if err != nil {
+		return dbGen.InsertRevisionParams{}, err
+	}
+
+	return dbGen.InsertRevisionParams{
+		MovieID:  m.ID,
+		Version:  m.Version,
+		Author:   utilities.AuthorFromContext(ctx),
+		Changes:  changesJSON,
+		Snapshot: snapshot,
+	}, nil
+}
+
+// GetAsOf returns the snapshot of the last revision of the movie made at or before asOf.
+func (r *repo) GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.Metadata, error) {
+	row, err := r.q.GetRevisionAsOf(ctx, dbGen.GetRevisionAsOfParams{
+		MovieID: id,
+		AsOf:    pgtype.Timestamptz{Time: asOf, Valid: true},
+	})
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	rev, err := revisionFromRow(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return rev.Snapshot, nil
+}
+
+// GetRevision returns the revision of the movie that produced the given version.
+func (r *repo) GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error) {
+	row, err := r.q.GetRevision(ctx, dbGen.GetRevisionParams{MovieID: id, Version: version})
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return revisionFromRow(row)
+}
+
+// ListRevisions returns at most limit revisions of the movie, newest first, skipping the first offset ones.
+func (r *repo) ListRevisions(ctx context.Context, id string, offset, limit int) ([]*model.Revision, error) {
+	rows, err := r.q.ListRevisions(ctx, dbGen.ListRevisionsParams{
+		MovieID:    id,
+		PageOffset: int32(offset),
+		PageLimit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Revision, 0, len(rows))
+	for _, row := range rows {
+		rev, err := revisionFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rev)
+	}
+
+	return res, nil
+}
+
+func revisionFromRow(row dbGen.MovieRevision) (*model.Revision, error) {
+	rev := &model.Revision{
+		MovieID:   row.MovieID,
+		Version:   row.Version,
+		Author:    row.Author,
+		CreatedAt: row.CreatedAt.Time,
+	}
+	if err := json.Unmarshal(row.Changes, &rev.Changes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(row.Snapshot, &rev.Snapshot); err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// putGenres replaces the genres linked to the movie, creating unknown genres on the fly.
+func putGenres(ctx context.Context, q *dbGen.Queries, id string, genres []string) error {
+	if err := q.DeleteMovieGenres(ctx, id); err != nil {
+		return err
+	}
+	for _, g := range genres {
+		genreID, err := q.UpsertGenre(ctx, g)
+		if err != nil {
+			return err
+		}
+		if err := q.InsertMovieGenre(ctx, dbGen.InsertMovieGenreParams{MovieID: id, GenreID: genreID}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// putCredits replaces the cast and crew of the movie.
+func putCredits(ctx context.Context, q *dbGen.Queries, id string, credits []model.Credit) error {
+	if err := q.DeleteMovieCredits(ctx, id); err != nil {
+		return err
+	}
+	for _, c := range credits {
+		err := q.InsertMovieCredit(ctx, dbGen.InsertMovieCreditParams{
+			MovieID:       id,
+			PersonName:    c.Name,
+			Role:          string(c.Role),
+			CharacterName: pgtype.Text{String: c.Character, Valid: c.Character != ""},
+			BillingOrder:  c.Order,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List returns at most limit movies matching filter, skipping the first offset ones.
+func (r *repo) List(ctx context.Context, filter model.Filter, sort model.SortOrder, offset, limit int) ([]*model.Metadata, error) {
+	rows, err := r.q.ListMovies(ctx, dbGen.ListMoviesParams{
+		Director:        pgtype.Text{String: filter.Director, Valid: filter.Director != ""},
+		TitlePrefix:     pgtype.Text{String: likeEscaper.Replace(filter.TitlePrefix), Valid: filter.TitlePrefix != ""},
+		Genre:           pgtype.Text{String: filter.Genre, Valid: filter.Genre != ""},
+		ReleaseYearFrom: pgtype.Int4{Int32: int32(filter.ReleaseYearFrom), Valid: filter.ReleaseYearFrom != 0},
+		ReleaseYearTo:   pgtype.Int4{Int32: int32(filter.ReleaseYearTo), Valid: filter.ReleaseYearTo != 0},
+		SortOrder:       string(sort),
+		PageOffset:      int32(offset),
+		PageLimit:       int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Metadata, 0, len(rows))
+	for _, mv := range rows {
+		res = append(res, metadataFromMovie(mv))
+	}
+
+	if err := r.attachDetails(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListAfter returns at most limit movies whose id sorts after afterID, ordered by id.
+// It pages with a keyset cursor on the primary key rather than an offset, so walking
+// the whole catalog reads each row once and holds a single page in memory.
+func (r *repo) ListAfter(ctx context.Context, afterID string, limit int) ([]*model.Metadata, error) {
+	rows, err := r.q.ListMoviesAfter(ctx, dbGen.ListMoviesAfterParams{
+		AfterID:   afterID,
+		PageLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Metadata, 0, len(rows))
+	for _, mv := range rows {
+		res = append(res, metadataFromMovie(dbGen.ListMoviesRow(mv)))
+	}
+
+	if err := r.attachDetails(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetMany returns the live movies among ids, ordered by id, with one query per table.
+func (r *repo) GetMany(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	rows, err := r.q.GetMovies(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Metadata, 0, len(rows))
+	for _, mv := range rows {
+		res = append(res, metadataFromMovie(dbGen.ListMoviesRow(mv)))
+	}
+
+	if err := r.attachDetails(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Search returns movies matching the web-search style query, ranked by ts_rank
+// over the weighted title, director and description vector.
+func (r *repo) Search(ctx context.Context, query string, offset, limit int) ([]model.SearchResult, error) {
+	rows, err := r.q.SearchMovies(ctx, dbGen.SearchMoviesParams{
+		Query:      query,
+		PageOffset: int32(offset),
+		PageLimit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.SearchResult, 0, len(rows))
+	page := make([]*model.Metadata, 0, len(rows))
+	for _, row := range rows {
+		m := metadataFromMovie(dbGen.ListMoviesRow{
+			ID:               row.ID,
+			Title:            row.Title,
+			Description:      row.Description,
+			Director:         row.Director,
+			RuntimeMinutes:   row.RuntimeMinutes,
+			ReleaseDate:      row.ReleaseDate,
+			OriginalLanguage: row.OriginalLanguage,
+			Country:          row.Country,
+			Version:          row.Version,
+		})
+		page = append(page, m)
+		res = append(res, model.SearchResult{Metadata: m, Score: row.Score})
+	}
+
+	if err := r.attachDetails(ctx, page); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// attachDetails loads the genres, credits, external IDs and current slugs of a page of movies with
+// one query each.
+func (r *repo) attachDetails(ctx context.Context, page []*model.Metadata) error {
+	if len(page) == 0 {
+		return nil
+	}
+	byID := make(map[string]*model.Metadata, len(page))
+	ids := make([]string, 0, len(page))
+	for _, m := range page {
+		byID[m.ID] = m
+		ids = append(ids, m.ID)
+	}
+
+	genres, err := r.q.ListGenresForMovies(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, g := range genres {
+		byID[g.MovieID].Genres = append(byID[g.MovieID].Genres, g.Name)
+	}
+
+	credits, err := r.q.ListCreditsForMovies(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, c := range credits {
+		byID[c.MovieID].Credits = append(byID[c.MovieID].Credits, model.Credit{
+			Name:      c.PersonName,
+			Role:      model.CreditRole(c.Role),
+			Character: c.CharacterName.String,
+			Order:     c.BillingOrder,
+		})
+	}
+
+	externalIDs, err := r.q.ListExternalIDsForMovies(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, e := range externalIDs {
+		byID[e.MovieID].ExternalIDs = append(byID[e.MovieID].ExternalIDs, model.ExternalID{
+			Provider: model.ExternalIDProvider(e.Provider),
+			ID:       e.ExternalID,
+		})
+	}
+
+	slugs, err := r.q.ListCurrentSlugsForMovies(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, s := range slugs {
+		byID[s.MovieID].Slug = s.Slug
+	}
+
+	return nil
+}
+
+// Delete soft deletes the movie by stamping its deleted_at column.
+// It returns repository.ErrNotFound if there is no live movie with the given id.
+func (r *repo) Delete(ctx context.Context, id string) error {
+	n, err := r.q.SoftDeleteMovie(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// Undelete clears the deleted_at column of a soft deleted movie.
+// It returns repository.ErrNotFound if there is no deleted movie with the given id.
+func (r *repo) Undelete(ctx context.Context, id string) error {
+	n, err := r.q.UndeleteMovie(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// Purge deletes the movie row; genres links, credits, external IDs, slugs and relations go with it
+// through ON DELETE CASCADE.
+// It returns repository.ErrNotFound if there is no movie with the given id.
+func (r *repo) Purge(ctx context.Context, id string) error {
+	n, err := r.q.DeleteMovie(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// likeEscaper escapes the LIKE wildcards so a title prefix is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func metadataFromMovie(mv dbGen.ListMoviesRow) *model.Metadata {
+	m := &model.Metadata{
+		ID:               mv.ID,
+		Title:            mv.Title.String,
+		Description:      mv.Description.String,
+		Director:         mv.Director.String,
+		RuntimeMinutes:   mv.RuntimeMinutes.Int32,
+		OriginalLanguage: mv.OriginalLanguage.String,
+		Country:          mv.Country.String,
+		Version:          mv.Version,
+	}
+	if mv.ReleaseDate.Valid {
+		m.ReleaseDate = mv.ReleaseDate.Time
+	}
+
+	return m
+}
+
+func dateToPg(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: !t.IsZero()}
 }
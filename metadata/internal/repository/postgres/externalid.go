@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// uniqueViolation is the Postgres error code raised when a write breaks a unique constraint.
+const uniqueViolation = "23505"
+
+// GetByExternalID retrieves the live movie that has the given external ID.
+func (r *repo) GetByExternalID(ctx context.Context, e model.ExternalID) (*model.Metadata, error) {
+	id, err := r.q.GetMovieIDByExternalID(ctx, dbGen.GetMovieIDByExternalIDParams{
+		Provider:   string(e.Provider),
+		ExternalID: e.ID,
+	})
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, id)
+}
+
+// listExternalIDs returns the external IDs of the movie ordered by provider.
+func listExternalIDs(ctx context.Context, q *dbGen.Queries, id string) ([]model.ExternalID, error) {
+	rows, err := q.ListMovieExternalIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []model.ExternalID
+	for _, row := range rows {
+		res = append(res, model.ExternalID{Provider: model.ExternalIDProvider(row.Provider), ID: row.ExternalID})
+	}
+
+	return res, nil
+}
+
+// putExternalIDs replaces the external IDs of the movie.
+func putExternalIDs(ctx context.Context, q *dbGen.Queries, id string, ids []model.ExternalID) error {
+	if err := q.DeleteMovieExternalIDs(ctx, id); err != nil {
+		return err
+	}
+	for _, e := range ids {
+		err := q.InsertMovieExternalID(ctx, dbGen.InsertMovieExternalIDParams{
+			MovieID:    id,
+			Provider:   string(e.Provider),
+			ExternalID: e.ID,
+		})
+		if err != nil {
+			return externalIDError(err)
+		}
+	}
+
+	return nil
+}
+
+// copyExternalIDs replaces the external IDs of the batch.
+func copyExternalIDs(ctx context.Context, q *dbGen.Queries, ids []string, ms []*model.Metadata) error {
+	if err := q.DeleteExternalIDsForMovies(ctx, ids); err != nil {
+		return err
+	}
+
+	var rows []dbGen.CopyMovieExternalIDsParams
+	for _, m := range ms {
+		for _, e := range m.ExternalIDs {
+			rows = append(rows, dbGen.CopyMovieExternalIDsParams{
+				MovieID:    m.ID,
+				Provider:   string(e.Provider),
+				ExternalID: e.ID,
+			})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := q.CopyMovieExternalIDs(ctx, rows)
+
+	return externalIDError(err)
+}
+
+// externalIDError maps a violation of the one-movie-per-external-ID constraint to
+// repository.ErrExternalIDConflict.
+func externalIDError(err error) error {
+	var pgErr *pgconn.PgError
+	if err != nil && errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return repository.ErrExternalIDConflict
+	}
+
+	return err
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// foreignKeyViolation is the Postgres error code raised when a referenced row does not exist.
+const foreignKeyViolation = "23503"
+
+// storedVariant is the JSON form of an image variant in the variants column. Unlike
+// model.ImageVariant it keeps the storage key and leaves out the URL.
+type storedVariant struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Width       int32  `json:"width"`
+	Height      int32  `json:"height"`
+	Key         string `json:"key"`
+}
+
+// PutImage records an uploaded image of a movie.
+func (r *repo) PutImage(ctx context.Context, img *model.Image) error {
+	variants := make([]storedVariant, 0, len(img.Variants))
+	for _, v := range img.Variants {
+		variants = append(variants, storedVariant{
+			Name:        v.Name,
+			ContentType: v.ContentType,
+			Width:       v.Width,
+			Height:      v.Height,
+			Key:         v.Key,
+		})
+	}
+	b, err := json.Marshal(variants)
+	if err != nil {
+		return err
+	}
+
+	createdAt, err := r.q.InsertImage(ctx, dbGen.InsertImageParams{
+		ID:          img.ID,
+		MovieID:     img.MovieID,
+		Kind:        string(img.Kind),
+		ContentType: img.ContentType,
+		Width:       img.Width,
+		Height:      img.Height,
+		SizeBytes:   img.Size,
+		StorageKey:  img.Key,
+		Variants:    b,
+	})
+	var pgErr *pgconn.PgError
+	if err != nil && errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return repository.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	img.CreatedAt = createdAt.Time
+
+	return nil
+}
+
+// ListImages returns the images of a movie, oldest first.
+func (r *repo) ListImages(ctx context.Context, movieID string) ([]model.Image, error) {
+	rows, err := r.q.ListImages(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Image, 0, len(rows))
+	for _, row := range rows {
+		var variants []storedVariant
+		if err := json.Unmarshal(row.Variants, &variants); err != nil {
+			return nil, err
+		}
+		img := model.Image{
+			ID:          row.ID,
+			MovieID:     row.MovieID,
+			Kind:        model.ImageKind(row.Kind),
+			ContentType: row.ContentType,
+			Width:       row.Width,
+			Height:      row.Height,
+			Size:        row.SizeBytes,
+			Key:         row.StorageKey,
+			CreatedAt:   row.CreatedAt.Time,
+		}
+		for _, v := range variants {
+			img.Variants = append(img.Variants, model.ImageVariant{
+				Name:        v.Name,
+				ContentType: v.ContentType,
+				Width:       v.Width,
+				Height:      v.Height,
+				Key:         v.Key,
+			})
+		}
+		res = append(res, img)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// The movie rows of a batch are copied into a temporary staging table and merged from there,
+// since COPY itself cannot upsert. sqlc does not know about temporary tables, so these
+// statements are kept here instead of in query/movies.sql.
+const (
+	createMovieStaging = `CREATE TEMP TABLE movie_import (
+    id VARCHAR(255) NOT NULL,
+    title VARCHAR(255),
+    description TEXT,
+    director VARCHAR(255),
+    runtime_minutes INT,
+    release_date DATE,
+    original_language VARCHAR(35),
+    country VARCHAR(64)
+) ON COMMIT DROP`
+
+	mergeMovieStaging = `INSERT INTO movie (id, title, description, director, runtime_minutes, release_date, original_language, country)
+SELECT id, title, description, director, runtime_minutes, release_date, original_language, country
+FROM movie_import
+ON CONFLICT (id) DO UPDATE SET
+  title = EXCLUDED.title,
+  description = EXCLUDED.description,
+  director = EXCLUDED.director,
+  runtime_minutes = EXCLUDED.runtime_minutes,
+  release_date = EXCLUDED.release_date,
+  original_language = EXCLUDED.original_language,
+  country = EXCLUDED.country,
+  deleted_at = NULL,
+  version = movie.version + 1
+RETURNING id, version`
+)
+
+var movieStagingColumns = []string{
+	"id", "title", "description", "director", "runtime_minutes", "release_date", "original_language", "country",
+}
+
+// PutMany upserts a batch of movies in one transaction, using COPY for the movie rows, their genres,
+// their credits, their external IDs and their revisions. The IDs of the batch must be unique. Slugs are
+// assigned one movie at a time in the same transaction. The new versions and slugs are written back to the records.
+func (r *repo) PutMany(ctx context.Context, ms []*model.Metadata) error {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op once committed
+
+	q := r.q.WithTx(tx)
+	ids := make([]string, 0, len(ms))
+	for _, m := range ms {
+		ids = append(ids, m.ID)
+	}
+
+	versions, err := copyMovies(ctx, tx, ms)
+	if err != nil {
+		return err
+	}
+
+	if err := copyGenres(ctx, q, ids, ms); err != nil {
+		return err
+	}
+
+	if err := copyCredits(ctx, q, ids, ms); err != nil {
+		return err
+	}
+
+	if err := copyExternalIDs(ctx, q, ids, ms); err != nil {
+		return err
+	}
+
+	slugs := make(map[string]string, len(ms))
+	snapshots := make([]*model.Metadata, 0, len(ms))
+	for _, m := range ms {
+		slug, err := assignSlug(ctx, q, m.ID, m.Title, m.ReleaseDate)
+		if err != nil {
+			return err
+		}
+		slugs[m.ID] = slug
+		s := repository.Normalize(m)
+		s.Version = versions[m.ID]
+		s.Slug = slug
+		snapshots = append(snapshots, s)
+	}
+	if err := copyRevisions(ctx, q, ids, snapshots); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	for _, m := range ms {
+		m.Version = versions[m.ID]
+		m.Slug = slugs[m.ID]
+	}
+
+	return nil
+}
+
+// copyMovies stages the movie rows with COPY and merges them into the movie table.
+// It returns the new version of every movie, keyed by ID.
+func copyMovies(ctx context.Context, tx pgx.Tx, ms []*model.Metadata) (map[string]int64, error) {
+	if _, err := tx.Exec(ctx, createMovieStaging); err != nil {
+		return nil, err
+	}
+
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"movie_import"}, movieStagingColumns, pgx.CopyFromSlice(len(ms), func(i int) ([]any, error) {
+		m := ms[i]
+		return []any{
+			m.ID,
+			pgtype.Text{String: m.Title, Valid: m.Title != ""},
+			pgtype.Text{String: m.Description, Valid: m.Description != ""},
+			pgtype.Text{String: m.Director, Valid: m.Director != ""},
+			pgtype.Int4{Int32: m.RuntimeMinutes, Valid: m.RuntimeMinutes != 0},
+			dateToPg(m.ReleaseDate),
+			pgtype.Text{String: m.OriginalLanguage, Valid: m.OriginalLanguage != ""},
+			pgtype.Text{String: m.Country, Valid: m.Country != ""},
+		}, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, mergeMovieStaging)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]int64, len(ms))
+	var id string
+	var version int64
+	_, err = pgx.ForEachRow(rows, []any{&id, &version}, func() error {
+		versions[id] = version
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// copyGenres replaces the genre links of the batch, creating unknown genres with a single upsert.
+func copyGenres(ctx context.Context, q *dbGen.Queries, ids []string, ms []*model.Metadata) error {
+	if err := q.DeleteGenresForMovies(ctx, ids); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, m := range ms {
+		names = append(names, m.Genres...)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	genres, err := q.UpsertGenres(ctx, names)
+	if err != nil {
+		return err
+	}
+	genreIDs := make(map[string]int32, len(genres))
+	for _, g := range genres {
+		genreIDs[g.Name] = g.ID
+	}
+
+	var links []dbGen.CopyMovieGenresParams
+	for _, m := range ms {
+		for _, g := range repository.UniqueSorted(m.Genres) {
+			links = append(links, dbGen.CopyMovieGenresParams{MovieID: m.ID, GenreID: genreIDs[g]})
+		}
+	}
+	_, err = q.CopyMovieGenres(ctx, links)
+
+	return err
+}
+
+// copyCredits replaces the cast and crew of the batch.
+func copyCredits(ctx context.Context, q *dbGen.Queries, ids []string, ms []*model.Metadata) error {
+	if err := q.DeleteCreditsForMovies(ctx, ids); err != nil {
+		return err
+	}
+
+	var credits []dbGen.CopyMovieCreditsParams
+	for _, m := range ms {
+		for _, c := range m.Credits {
+			credits = append(credits, dbGen.CopyMovieCreditsParams{
+				MovieID:       m.ID,
+				PersonName:    c.Name,
+				Role:          string(c.Role),
+				CharacterName: pgtype.Text{String: c.Character, Valid: c.Character != ""},
+				BillingOrder:  c.Order,
+			})
+		}
+	}
+	if len(credits) == 0 {
+		return nil
+	}
+	_, err := q.CopyMovieCredits(ctx, credits)
+
+	return err
+}
+
+// copyRevisions records a revision for every movie of the batch, diffed against its latest revision.
+func copyRevisions(ctx context.Context, q *dbGen.Queries, ids []string, snapshots []*model.Metadata) error {
+	latest, err := q.ListLatestRevisions(ctx, ids)
+	if err != nil {
+		return err
+	}
+	prev := make(map[string]*model.Metadata, len(latest))
+	for _, row := range latest {
+		var m *model.Metadata
+		if err := json.Unmarshal(row.Snapshot, &m); err != nil {
+			return err
+		}
+		prev[row.MovieID] = m
+	}
+
+	revisions := make([]dbGen.CopyRevisionsParams, 0, len(snapshots))
+	for _, s := range snapshots {
+		p, err := newRevision(ctx, prev[s.ID], s)
+		if err != nil {
+			return err
+		}
+		revisions = append(revisions, dbGen.CopyRevisionsParams(p))
+	}
+	_, err = q.CopyRevisions(ctx, revisions)
+
+	return err
+}
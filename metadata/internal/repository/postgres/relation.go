@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// PutRelation links two movies. The relation goes away with either movie when it is purged.
+// The cycle check and the insert run in one transaction that holds a lock on the relation type,
+// so concurrent puts of the same type cannot close a loop between them.
+func (r *repo) PutRelation(ctx context.Context, rel model.Relation) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op once committed
+	q := r.q.WithTx(tx)
+
+	if err := q.LockRelationType(ctx, string(rel.Type)); err != nil {
+		return err
+	}
+	cycle, err := q.RelationReaches(ctx, dbGen.RelationReachesParams{
+		FromID:       rel.RelatedID,
+		ToID:         rel.MovieID,
+		RelationType: string(rel.Type),
+	})
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return repository.ErrRelationCycle
+	}
+
+	err = q.InsertMovieRelation(ctx, dbGen.InsertMovieRelationParams{
+		MovieID:      rel.MovieID,
+		RelationType: string(rel.Type),
+		RelatedID:    rel.RelatedID,
+	})
+	var pgErr *pgconn.PgError
+	if err != nil && errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return repository.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteRelation removes a link between two movies.
+func (r *repo) DeleteRelation(ctx context.Context, rel model.Relation) error {
+	n, err := r.q.DeleteMovieRelation(ctx, dbGen.DeleteMovieRelationParams{
+		MovieID:      rel.MovieID,
+		RelationType: string(rel.Type),
+		RelatedID:    rel.RelatedID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListRelations returns the relations from and to a movie, ordered by type, movie ID and related ID.
+func (r *repo) ListRelations(ctx context.Context, id string) ([]model.Relation, error) {
+	rows, err := r.q.ListMovieRelations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Relation, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, model.Relation{
+			MovieID:   row.MovieID,
+			Type:      model.RelationType(row.RelationType),
+			RelatedID: row.RelatedID,
+		})
+	}
+
+	return res, nil
+}
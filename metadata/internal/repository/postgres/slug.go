@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/metadata/internal/repository"
+)
+
+// assignSlug gives the movie the slug derived from its title and release date in the
+// transaction of q, see repository.AssignSlug. A former current slug stays in movie_slugs as a
+// redirect. Two writers claiming the same slug are serialized by its primary key, so the later one
+// moves on to the next suffix.
+func assignSlug(ctx context.Context, q *dbGen.Queries, id, title string, releaseDate time.Time) (string, error) {
+	current, err := currentSlug(ctx, q, id)
+	if err != nil {
+		return "", err
+	}
+
+	return repository.AssignSlug(current, title, releaseDate, func(slug string) (bool, error) {
+		if err := q.RetireCurrentSlug(ctx, dbGen.RetireCurrentSlugParams{MovieID: id, Keep: slug}); err != nil {
+			return false, err
+		}
+		n, err := q.ClaimSlug(ctx, dbGen.ClaimSlugParams{Slug: slug, MovieID: id})
+
+		return n > 0, err
+	})
+}
+
+// ResolveSlug returns the ID of the movie that has slug as its current or a former slug.
+func (r *repo) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	id, err := r.q.ResolveSlug(ctx, slug)
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return "", repository.ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// currentSlug returns the current slug of the movie, or "" if it has none yet.
+func currentSlug(ctx context.Context, q *dbGen.Queries, id string) (string, error) {
+	slug, err := q.GetCurrentSlug(ctx, id)
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+
+	return slug, err
+}
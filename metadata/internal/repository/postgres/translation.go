@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/metadata/internal/repository"
+	"movieexample.com/metadata/pkg/model"
+)
+
+// PutTranslation adds or replaces the translation of a movie into t.Locale.
+func (r *repo) PutTranslation(ctx context.Context, movieID string, t model.Translation) error {
+	err := r.q.UpsertTranslation(ctx, dbGen.UpsertTranslationParams{
+		MovieID:     movieID,
+		Locale:      t.Locale,
+		Title:       t.Title,
+		Description: t.Description,
+	})
+	var pgErr *pgconn.PgError
+	if err != nil && errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return repository.ErrNotFound
+	}
+
+	return err
+}
+
+// DeleteTranslation removes the translation of a movie into locale.
+func (r *repo) DeleteTranslation(ctx context.Context, movieID, locale string) error {
+	n, err := r.q.DeleteTranslation(ctx, dbGen.DeleteTranslationParams{MovieID: movieID, Locale: locale})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListTranslations returns the translations of a movie ordered by locale.
+func (r *repo) ListTranslations(ctx context.Context, movieID string) ([]model.Translation, error) {
+	rows, err := r.q.ListTranslations(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Translation, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, model.Translation{
+			Locale:      row.Locale,
+			Title:       row.Title,
+			Description: row.Description,
+		})
+	}
+
+	return res, nil
+}
+
+// ListTranslationsForMovies returns the translations of each of the movies that has any, keyed by
+// movie ID and ordered by locale, with one query.
+func (r *repo) ListTranslationsForMovies(ctx context.Context, movieIDs []string) (map[string][]model.Translation, error) {
+	rows, err := r.q.ListTranslationsForMovies(ctx, movieIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string][]model.Translation)
+	for _, row := range rows {
+		res[row.MovieID] = append(res[row.MovieID], model.Translation{
+			Locale:      row.Locale,
+			Title:       row.Title,
+			Description: row.Description,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"movieexample.com/metadata/pkg/model"
+)
+
+// AssignSlug returns the slug of a record with the given title and release date. The current
+// slug is kept if it already derives from them. Otherwise the derived slug and then the same with
+// a numeric suffix are passed to claim, which makes a slug the current one of the record unless
+// another record has it, and reports whether it did. Repositories call it in the same transaction
+// as the write, so that a record is never stored without its slug. It returns ErrSlugTaken if no
+// suffix is free.
+func AssignSlug(current, title string, releaseDate time.Time, claim func(slug string) (bool, error)) (string, error) {
+	base := model.Slugify(title, releaseDate)
+	if current != "" && model.IsSlugOf(current, base) {
+		return current, nil
+	}
+
+	for n := 1; n <= model.MaxSlugSuffix; n++ {
+		slug := base
+		if n > 1 {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		ok, err := claim(slug)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return slug, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: no free slug for %q", ErrSlugTaken, base)
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// ImageKind is the kind of artwork an image is.
+type ImageKind string
+
+const (
+	ImageKindPoster   ImageKind = "poster"
+	ImageKindStill    ImageKind = "still"
+	ImageKindBackdrop ImageKind = "backdrop"
+)
+
+// Image is a piece of artwork of a movie. The original upload and its thumbnails live in blob
+// storage under their keys; URLs are filled in when images are read and are not stored.
+type Image struct {
+	ID          string         `json:"id"`
+	MovieID     string         `json:"movie_id"`
+	Kind        ImageKind      `json:"kind"`
+	ContentType string         `json:"content_type"`
+	Width       int32          `json:"width"`
+	Height      int32          `json:"height"`
+	Size        int64          `json:"size"`
+	Key         string         `json:"-"`
+	URL         string         `json:"url,omitempty"`
+	Variants    []ImageVariant `json:"variants,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// ImageVariant is a thumbnail generated from an image. Name is the thumbnail size, such as "small".
+type ImageVariant struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Width       int32  `json:"width"`
+	Height      int32  `json:"height"`
+	Key         string `json:"-"`
+	URL         string `json:"url,omitempty"`
+}
@@ -0,0 +1,16 @@
+package model
+
+// ImportSummary reports the outcome of a bulk import.
+type ImportSummary struct {
+	Received int64         `json:"received"`
+	Imported int64         `json:"imported"`
+	Failed   int64         `json:"failed"`
+	Errors   []ImportError `json:"errors,omitempty"`
+}
+
+// ImportError describes why a row of a bulk import was not imported.
+type ImportError struct {
+	Row     int64  `json:"row"`
+	MovieID string `json:"movie_id,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// SortOrder is a string type used to represent the order in which metadata records are listed.
+type SortOrder string
+
+// Supported sort orders. Ties are always broken by ID so paging is stable.
+const (
+	SortByID              = SortOrder("id")
+	SortByTitle           = SortOrder("title")
+	SortByTitleDesc       = SortOrder("title_desc")
+	SortByReleaseDate     = SortOrder("release_date")
+	SortByReleaseDateDesc = SortOrder("release_date_desc")
+)
+
+// Filter narrows down the metadata records returned by a listing.
+// Zero values disable the corresponding condition.
+type Filter struct {
+	Director        string `json:"director,omitempty"`
+	TitlePrefix     string `json:"title_prefix,omitempty"`
+	Genre           string `json:"genre,omitempty"`
+	ReleaseYearFrom int    `json:"release_year_from,omitempty"`
+	ReleaseYearTo   int    `json:"release_year_to,omitempty"`
+}
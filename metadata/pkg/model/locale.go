@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+
+	"golang.org/x/text/language"
+)
+
+// ErrInvalidLocale is returned for locales that are not well-formed BCP 47 language tags.
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// Translation is the title and description of a movie in one locale. An empty description
+// falls back to the next locale of the fallback chain.
+type Translation struct {
+	Locale      string `json:"locale"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// wildcard is the tag an Accept-Language wildcard is parsed as.
+var wildcard = language.Make("mul")
+
+// ParseLocale returns the canonical form of a BCP 47 language tag, such as "pt-BR" for "pt_br".
+func ParseLocale(s string) (string, error) {
+	tag, err := language.Parse(s)
+	if err != nil || tag == language.Und {
+		return "", ErrInvalidLocale
+	}
+
+	return tag.String(), nil
+}
+
+// LocaleChain returns the locales to look translations up in for the given preferred locales,
+// most preferred first. Every locale is followed by its parents, so "pt-BR" yields "pt-BR" and
+// "pt"; the default, untranslated text comes after the chain.
+func LocaleChain(prefs ...string) ([]string, error) {
+	var res []string
+	seen := map[string]bool{}
+	for _, p := range prefs {
+		tag, err := language.Parse(p)
+		if err != nil {
+			return nil, ErrInvalidLocale
+		}
+		for ; tag != language.Und; tag = tag.Parent() {
+			if s := tag.String(); !seen[s] {
+				seen[s] = true
+				res = append(res, s)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// PreferredLocales returns locale followed by the fallback locales, most preferred first,
+// leaving out locale if it is empty.
+func PreferredLocales(locale string, fallbacks ...string) []string {
+	if locale == "" {
+		return fallbacks
+	}
+
+	return append([]string{locale}, fallbacks...)
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header, most preferred first.
+// Wildcards and malformed headers yield no locales.
+func ParseAcceptLanguage(header string) []string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag != language.Und && tag != wildcard {
+			res = append(res, tag.String())
+		}
+	}
+
+	return res
+}
@@ -1,15 +1,33 @@
 package model
 
-import "movieexample.com/gen"
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"movieexample.com/gen"
+)
 
 // MetadataToProto converts a Metadata struct to a gen.Metadata proto.
 // It copies the fields from the Metadata struct to the gen.Metadata proto.
 func MetadataToProto(m *Metadata) *gen.Metadata {
 	return &gen.Metadata{
-		Id:          m.ID,
-		Title:       m.Title,
-		Description: m.Description,
-		Director:    m.Director,
+		Id:               m.ID,
+		Title:            m.Title,
+		Description:      m.Description,
+		Director:         m.Director,
+		Genres:           m.Genres,
+		Credits:          creditsToProto(m.Credits),
+		RuntimeMinutes:   m.RuntimeMinutes,
+		ReleaseDate:      timeToProto(m.ReleaseDate),
+		OriginalLanguage: m.OriginalLanguage,
+		Country:          m.Country,
+		ExternalIds:      externalIDsToProto(m.ExternalIDs),
+		Slug:             m.Slug,
+		Version:          m.Version,
 	}
 }
 
@@ -17,9 +35,295 @@ func MetadataToProto(m *Metadata) *gen.Metadata {
 // It copies the fields from the gen.Metadata proto to the Metadata struct.
 func MetadataFromProto(m *gen.Metadata) *Metadata {
 	return &Metadata{
-		ID:          m.Id,
-		Title:       m.Title,
-		Description: m.Description,
-		Director:    m.Director,
+		ID:               m.Id,
+		Title:            m.Title,
+		Description:      m.Description,
+		Director:         m.Director,
+		Genres:           m.Genres,
+		Credits:          creditsFromProto(m.Credits),
+		RuntimeMinutes:   m.RuntimeMinutes,
+		ReleaseDate:      timeFromProto(m.ReleaseDate),
+		OriginalLanguage: m.OriginalLanguage,
+		Country:          m.Country,
+		ExternalIDs:      externalIDsFromProto(m.ExternalIds),
+		Slug:             m.Slug,
+		Version:          m.Version,
+	}
+}
+
+func creditsToProto(credits []Credit) []*gen.Credit {
+	if credits == nil {
+		return nil
+	}
+	res := make([]*gen.Credit, 0, len(credits))
+	for _, c := range credits {
+		res = append(res, &gen.Credit{
+			Name:      c.Name,
+			Role:      string(c.Role),
+			Character: c.Character,
+			Order:     c.Order,
+		})
+	}
+	return res
+}
+
+func creditsFromProto(credits []*gen.Credit) []Credit {
+	if credits == nil {
+		return nil
+	}
+	res := make([]Credit, 0, len(credits))
+	for _, c := range credits {
+		res = append(res, Credit{
+			Name:      c.Name,
+			Role:      CreditRole(c.Role),
+			Character: c.Character,
+			Order:     c.Order,
+		})
+	}
+	return res
+}
+
+func externalIDsToProto(ids []ExternalID) []*gen.ExternalID {
+	if ids == nil {
+		return nil
+	}
+	res := make([]*gen.ExternalID, 0, len(ids))
+	for _, e := range ids {
+		res = append(res, &gen.ExternalID{Provider: string(e.Provider), Id: e.ID})
+	}
+	return res
+}
+
+func externalIDsFromProto(ids []*gen.ExternalID) []ExternalID {
+	if ids == nil {
+		return nil
+	}
+	res := make([]ExternalID, 0, len(ids))
+	for _, e := range ids {
+		res = append(res, ExternalID{Provider: ExternalIDProvider(e.Provider), ID: e.Id})
+	}
+	return res
+}
+
+// timeToProto leaves unset times out of the proto message instead of sending year 1.
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// FilterFromProto converts a gen.MetadataFilter proto to a Filter struct.
+// A nil filter matches every record.
+func FilterFromProto(f *gen.MetadataFilter) Filter {
+	if f == nil {
+		return Filter{}
+	}
+	return Filter{
+		Director:        f.Director,
+		TitlePrefix:     f.TitlePrefix,
+		Genre:           f.Genre,
+		ReleaseYearFrom: int(f.ReleaseYearFrom),
+		ReleaseYearTo:   int(f.ReleaseYearTo),
+	}
+}
+
+// SortOrderFromProto converts a gen.MetadataSortOrder enum to a SortOrder.
+// Unknown values fall back to ordering by ID.
+func SortOrderFromProto(s gen.MetadataSortOrder) SortOrder {
+	switch s {
+	case gen.MetadataSortOrder_METADATA_SORT_ORDER_TITLE:
+		return SortByTitle
+	case gen.MetadataSortOrder_METADATA_SORT_ORDER_TITLE_DESC:
+		return SortByTitleDesc
+	case gen.MetadataSortOrder_METADATA_SORT_ORDER_RELEASE_DATE:
+		return SortByReleaseDate
+	case gen.MetadataSortOrder_METADATA_SORT_ORDER_RELEASE_DATE_DESC:
+		return SortByReleaseDateDesc
+	default:
+		return SortByID
+	}
+}
+
+// SearchResultToProto converts a SearchResult struct to a gen.SearchResult proto.
+func SearchResultToProto(r SearchResult) *gen.SearchResult {
+	return &gen.SearchResult{
+		Metadata: MetadataToProto(r.Metadata),
+		Score:    r.Score,
+	}
+}
+
+// FieldsFromMask converts the paths of a field mask to update fields.
+// See FieldsFromPaths for the paths that are accepted.
+func FieldsFromMask(mask *fieldmaskpb.FieldMask) ([]Field, error) {
+	return FieldsFromPaths(mask.GetPaths())
+}
+
+// FieldsFromPaths validates field paths against the fields of gen.Metadata and converts them
+// to update fields. Only top-level, writable fields are accepted; repeated paths are collapsed.
+func FieldsFromPaths(paths []string) ([]Field, error) {
+	desc := (&gen.Metadata{}).ProtoReflect().Descriptor().Fields()
+	res := make([]Field, 0, len(paths))
+	for _, p := range paths {
+		switch {
+		case strings.Contains(p, "."):
+			return nil, invalidField(p, "nested paths are not supported")
+		case desc.ByName(protoreflect.Name(p)) == nil:
+			return nil, invalidField(p, "unknown field")
+		case readOnlyFields[Field(p)]:
+			return nil, invalidField(p, "field is read-only")
+		}
+		if !slices.Contains(res, Field(p)) {
+			res = append(res, Field(p))
+		}
+	}
+	return res, nil
+}
+
+// RevisionToProto converts a Revision struct to a gen.MetadataRevision proto.
+func RevisionToProto(r *Revision) *gen.MetadataRevision {
+	changes := make([]*gen.FieldChange, 0, len(r.Changes))
+	for _, c := range r.Changes {
+		changes = append(changes, &gen.FieldChange{
+			Field:    string(c.Field),
+			OldValue: string(c.Old),
+			NewValue: string(c.New),
+		})
+	}
+	return &gen.MetadataRevision{
+		MovieId:   r.MovieID,
+		Version:   r.Version,
+		Author:    r.Author,
+		CreatedAt: timeToProto(r.CreatedAt),
+		Changes:   changes,
+		Metadata:  MetadataToProto(r.Snapshot),
+	}
+}
+
+// ImportSummaryToProto converts an ImportSummary struct to a gen.ImportMetadataResponse proto.
+func ImportSummaryToProto(s ImportSummary) *gen.ImportMetadataResponse {
+	errs := make([]*gen.ImportError, 0, len(s.Errors))
+	for _, e := range s.Errors {
+		errs = append(errs, &gen.ImportError{Row: e.Row, MovieId: e.MovieID, Message: e.Message})
+	}
+	return &gen.ImportMetadataResponse{
+		Received: s.Received,
+		Imported: s.Imported,
+		Failed:   s.Failed,
+		Errors:   errs,
+	}
+}
+
+// ImportSummaryFromProto converts a gen.ImportMetadataResponse proto to an ImportSummary struct.
+func ImportSummaryFromProto(s *gen.ImportMetadataResponse) ImportSummary {
+	res := ImportSummary{
+		Received: s.Received,
+		Imported: s.Imported,
+		Failed:   s.Failed,
+	}
+	for _, e := range s.Errors {
+		res.Errors = append(res.Errors, ImportError{Row: e.Row, MovieID: e.MovieId, Message: e.Message})
+	}
+	return res
+}
+
+// ImageToProto converts an Image to its proto representation.
+func ImageToProto(img *Image) *gen.Image {
+	res := &gen.Image{
+		Id:          img.ID,
+		MovieId:     img.MovieID,
+		Kind:        string(img.Kind),
+		ContentType: img.ContentType,
+		Width:       img.Width,
+		Height:      img.Height,
+		Size:        img.Size,
+		Url:         img.URL,
+		CreatedAt:   timeToProto(img.CreatedAt),
+	}
+	for _, v := range img.Variants {
+		res.Variants = append(res.Variants, &gen.ImageVariant{
+			Name:        v.Name,
+			ContentType: v.ContentType,
+			Width:       v.Width,
+			Height:      v.Height,
+			Url:         v.URL,
+		})
+	}
+	return res
+}
+
+// ImagesToProto converts a list of images to their proto representation.
+func ImagesToProto(imgs []Image) []*gen.Image {
+	var res []*gen.Image
+	for i := range imgs {
+		res = append(res, ImageToProto(&imgs[i]))
+	}
+	return res
+}
+
+// TranslationToProto converts a Translation to its proto representation.
+func TranslationToProto(t Translation) *gen.Translation {
+	return &gen.Translation{
+		Locale:      t.Locale,
+		Title:       t.Title,
+		Description: t.Description,
+	}
+}
+
+// TranslationFromProto converts a proto Translation to a Translation.
+func TranslationFromProto(t *gen.Translation) *Translation {
+	return &Translation{
+		Locale:      t.Locale,
+		Title:       t.Title,
+		Description: t.Description,
+	}
+}
+
+// RelationToProto converts a Relation to its proto representation.
+func RelationToProto(r Relation) *gen.MetadataRelation {
+	return &gen.MetadataRelation{
+		MovieId:   r.MovieID,
+		Type:      string(r.Type),
+		RelatedId: r.RelatedID,
+	}
+}
+
+// RelationFromProto converts a proto MetadataRelation to a Relation.
+func RelationFromProto(r *gen.MetadataRelation) Relation {
+	return Relation{
+		MovieID:   r.GetMovieId(),
+		Type:      RelationType(r.GetType()),
+		RelatedID: r.GetRelatedId(),
+	}
+}
+
+// RelatedMoviesToProto converts a list of related movies to their proto representation.
+func RelatedMoviesToProto(related []RelatedMovie) []*gen.RelatedMovie {
+	var res []*gen.RelatedMovie
+	for _, r := range related {
+		res = append(res, &gen.RelatedMovie{
+			Relation: RelationToProto(r.Relation),
+			Metadata: MetadataToProto(r.Metadata),
+		})
+	}
+	return res
+}
+
+// RelatedMoviesFromProto converts a list of proto related movies to RelatedMovies.
+func RelatedMoviesFromProto(related []*gen.RelatedMovie) []RelatedMovie {
+	var res []RelatedMovie
+	for _, r := range related {
+		res = append(res, RelatedMovie{
+			Relation: RelationFromProto(r.Relation),
+			Metadata: MetadataFromProto(r.Metadata),
+		})
 	}
+	return res
 }
@@ -1,8 +1,69 @@
 package model
 
+import (
+	"slices"
+	"time"
+)
+
+// CreditRole is a string type used to represent the part a person played in a movie.
+type CreditRole string
+
+// Known credit roles for cast and crew members.
+const (
+	CreditRoleActor    = CreditRole("actor")
+	CreditRoleDirector = CreditRole("director")
+	CreditRoleWriter   = CreditRole("writer")
+	CreditRoleProducer = CreditRole("producer")
+	CreditRoleComposer = CreditRole("composer")
+)
+
+// Metadata is the catalog record of a movie. Slug and Version are managed by the service.
 type Metadata struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Director    string `json:"director"`
+	ID               string       `json:"id"`
+	Title            string       `json:"title"`
+	Description      string       `json:"description"`
+	Director         string       `json:"director"`
+	Genres           []string     `json:"genres,omitempty"`
+	Credits          []Credit     `json:"credits,omitempty"`
+	RuntimeMinutes   int32        `json:"runtime_minutes,omitempty"`
+	ReleaseDate      time.Time    `json:"release_date"`
+	OriginalLanguage string       `json:"original_language,omitempty"`
+	Country          string       `json:"country,omitempty"`
+	ExternalIDs      []ExternalID `json:"external_ids,omitempty"`
+	Slug             string       `json:"slug,omitempty"`
+	Version          int64        `json:"version"`
+}
+
+// Clone returns a copy of m that shares no slices with it.
+func (m *Metadata) Clone() *Metadata {
+	res := *m
+	res.Genres = slices.Clone(m.Genres)
+	res.Credits = slices.Clone(m.Credits)
+	res.ExternalIDs = slices.Clone(m.ExternalIDs)
+	return &res
+}
+
+// Credit represents a cast or crew member of a movie.
+// Character is only meaningful for actors, Order is the billing position.
+type Credit struct {
+	Name      string     `json:"name"`
+	Role      CreditRole `json:"role"`
+	Character string     `json:"character,omitempty"`
+	Order     int32      `json:"order"`
+}
+
+// ExternalIDProvider is a string type used to name a catalog that identifies movies by its own IDs.
+type ExternalIDProvider string
+
+// Known external ID providers.
+const (
+	ExternalIDProviderIMDb = ExternalIDProvider("imdb")
+	ExternalIDProviderTMDb = ExternalIDProvider("tmdb")
+)
+
+// ExternalID identifies a movie in a partner catalog. A movie has at most one ID per provider
+// and an ID of a provider belongs to at most one movie.
+type ExternalID struct {
+	Provider ExternalIDProvider `json:"provider"`
+	ID       string             `json:"id"`
 }
@@ -0,0 +1,36 @@
+package model
+
+// RelationType is the kind of link a relation makes from one movie to another.
+type RelationType string
+
+const (
+	// RelationSequelOf links a movie to the one it is a sequel of.
+	RelationSequelOf RelationType = "sequel_of"
+	// RelationRemakeOf links a movie to the one it is a remake of.
+	RelationRemakeOf RelationType = "remake_of"
+	// RelationPartOfCollection links a movie to the record of a collection or franchise it belongs to.
+	RelationPartOfCollection RelationType = "part_of_collection"
+)
+
+// Relation is a typed, directed link between two metadata records that reads
+// "MovieID is Type RelatedID", such as "The Empire Strikes Back is sequel_of Star Wars".
+type Relation struct {
+	MovieID   string       `json:"movie_id"`
+	Type      RelationType `json:"type"`
+	RelatedID string       `json:"related_id"`
+}
+
+// Other returns the ID at the other end of the relation from id.
+func (r Relation) Other(id string) string {
+	if r.MovieID == id {
+		return r.RelatedID
+	}
+
+	return r.MovieID
+}
+
+// RelatedMovie is a movie related to another one, with the relation that links them.
+type RelatedMovie struct {
+	Relation Relation  `json:"relation"`
+	Metadata *Metadata `json:"metadata"`
+}
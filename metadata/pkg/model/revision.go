@@ -0,0 +1,94 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// Revision is an immutable record of one write to a metadata record.
+// Version is the version of the record the write produced.
+type Revision struct {
+	MovieID   string    `json:"movie_id"`
+	Version   int64     `json:"version"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	Changes   []Change  `json:"changes"`
+	// Snapshot is the full record as written by this revision.
+	Snapshot *Metadata `json:"snapshot"`
+}
+
+// Change is the change of one field between two revisions. Values are JSON encoded,
+// with empty lists and unset dates encoded as null.
+type Change struct {
+	Field Field           `json:"field"`
+	Old   json.RawMessage `json:"old"`
+	New   json.RawMessage `json:"new"`
+}
+
+// Fields lists every field that is tracked in revisions, in the order of the gen.Metadata proto.
+var Fields = []Field{
+	FieldTitle,
+	FieldDescription,
+	FieldDirector,
+	FieldGenres,
+	FieldCredits,
+	FieldRuntimeMinutes,
+	FieldReleaseDate,
+	FieldOriginalLanguage,
+	FieldCountry,
+	FieldExternalIDs,
+}
+
+// Diff returns the changes that turn from into to. A nil from stands for an empty record,
+// so the diff of a new record lists the fields it sets.
+func Diff(from, to *Metadata) []Change {
+	if from == nil {
+		from = &Metadata{}
+	}
+	var res []Change
+	for _, f := range Fields {
+		oldValue := fieldJSON(from, f)
+		newValue := fieldJSON(to, f)
+		if !bytes.Equal(oldValue, newValue) {
+			res = append(res, Change{Field: f, Old: oldValue, New: newValue})
+		}
+	}
+	return res
+}
+
+func fieldJSON(m *Metadata, f Field) json.RawMessage {
+	var v any
+	switch f {
+	case FieldTitle:
+		v = m.Title
+	case FieldDescription:
+		v = m.Description
+	case FieldDirector:
+		v = m.Director
+	case FieldGenres:
+		if len(m.Genres) > 0 {
+			v = m.Genres
+		}
+	case FieldCredits:
+		if len(m.Credits) > 0 {
+			v = m.Credits
+		}
+	case FieldRuntimeMinutes:
+		v = m.RuntimeMinutes
+	case FieldReleaseDate:
+		if !m.ReleaseDate.IsZero() {
+			v = m.ReleaseDate
+		}
+	case FieldOriginalLanguage:
+		v = m.OriginalLanguage
+	case FieldCountry:
+		v = m.Country
+	case FieldExternalIDs:
+		if len(m.ExternalIDs) > 0 {
+			v = m.ExternalIDs
+		}
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
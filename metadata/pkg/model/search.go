@@ -0,0 +1,8 @@
+package model
+
+// SearchResult is a metadata record matched by a full-text search along with its relevance.
+// Higher scores rank first; scores are only comparable within a single search.
+type SearchResult struct {
+	Metadata *Metadata `json:"metadata"`
+	Score    float64   `json:"score"`
+}
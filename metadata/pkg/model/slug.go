@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugTitleLength caps the part of a slug taken from the title, in bytes.
+const MaxSlugTitleLength = 80
+
+// MaxSlugSuffix caps the numeric suffix added to a slug to make it unique, as in "heat-1995-2".
+const MaxSlugSuffix = 100
+
+// fallbackSlug is the slug of titles without a single ASCII letter or digit.
+const fallbackSlug = "movie"
+
+// Slugify returns the URL slug of a movie with the given title and release date, such as
+// "the-dark-knight-2008". Accents are stripped, other characters than ASCII letters and digits
+// become dashes and an unknown release date leaves the year out.
+func Slugify(title string, releaseDate time.Time) string {
+	t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)))
+	folded, _, err := transform.String(t, title)
+	if err != nil {
+		folded = title
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(folded) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugTitleLength {
+		// cut at the last word boundary rather than in the middle of a word
+		cut := slug[:MaxSlugTitleLength]
+		if slug[MaxSlugTitleLength] != '-' {
+			if i := strings.LastIndexByte(cut, '-'); i > 0 {
+				cut = cut[:i]
+			}
+		}
+		slug = strings.TrimSuffix(cut, "-")
+	}
+	if slug == "" {
+		slug = fallbackSlug
+	}
+	if !releaseDate.IsZero() {
+		slug += "-" + strconv.Itoa(releaseDate.Year())
+	}
+
+	return slug
+}
+
+// IsSlugOf reports whether slug is base itself or base with a numeric suffix from 2 to
+// MaxSlugSuffix added to keep it unique, such as "heat-1995-2". A longer suffix such as a year is
+// not one, so that "heat-1995" is not a slug of "heat".
+func IsSlugOf(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok || strings.HasPrefix(suffix, "0") || strings.HasPrefix(suffix, "+") {
+		return false
+	}
+	n, err := strconv.Atoi(suffix)
+
+	return err == nil && n >= 2 && n <= MaxSlugSuffix
+}
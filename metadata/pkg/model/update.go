@@ -0,0 +1,66 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Field is a string type used to name a metadata field in a partial update.
+// Field names match the gen.Metadata proto field names and the JSON keys of Metadata.
+type Field string
+
+// Fields that can be changed by a partial update. The ID and the version are not updatable.
+const (
+	FieldTitle            = Field("title")
+	FieldDescription      = Field("description")
+	FieldDirector         = Field("director")
+	FieldGenres           = Field("genres")
+	FieldCredits          = Field("credits")
+	FieldRuntimeMinutes   = Field("runtime_minutes")
+	FieldReleaseDate      = Field("release_date")
+	FieldOriginalLanguage = Field("original_language")
+	FieldCountry          = Field("country")
+	FieldExternalIDs      = Field("external_ids")
+)
+
+// ErrInvalidField is returned when an update names an unknown or read-only field.
+var ErrInvalidField = errors.New("invalid update field")
+
+// readOnlyFields are fields of gen.Metadata that are managed by the service.
+var readOnlyFields = map[Field]bool{
+	"id":      true,
+	"slug":    true,
+	"version": true,
+}
+
+// ApplyFields copies the named fields from src to dst, leaving the other fields of dst untouched.
+func ApplyFields(dst, src *Metadata, fields []Field) {
+	for _, f := range fields {
+		switch f {
+		case FieldTitle:
+			dst.Title = src.Title
+		case FieldDescription:
+			dst.Description = src.Description
+		case FieldDirector:
+			dst.Director = src.Director
+		case FieldGenres:
+			dst.Genres = src.Genres
+		case FieldCredits:
+			dst.Credits = src.Credits
+		case FieldRuntimeMinutes:
+			dst.RuntimeMinutes = src.RuntimeMinutes
+		case FieldReleaseDate:
+			dst.ReleaseDate = src.ReleaseDate
+		case FieldOriginalLanguage:
+			dst.OriginalLanguage = src.OriginalLanguage
+		case FieldCountry:
+			dst.Country = src.Country
+		case FieldExternalIDs:
+			dst.ExternalIDs = src.ExternalIDs
+		}
+	}
+}
+
+func invalidField(path string, reason string) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalidField, path, reason)
+}
@@ -3,6 +3,7 @@ package movie
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	metadatamodel "movieexample.com/metadata/pkg/model"
 	"movieexample.com/movie/internal/gateway"
@@ -12,18 +13,36 @@ import (
 
 var ErrNotFound = errors.New("movie metadata not found")
 
+// ErrInvalidLocale is returned when a requested locale is not a BCP 47 language tag.
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// ErrInvalidLeaderboard is returned for an unknown leaderboard or a query the rating service rejects.
+var ErrInvalidLeaderboard = errors.New("invalid leaderboard")
+
 // ratingGateway is an interface that provides methods for interacting with a rating system.
 // GetAggregatedRating retrieves the aggregated rating for a given record ID and record type.
 // PutRating stores a new rating for the given record ID and record type.
+// ListTopRated and ListTrending rank records by their ratings, or return gateway.ErrInvalidArgument.
 type ratingGateway interface {
 	GetAggregatedRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (float64, error)
+	GetAggregatedRatings(ctx context.Context, recordIDs []ratingmodel.RecordID, recordType ratingmodel.RecordType) (map[ratingmodel.RecordID]float64, error)
 	PutRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType, rating *ratingmodel.Rating) error
+	ListTopRated(ctx context.Context, query ratingmodel.LeaderboardQuery) ([]ratingmodel.LeaderboardEntry, error)
+	ListTrending(ctx context.Context, query ratingmodel.LeaderboardQuery) ([]ratingmodel.LeaderboardEntry, error)
 }
 
 // metadataGateway is an interface that provides methods for interacting with a metadata system.
-// Get retrieves the metadata for the given ID.
+// Get retrieves the metadata for the given ID, translated into the first of the preferred
+// locales that has a translation, see metadatamodel.LocaleChain. No locales leave it untranslated.
+// GetBySlug does the same for a current or former slug of the movie.
+// GetRelated returns the live movies related to the given one, translated as Get does.
+// GetMany returns the live movies among the given IDs with one call, translated as Get does;
+// unknown and deleted movies are left out.
 type metadataGateway interface {
-	Get(ctx context.Context, id string) (*metadatamodel.Metadata, error)
+	Get(ctx context.Context, id string, locales []string) (*metadatamodel.Metadata, error)
+	GetBySlug(ctx context.Context, slug string, locales []string) (*metadatamodel.Metadata, error)
+	GetRelated(ctx context.Context, id string, locales []string) ([]metadatamodel.RelatedMovie, error)
+	GetMany(ctx context.Context, ids []string, locales []string) ([]*metadatamodel.Metadata, error)
 }
 
 // Controller is the main struct for the movie controller. It contains the necessary gateways
@@ -47,16 +66,63 @@ func New(ratingGateway ratingGateway, metadataGateway metadataGateway) *Controll
 // metadataGateway and the aggregated rating from the ratingGateway, and returns a
 // model.MovieDetails struct containing the metadata and rating.
 // If the movie metadata or rating is not found, it returns ErrNotFound.
-func (c *Controller) Get(ctx context.Context, id string) (*model.MovieDetails, error) {
+// The title and description are translated into the preferred locales, most preferred first,
+// falling back from one to the next as metadatamodel.LocaleChain does.
+// With includeRelated the sequels, remakes and collections the movie is related to are added
+// with their own aggregated ratings.
+func (c *Controller) Get(ctx context.Context, id string, locales []string, includeRelated bool) (*model.MovieDetails, error) {
 	if id == "" {
 		return nil, errors.New("empty id")
 	}
-	metadata, err := c.metadataGateway.Get(ctx, id)
+	locales, err := canonicalLocales(locales)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := c.metadataGateway.Get(ctx, id, locales)
 	if err != nil && errors.Is(err, gateway.ErrNotFound) {
 		return nil, ErrNotFound
 	} else if err != nil {
 		return nil, err
 	}
+	return c.details(ctx, id, metadata, locales, includeRelated)
+}
+
+// GetBySlug retrieves the movie details for a current or former slug of the movie, as Get does
+// for an ID. The current slug is in the Slug field of the metadata, so callers can tell a former
+// slug by comparing it with the one they asked for.
+func (c *Controller) GetBySlug(ctx context.Context, slug string, locales []string, includeRelated bool) (*model.MovieDetails, error) {
+	if slug == "" {
+		return nil, errors.New("empty slug")
+	}
+	locales, err := canonicalLocales(locales)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := c.metadataGateway.GetBySlug(ctx, slug, locales)
+	if err != nil && errors.Is(err, gateway.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return c.details(ctx, metadata.ID, metadata, locales, includeRelated)
+}
+
+// canonicalLocales returns the canonical form of the locales, or ErrInvalidLocale.
+func canonicalLocales(locales []string) ([]string, error) {
+	var res []string
+	for _, l := range locales {
+		locale, err := metadatamodel.ParseLocale(l)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", ErrInvalidLocale, l)
+		}
+		res = append(res, locale)
+	}
+	return res, nil
+}
+
+// details returns the details of a movie made of its metadata and its aggregated rating, and of
+// its related movies if asked for.
+func (c *Controller) details(ctx context.Context, id string, metadata *metadatamodel.Metadata, locales []string, includeRelated bool) (*model.MovieDetails, error) {
 	details := &model.MovieDetails{
 		Metadata: *metadata,
 	}
@@ -67,5 +133,100 @@ func (c *Controller) Get(ctx context.Context, id string) (*model.MovieDetails, e
 		return nil, err
 	}
 	details.Rating = &rating
+	if includeRelated {
+		details.Related, err = c.related(ctx, id, locales)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return details, nil
 }
+
+// related returns the movies related to a movie with their aggregated ratings. A related movie
+// that has no ratings yet is kept with a nil rating.
+func (c *Controller) related(ctx context.Context, id string, locales []string) ([]model.RelatedMovieDetails, error) {
+	related, err := c.metadataGateway.GetRelated(ctx, id, locales)
+	if err != nil {
+		return nil, err
+	}
+	if len(related) == 0 {
+		return nil, nil
+	}
+	ids := make([]ratingmodel.RecordID, len(related))
+	for i, r := range related {
+		ids[i] = ratingmodel.RecordID(r.Metadata.ID)
+	}
+	ratings, err := c.ratingGateway.GetAggregatedRatings(ctx, ids, ratingmodel.RecordTypeMovie)
+	if err != nil {
+		return nil, err
+	}
+	var res []model.RelatedMovieDetails
+	for _, r := range related {
+		details := model.RelatedMovieDetails{
+			Relation: r.Relation,
+			Metadata: *r.Metadata,
+		}
+		if rating, ok := ratings[ratingmodel.RecordID(r.Metadata.ID)]; ok {
+			details.Rating = &rating
+		}
+		res = append(res, details)
+	}
+	return res, nil
+}
+
+// Leaderboard returns the movies ranked on a top_rated or trending leaderboard of the rating
+// service with their metadata, translated into the preferred locales as Get does. The metadata of
+// the whole page is read with one call. Movies whose metadata was deleted are left out, so ranks
+// may have gaps and the page may be shorter than the limit.
+func (c *Controller) Leaderboard(ctx context.Context, leaderboard ratingmodel.Leaderboard, query ratingmodel.LeaderboardQuery, locales []string) ([]model.LeaderboardMovie, error) {
+	locales, err := canonicalLocales(locales)
+	if err != nil {
+		return nil, err
+	}
+	query.RecordType = ratingmodel.RecordTypeMovie
+	var entries []ratingmodel.LeaderboardEntry
+	switch leaderboard {
+	case ratingmodel.LeaderboardTopRated:
+		entries, err = c.ratingGateway.ListTopRated(ctx, query)
+	case ratingmodel.LeaderboardTrending:
+		entries, err = c.ratingGateway.ListTrending(ctx, query)
+	default:
+		return nil, fmt.Errorf("%w %q, want top_rated or trending", ErrInvalidLeaderboard, leaderboard)
+	}
+	if err != nil && errors.Is(err, gateway.ErrInvalidArgument) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLeaderboard, err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = string(e.RecordID)
+	}
+	ms, err := c.metadataGateway.GetMany(ctx, ids, locales)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*metadatamodel.Metadata, len(ms))
+	for _, m := range ms {
+		byID[m.ID] = m
+	}
+
+	res := make([]model.LeaderboardMovie, 0, len(entries))
+	for _, e := range entries {
+		metadata, ok := byID[string(e.RecordID)]
+		if !ok {
+			continue
+		}
+		res = append(res, model.LeaderboardMovie{
+			Rank:     e.Rank,
+			Rating:   e.Mean,
+			Count:    e.Count,
+			Metadata: *metadata,
+		})
+	}
+	return res, nil
+}
@@ -10,8 +10,10 @@ import (
 
 func TestController_Get(t *testing.T) {
 	type args struct {
-		ctx context.Context
-		id  string
+		ctx            context.Context
+		id             string
+		locales        []string
+		includeRelated bool
 	}
 	tests := []struct {
 		name    string
@@ -25,7 +27,7 @@ func TestController_Get(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.c.Get(tt.args.ctx, tt.args.id)
+			got, err := tt.c.Get(tt.args.ctx, tt.args.id, tt.args.locales, tt.args.includeRelated)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Controller.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
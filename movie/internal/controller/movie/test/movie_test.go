@@ -3,12 +3,15 @@ package movie_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	gen "movieexample.com/gen/mock/movie/repository"
 	modelMetadata "movieexample.com/metadata/pkg/model"
 	"movieexample.com/movie/internal/controller/movie"
+	"movieexample.com/movie/internal/gateway"
+	"movieexample.com/movie/pkg/model"
 	ratingModel "movieexample.com/rating/pkg/model"
 )
 
@@ -21,11 +24,131 @@ func TestGetMovieDetails(t *testing.T) {
 	movieController := movie.New(ratingGatewayMock, metaGatewayMock)
 	ctx := context.Background()
 	id := "id"
-	metaGatewayMock.EXPECT().Get(ctx, id).Return(&modelMetadata.Metadata{}, nil)
+	metaGatewayMock.EXPECT().Get(ctx, id, nil).Return(&modelMetadata.Metadata{}, nil)
 
 	ratingGatewayMock.EXPECT().GetAggregatedRating(ctx, ratingModel.RecordID(id), ratingModel.RecordTypeMovie).Return(float64(5.0), nil)
 
-	md, err := movieController.Get(ctx, id)
+	md, err := movieController.Get(ctx, id, nil, false)
 	assert.NoError(t, err)
 	assert.Equal(t, float64(5.0), *md.Rating)
 }
+
+func TestGetDeletedMovie(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metaGatewayMock := gen.NewMockmetadataGateway(ctrl)
+	ratingGatewayMock := gen.NewMockratingGateway(ctrl)
+
+	movieController := movie.New(ratingGatewayMock, metaGatewayMock)
+	ctx := context.Background()
+	id := "deleted"
+	metaGatewayMock.EXPECT().Get(ctx, id, nil).Return(nil, gateway.ErrNotFound)
+
+	_, err := movieController.Get(ctx, id, nil, false)
+	assert.ErrorIs(t, err, movie.ErrNotFound)
+}
+
+func TestGetMovieDetailsLocalized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metaGatewayMock := gen.NewMockmetadataGateway(ctrl)
+	ratingGatewayMock := gen.NewMockratingGateway(ctrl)
+
+	movieController := movie.New(ratingGatewayMock, metaGatewayMock)
+	ctx := context.Background()
+	id := "id"
+	metaGatewayMock.EXPECT().Get(ctx, id, []string{"pt-BR", "en"}).Return(&modelMetadata.Metadata{Title: "Cidade de Deus"}, nil)
+	ratingGatewayMock.EXPECT().GetAggregatedRating(ctx, ratingModel.RecordID(id), ratingModel.RecordTypeMovie).Return(float64(4.5), nil)
+
+	md, err := movieController.Get(ctx, id, []string{"pt_br", "en"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "Cidade de Deus", md.Metadata.Title)
+
+	_, err = movieController.Get(ctx, id, []string{"en", "not a locale"}, false)
+	assert.ErrorIs(t, err, movie.ErrInvalidLocale)
+}
+
+func TestGetMovieDetailsBySlug(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metaGatewayMock := gen.NewMockmetadataGateway(ctrl)
+	ratingGatewayMock := gen.NewMockratingGateway(ctrl)
+
+	movieController := movie.New(ratingGatewayMock, metaGatewayMock)
+	ctx := context.Background()
+	metaGatewayMock.EXPECT().GetBySlug(ctx, "dark-knight-2008", nil).Return(&modelMetadata.Metadata{ID: "id", Slug: "the-dark-knight-2008"}, nil)
+	ratingGatewayMock.EXPECT().GetAggregatedRating(ctx, ratingModel.RecordID("id"), ratingModel.RecordTypeMovie).Return(float64(4.5), nil)
+
+	md, err := movieController.GetBySlug(ctx, "dark-knight-2008", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "the-dark-knight-2008", md.Metadata.Slug)
+	assert.Equal(t, float64(4.5), *md.Rating)
+
+	metaGatewayMock.EXPECT().GetBySlug(ctx, "unknown", nil).Return(nil, gateway.ErrNotFound)
+	_, err = movieController.GetBySlug(ctx, "unknown", nil, false)
+	assert.ErrorIs(t, err, movie.ErrNotFound)
+}
+
+func TestGetMovieDetailsWithRelated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metaGatewayMock := gen.NewMockmetadataGateway(ctrl)
+	ratingGatewayMock := gen.NewMockratingGateway(ctrl)
+
+	movieController := movie.New(ratingGatewayMock, metaGatewayMock)
+	ctx := context.Background()
+	id := "toy-story"
+	sequel := modelMetadata.Relation{MovieID: "toy-story-2", Type: modelMetadata.RelationSequelOf, RelatedID: id}
+	collection := modelMetadata.Relation{MovieID: id, Type: modelMetadata.RelationPartOfCollection, RelatedID: "toy-story-collection"}
+	metaGatewayMock.EXPECT().Get(ctx, id, nil).Return(&modelMetadata.Metadata{ID: id}, nil)
+	metaGatewayMock.EXPECT().GetRelated(ctx, id, nil).Return([]modelMetadata.RelatedMovie{
+		{Relation: collection, Metadata: &modelMetadata.Metadata{ID: "toy-story-collection"}},
+		{Relation: sequel, Metadata: &modelMetadata.Metadata{ID: "toy-story-2"}},
+	}, nil)
+	ratingGatewayMock.EXPECT().GetAggregatedRating(ctx, ratingModel.RecordID(id), ratingModel.RecordTypeMovie).Return(float64(4.5), nil)
+	ratingGatewayMock.EXPECT().GetAggregatedRatings(ctx, []ratingModel.RecordID{"toy-story-collection", "toy-story-2"}, ratingModel.RecordTypeMovie).
+		Return(map[ratingModel.RecordID]float64{"toy-story-2": 4}, nil)
+
+	md, err := movieController.Get(ctx, id, nil, true)
+	assert.NoError(t, err)
+	if assert.Len(t, md.Related, 2) {
+		assert.Equal(t, collection, md.Related[0].Relation)
+		assert.Nil(t, md.Related[0].Rating, "unrated movies have no rating")
+		assert.Equal(t, sequel, md.Related[1].Relation)
+		assert.Equal(t, float64(4), *md.Related[1].Rating)
+	}
+}
+
+func TestGetMovieLeaderboard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metaGatewayMock := gen.NewMockmetadataGateway(ctrl)
+	ratingGatewayMock := gen.NewMockratingGateway(ctrl)
+
+	movieController := movie.New(ratingGatewayMock, metaGatewayMock)
+	ctx := context.Background()
+	query := ratingModel.LeaderboardQuery{RecordType: ratingModel.RecordTypeMovie, MinVotes: 10, Window: 7 * 24 * time.Hour}
+	ratingGatewayMock.EXPECT().ListTrending(ctx, query).Return([]ratingModel.LeaderboardEntry{
+		{Rank: 1, RecordID: "1", RecordType: ratingModel.RecordTypeMovie, Count: 40, Mean: 4.5},
+		{Rank: 2, RecordID: "deleted", RecordType: ratingModel.RecordTypeMovie, Count: 30, Mean: 3},
+		{Rank: 3, RecordID: "2", RecordType: ratingModel.RecordTypeMovie, Count: 20, Mean: 4},
+	}, nil)
+	metaGatewayMock.EXPECT().GetMany(ctx, []string{"1", "deleted", "2"}, []string{"de"}).Return([]*modelMetadata.Metadata{
+		{ID: "1", Title: "Der Film"},
+		{ID: "2", Title: "Der Film 2"},
+	}, nil)
+
+	movies, err := movieController.Leaderboard(ctx, ratingModel.LeaderboardTrending, ratingModel.LeaderboardQuery{MinVotes: 10, Window: 7 * 24 * time.Hour}, []string{"de"})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.LeaderboardMovie{
+		{Rank: 1, Rating: 4.5, Count: 40, Metadata: modelMetadata.Metadata{ID: "1", Title: "Der Film"}},
+		{Rank: 3, Rating: 4, Count: 20, Metadata: modelMetadata.Metadata{ID: "2", Title: "Der Film 2"}},
+	}, movies)
+
+	ratingGatewayMock.EXPECT().ListTopRated(ctx, ratingModel.LeaderboardQuery{RecordType: ratingModel.RecordTypeMovie, Limit: -1}).Return(nil, gateway.ErrInvalidArgument)
+	_, err = movieController.Leaderboard(ctx, ratingModel.LeaderboardTopRated, ratingModel.LeaderboardQuery{Limit: -1}, nil)
+	assert.ErrorIs(t, err, movie.ErrInvalidLeaderboard)
+
+	_, err = movieController.Leaderboard(ctx, "most_watched", ratingModel.LeaderboardQuery{}, nil)
+	assert.ErrorIs(t, err, movie.ErrInvalidLeaderboard)
+}
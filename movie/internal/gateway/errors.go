@@ -2,7 +2,9 @@ package gateway
 
 import "errors"
 
-// ErrNotFound is returned when a requested resource is not found.
 var (
+	// ErrNotFound is returned when a requested resource is not found.
 	ErrNotFound = errors.New("not found")
+	// ErrInvalidArgument is returned when the service rejects the arguments of a request.
+	ErrInvalidArgument = errors.New("invalid argument")
 )
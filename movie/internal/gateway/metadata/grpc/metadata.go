@@ -8,6 +8,7 @@ import (
 	"movieexample.com/gen"
 	"movieexample.com/internal/grpcutil"
 	"movieexample.com/metadata/pkg/model"
+	"movieexample.com/movie/internal/gateway"
 	"movieexample.com/pkg/discovery"
 )
 
@@ -21,8 +22,29 @@ func New(registry discovery.Registry) *Gateway {
 	return &Gateway{registry}
 }
 
-// Get returns movie metadata by a movie id.
-func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
+// Get returns movie metadata by a movie id, translated into the preferred locales, or
+// gateway.ErrNotFound if the movie does not exist or was deleted.
+func (g *Gateway) Get(ctx context.Context, id string, locales []string) (*model.Metadata, error) {
+	locale, fallbacks := splitLocales(locales)
+	return g.get(ctx, &gen.GetMetadataRequest{MovieId: id, Locale: locale, FallbackLocales: fallbacks})
+}
+
+// GetBySlug returns movie metadata by a current or former slug of the movie, translated into
+// the preferred locales. The current slug is in the Slug field of the metadata.
+func (g *Gateway) GetBySlug(ctx context.Context, slug string, locales []string) (*model.Metadata, error) {
+	locale, fallbacks := splitLocales(locales)
+	return g.get(ctx, &gen.GetMetadataRequest{Slug: slug, Locale: locale, FallbackLocales: fallbacks})
+}
+
+// splitLocales splits the preferred locales into the most preferred one and the fallbacks.
+func splitLocales(locales []string) (string, []string) {
+	if len(locales) == 0 {
+		return "", nil
+	}
+	return locales[0], locales[1:]
+}
+
+func (g *Gateway) get(ctx context.Context, req *gen.GetMetadataRequest) (*model.Metadata, error) {
 	conn, err := grpcutil.ServiceConnection(ctx, "metadata", g.registry)
 	if err != nil {
 		return nil, err
@@ -32,11 +54,14 @@ func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	var resp *gen.GetMetadataResponse
 	const maxRetries = 5
 	for i := 0; i < maxRetries; i++ {
-		resp, err = client.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: id})
+		resp, err = client.GetMetadata(ctx, req)
 		if err != nil {
 			if shouldRetry(err) {
 				continue
 			}
+			if status.Code(err) == codes.NotFound {
+				return nil, gateway.ErrNotFound
+			}
 			return nil, err
 		}
 		return model.MetadataFromProto(resp.Metadata), nil
@@ -44,6 +69,63 @@ func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	return nil, err
 }
 
+// GetRelated returns the live movies related to a movie, translated into the preferred locales,
+// or gateway.ErrNotFound if the movie does not exist or was deleted.
+func (g *Gateway) GetRelated(ctx context.Context, id string, locales []string) ([]model.RelatedMovie, error) {
+	locale, fallbacks := splitLocales(locales)
+	conn, err := grpcutil.ServiceConnection(ctx, "metadata", g.registry)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := gen.NewMetadataServiceClient(conn)
+	var resp *gen.GetRelatedMoviesResponse
+	const maxRetries = 5
+	for i := 0; i < maxRetries; i++ {
+		resp, err = client.GetRelatedMovies(ctx, &gen.GetRelatedMoviesRequest{MovieId: id, Locale: locale, FallbackLocales: fallbacks})
+		if err != nil {
+			if shouldRetry(err) {
+				continue
+			}
+			if status.Code(err) == codes.NotFound {
+				return nil, gateway.ErrNotFound
+			}
+			return nil, err
+		}
+		return model.RelatedMoviesFromProto(resp.Related), nil
+	}
+	return nil, err
+}
+
+// GetMany returns the live movies among ids with one call, ordered by ID and translated into the
+// preferred locales. Unknown and deleted movies are left out.
+func (g *Gateway) GetMany(ctx context.Context, ids []string, locales []string) ([]*model.Metadata, error) {
+	locale, fallbacks := splitLocales(locales)
+	conn, err := grpcutil.ServiceConnection(ctx, "metadata", g.registry)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := gen.NewMetadataServiceClient(conn)
+	var resp *gen.BatchGetMetadataResponse
+	const maxRetries = 5
+	for i := 0; i < maxRetries; i++ {
+		resp, err = client.BatchGetMetadata(ctx, &gen.BatchGetMetadataRequest{MovieIds: ids, Locale: locale, FallbackLocales: fallbacks})
+		if err != nil {
+			if shouldRetry(err) {
+				continue
+			}
+			return nil, err
+		}
+		res := make([]*model.Metadata, 0, len(resp.Metadata))
+		for _, m := range resp.Metadata {
+			res = append(res, model.MetadataFromProto(m))
+		}
+		return res, nil
+	}
+	return nil, err
+}
+
 func shouldRetry(err error) bool {
 	e, ok := status.FromError(err)
 	if !ok {
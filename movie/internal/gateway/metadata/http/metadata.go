@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"strings"
 
 	"movieexample.com/metadata/pkg/model"
 	"movieexample.com/movie/internal/gateway"
@@ -25,10 +26,22 @@ func New(registry discovery.Registry) *Gateway {
 	}
 }
 
-// Get retrieves the metadata for the given ID from the metadata service.
-// The context can be used to cancel or timeout the request.
+// Get retrieves the metadata for the given ID from the metadata service, translated into the
+// preferred locales. The context can be used to cancel or timeout the request.
 // It returns the retrieved metadata or an error if the request failed.
-func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
+func (g *Gateway) Get(ctx context.Context, id string, locales []string) (*model.Metadata, error) {
+	return g.get(ctx, "id", id, locales)
+}
+
+// GetBySlug retrieves the metadata for a current or former slug of the movie, translated into
+// the preferred locales. The redirect the metadata service answers former slugs with is
+// followed, so the current slug is in the Slug field of the metadata.
+func (g *Gateway) GetBySlug(ctx context.Context, slug string, locales []string) (*model.Metadata, error) {
+	return g.get(ctx, "slug", slug, locales)
+}
+
+// get retrieves the metadata of the movie named by the given query parameter.
+func (g *Gateway) get(ctx context.Context, param, value string, locales []string) (*model.Metadata, error) {
 	addrs, err := g.registry.ServiceAddresses(ctx, "metadata")
 	if err != nil {
 		return nil, err
@@ -41,8 +54,9 @@ func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	}
 	req = req.WithContext(ctx)
 	values := req.URL.Query()
-	values.Add("id", id)
+	values.Add(param, value)
 	req.URL.RawQuery = values.Encode()
+	setAcceptLanguage(req, locales)
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -59,3 +73,83 @@ func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	}
 	return &metadata, nil
 }
+
+// GetRelated retrieves the live movies related to a movie from the metadata service, translated
+// into the preferred locales. It returns gateway.ErrNotFound if the movie does not exist.
+func (g *Gateway) GetRelated(ctx context.Context, id string, locales []string) ([]model.RelatedMovie, error) {
+	addrs, err := g.registry.ServiceAddresses(ctx, "metadata")
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("http://%s/%s", addrs[rand.Intn(len(addrs))], "metadata/related")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	values.Add("id", id)
+	req.URL.RawQuery = values.Encode()
+	setAcceptLanguage(req, locales)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, gateway.ErrNotFound
+	} else if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	var body struct {
+		Related []model.RelatedMovie `json:"related"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.Related, nil
+}
+
+// GetMany retrieves the live movies among ids from the metadata service with one request, ordered
+// by ID and translated into the preferred locales. Unknown and deleted movies are left out.
+func (g *Gateway) GetMany(ctx context.Context, ids []string, locales []string) ([]*model.Metadata, error) {
+	addrs, err := g.registry.ServiceAddresses(ctx, "metadata")
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("http://%s/%s", addrs[rand.Intn(len(addrs))], "metadata/batch")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	for _, id := range ids {
+		values.Add("id", id)
+	}
+	req.URL.RawQuery = values.Encode()
+	setAcceptLanguage(req, locales)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	var body struct {
+		Metadata []*model.Metadata `json:"metadata"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.Metadata, nil
+}
+
+// setAcceptLanguage asks the metadata service for the preferred locales, most preferred first,
+// through the Accept-Language header so that it falls back from one to the next.
+func setAcceptLanguage(req *http.Request, locales []string) {
+	if len(locales) > 0 {
+		req.Header.Set("Accept-Language", strings.Join(locales, ", "))
+	}
+}
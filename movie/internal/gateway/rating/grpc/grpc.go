@@ -2,9 +2,14 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"movieexample.com/gen"
 	"movieexample.com/internal/grpcutil"
+	"movieexample.com/movie/internal/gateway"
 	"movieexample.com/pkg/discovery"
 	"movieexample.com/rating/pkg/model"
 )
@@ -28,12 +33,40 @@ func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.Record
 	defer conn.Close()
 	client := gen.NewRatingServiceClient(conn)
 	resp, err := client.GetAggregatedRating(ctx, &gen.GetAggregatedRatingRequest{RecordId: string(recordID), RecordType: string(recordType)})
-	if err != nil {
+	if err != nil && status.Code(err) == codes.NotFound {
+		return 0, gateway.ErrNotFound
+	} else if err != nil {
 		return 0, err
 	}
 	return resp.RatingValue, nil
 }
 
+// GetAggregatedRatings returns the mean ratings of several records of a type with one call,
+// keyed by record ID. Records without ratings are left out.
+func (g *Gateway) GetAggregatedRatings(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]float64, error) {
+	conn, err := grpcutil.ServiceConnection(ctx, "rating", g.registry)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := gen.NewRatingServiceClient(conn)
+	ids := make([]string, len(recordIDs))
+	for i, id := range recordIDs {
+		ids[i] = string(id)
+	}
+	resp, err := client.BatchGetAggregatedRatings(ctx, &gen.BatchGetAggregatedRatingsRequest{RecordIds: ids, RecordType: string(recordType)})
+	if err != nil && status.Code(err) == codes.InvalidArgument {
+		return nil, fmt.Errorf("%w: %s", gateway.ErrInvalidArgument, status.Convert(err).Message())
+	} else if err != nil {
+		return nil, err
+	}
+	res := make(map[model.RecordID]float64, len(resp.Ratings))
+	for id, agg := range resp.Ratings {
+		res[model.RecordID(id)] = agg.RatingValue
+	}
+	return res, nil
+}
+
 // PutRating puts a rating for the given record ID and record type. It returns an error if the rating could not be stored.
 func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
 	conn, err := grpcutil.ServiceConnection(ctx, "rating", g.registry)
@@ -42,9 +75,53 @@ func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, record
 	}
 	defer conn.Close()
 	client := gen.NewRatingServiceClient(conn)
-	_, err = client.PutRating(ctx, &gen.PutRatingRequest{RecordId: string(recordID), RecordType: string(recordType), RatingValue: int32(rating.Value)})
+	_, err = client.PutRating(ctx, &gen.PutRatingRequest{UserId: string(rating.UserID), RecordId: string(recordID), RecordType: string(recordType), RatingValue: int32(rating.Value)})
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// ListTopRated returns the records with the highest mean rating, or ErrInvalidArgument if the
+// rating service rejects the query.
+func (g *Gateway) ListTopRated(ctx context.Context, query model.LeaderboardQuery) ([]model.LeaderboardEntry, error) {
+	conn, err := grpcutil.ServiceConnection(ctx, "rating", g.registry)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := gen.NewRatingServiceClient(conn)
+	req := &gen.ListTopRatedRequest{RecordType: string(query.RecordType), MinVotes: query.MinVotes, Limit: int32(query.Limit)}
+	if query.Window > 0 {
+		req.Window = durationpb.New(query.Window)
+	}
+	resp, err := client.ListTopRated(ctx, req)
+	if err != nil && status.Code(err) == codes.InvalidArgument {
+		return nil, fmt.Errorf("%w: %s", gateway.ErrInvalidArgument, status.Convert(err).Message())
+	} else if err != nil {
+		return nil, err
+	}
+	return model.LeaderboardFromProto(resp.Entries), nil
+}
+
+// ListTrending returns the records with the most ratings in a recent time window, or
+// ErrInvalidArgument if the rating service rejects the query.
+func (g *Gateway) ListTrending(ctx context.Context, query model.LeaderboardQuery) ([]model.LeaderboardEntry, error) {
+	conn, err := grpcutil.ServiceConnection(ctx, "rating", g.registry)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := gen.NewRatingServiceClient(conn)
+	req := &gen.ListTrendingRequest{RecordType: string(query.RecordType), MinVotes: query.MinVotes, Limit: int32(query.Limit)}
+	if query.Window > 0 {
+		req.Window = durationpb.New(query.Window)
+	}
+	resp, err := client.ListTrending(ctx, req)
+	if err != nil && status.Code(err) == codes.InvalidArgument {
+		return nil, fmt.Errorf("%w: %s", gateway.ErrInvalidArgument, status.Convert(err).Message())
+	} else if err != nil {
+		return nil, err
+	}
+	return model.LeaderboardFromProto(resp.Entries), nil
+}
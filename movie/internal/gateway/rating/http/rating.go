@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/rand"
 	"movieexample.com/movie/internal/gateway"
@@ -30,7 +32,7 @@ func New(registry discovery.Registry) *Gateway {
 // The context parameter is used to control the lifetime of the request.
 // The recordID parameter specifies the unique identifier of the record.
 // The recordType parameter specifies the type of the record.
-// The function returns the aggregated rating as a float64 value, and an error if any occurred during the operation.
+// The function returns the mean rating as a float64 value, and an error if any occurred during the operation.
 func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
 	// req, err := http.NewRequest("GET", g.ratingURL+"/rating", nil)
 	addrs, err := g.registry.ServiceAddresses(ctx, "metadata")
@@ -58,11 +60,52 @@ func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.Record
 	} else if res.StatusCode != http.StatusOK {
 		return 0, fmt.Errorf("non 200 response: %v", res)
 	}
-	var rating float64
-	if err := json.NewDecoder(res.Body).Decode(&rating); err != nil {
+	var agg model.RatingAggregate
+	if err := json.NewDecoder(res.Body).Decode(&agg); err != nil {
 		return 0, err
 	}
-	return rating, nil
+	return agg.Mean, nil
+}
+
+// GetAggregatedRatings returns the mean ratings of several records of a type with one request,
+// keyed by record ID. Records without ratings are left out.
+func (g *Gateway) GetAggregatedRatings(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]float64, error) {
+	addrs, err := g.registry.ServiceAddresses(ctx, "rating")
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("http://%s/%s", addrs[rand.Intn(len(addrs))], "rating/aggregates")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	values.Add("type", string(recordType))
+	for _, id := range recordIDs {
+		values.Add("id", string(id))
+	}
+	req.URL.RawQuery = values.Encode()
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusBadRequest {
+		msg, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("%w: %s", gateway.ErrInvalidArgument, strings.TrimSpace(string(msg)))
+	} else if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	var aggs map[model.RecordID]model.RatingAggregate
+	if err := json.NewDecoder(res.Body).Decode(&aggs); err != nil {
+		return nil, err
+	}
+	means := make(map[model.RecordID]float64, len(aggs))
+	for id, agg := range aggs {
+		means[id] = agg.Mean
+	}
+	return means, nil
 }
 
 // PutRating updates the rating for the specified record ID and record type.
@@ -70,7 +113,8 @@ func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.Record
 // The recordID parameter specifies the unique identifier of the record.
 // The recordType parameter specifies the type of the record.
 // The rating parameter specifies the new rating to be set.
-// The function returns an error if any occurred during the operation.
+// The function returns an error if any occurred during the operation; the rating service answers
+// 201 Created rather than 200 OK for a first rating, which is not one.
 func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
 	addrs, err := g.registry.ServiceAddresses(ctx, "rating")
 	if err != nil {
@@ -95,8 +139,57 @@ func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, record
 	defer res.Body.Close()
 	if res.StatusCode == http.StatusNotFound {
 		return gateway.ErrNotFound
-	} else if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %v", res)
+	} else if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return fmt.Errorf("non 2xx response: %v", res)
 	}
 	return nil
 }
+
+// ListTopRated returns the records with the highest mean rating, or ErrInvalidArgument if the
+// rating service rejects the query.
+func (g *Gateway) ListTopRated(ctx context.Context, query model.LeaderboardQuery) ([]model.LeaderboardEntry, error) {
+	return g.leaderboard(ctx, model.LeaderboardTopRated, query)
+}
+
+// ListTrending returns the records with the most ratings in a recent time window, or
+// ErrInvalidArgument if the rating service rejects the query.
+func (g *Gateway) ListTrending(ctx context.Context, query model.LeaderboardQuery) ([]model.LeaderboardEntry, error) {
+	return g.leaderboard(ctx, model.LeaderboardTrending, query)
+}
+
+// leaderboard reads a leaderboard from the rating service.
+func (g *Gateway) leaderboard(ctx context.Context, leaderboard model.Leaderboard, query model.LeaderboardQuery) ([]model.LeaderboardEntry, error) {
+	addrs, err := g.registry.ServiceAddresses(ctx, "rating")
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("http://%s/%s", addrs[rand.Intn(len(addrs))], "rating/leaderboard")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	values.Add("leaderboard", string(leaderboard))
+	values.Add("type", string(query.RecordType))
+	values.Add("minVotes", strconv.FormatInt(query.MinVotes, 10))
+	values.Add("window", query.Window.String())
+	values.Add("limit", strconv.Itoa(query.Limit))
+	req.URL.RawQuery = values.Encode()
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusBadRequest {
+		msg, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("%w: %s", gateway.ErrInvalidArgument, strings.TrimSpace(string(msg)))
+	} else if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	var entries []model.LeaderboardEntry
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
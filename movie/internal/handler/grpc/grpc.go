@@ -9,6 +9,8 @@ import (
 	"movieexample.com/gen"
 	"movieexample.com/metadata/pkg/model"
 	"movieexample.com/movie/internal/controller/movie"
+	moviemodel "movieexample.com/movie/pkg/model"
+	ratingmodel "movieexample.com/rating/pkg/model"
 )
 
 // Handler defines a movie gRPC handler.
@@ -22,21 +24,66 @@ func New(ctrl *movie.Controller) *Handler {
 	return &Handler{ctrl: ctrl}
 }
 
-// GetMovieDetails returns moviie details by id.
+// GetMovieDetails returns moviie details by id or by a current or former slug, translated into
+// the requested locale, and with the related movies and their ratings if asked for.
 func (h *Handler) GetMovieDetails(ctx context.Context, req *gen.GetMovieDetailsRequest) (*gen.GetMovieDetailsResponse, error) {
-	if req == nil || req.MovieId == "" {
+	if req == nil || (req.MovieId == "" && req.Slug == "") {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
 	}
-	m, err := h.ctrl.Get(ctx, req.MovieId)
+	if req.MovieId != "" && req.Slug != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "only one of movie_id and slug may be set")
+	}
+	var m *moviemodel.MovieDetails
+	var err error
+	if req.Slug != "" {
+		m, err = h.ctrl.GetBySlug(ctx, req.Slug, model.PreferredLocales(req.Locale, req.FallbackLocales...), req.IncludeRelated)
+	} else {
+		m, err = h.ctrl.Get(ctx, req.MovieId, model.PreferredLocales(req.Locale, req.FallbackLocales...), req.IncludeRelated)
+	}
 	if err != nil && errors.Is(err, movie.ErrNotFound) {
 		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrInvalidLocale) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	return &gen.GetMovieDetailsResponse{
-		MovieDetails: &gen.MovieDetails{
+	details := &gen.MovieDetails{
+		Metadata: model.MetadataToProto(&m.Metadata),
+		Rating:   *m.Rating,
+	}
+	for _, r := range m.Related {
+		details.Related = append(details.Related, &gen.RelatedMovieDetails{
+			Relation: model.RelationToProto(r.Relation),
+			Metadata: model.MetadataToProto(&r.Metadata),
+			Rating:   r.Rating,
+		})
+	}
+	return &gen.GetMovieDetailsResponse{MovieDetails: details}, nil
+}
+
+// GetMovieLeaderboard returns the top rated or trending movies with their metadata.
+func (h *Handler) GetMovieLeaderboard(ctx context.Context, req *gen.GetMovieLeaderboardRequest) (*gen.GetMovieLeaderboardResponse, error) {
+	if req == nil || req.Leaderboard == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty leaderboard")
+	}
+	movies, err := h.ctrl.Leaderboard(ctx, ratingmodel.Leaderboard(req.Leaderboard), ratingmodel.LeaderboardQuery{
+		MinVotes: req.MinVotes,
+		Window:   req.Window.AsDuration(),
+		Limit:    int(req.Limit),
+	}, model.PreferredLocales(req.Locale, req.FallbackLocales...))
+	if err != nil && (errors.Is(err, movie.ErrInvalidLeaderboard) || errors.Is(err, movie.ErrInvalidLocale)) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &gen.GetMovieLeaderboardResponse{}
+	for _, m := range movies {
+		res.Movies = append(res.Movies, &gen.LeaderboardMovie{
+			Rank:     m.Rank,
 			Metadata: model.MetadataToProto(&m.Metadata),
-			Rating:   *m.Rating,
-		},
-	}, nil
+			Rating:   m.Rating,
+			Count:    m.Count,
+		})
+	}
+	return res, nil
 }
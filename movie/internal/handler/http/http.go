@@ -5,8 +5,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 
+	"movieexample.com/metadata/pkg/model"
 	"movieexample.com/movie/internal/controller/movie"
+	moviemodel "movieexample.com/movie/pkg/model"
 )
 
 // Handler is an HTTP handler that wraps a movie.Controller to handle HTTP requests.
@@ -25,20 +28,68 @@ func New(controller *movie.Controller) *Handler {
 // If the movie is not found, it returns a 404 Not Found status.
 // If there is an error retrieving the movie details, it returns a 500 Internal Server Error status.
 // The movie details are encoded and written to the response writer.
+// The metadata is translated into the locale parameter, or else into the languages of the
+// Accept-Language header in order of preference; an invalid locale parameter returns a 400 Bad
+// Request status.
+// Instead of the id parameter, the movie may be named by a slug parameter; a former slug is
+// answered with a 301 Moved Permanently redirect to the current one. A related=true parameter
+// adds the related movies with their ratings.
 func (h *Handler) GetMoviedetails(w http.ResponseWriter, r *http.Request) {
 	id := r.FormValue("id")
-	MovieDetails, err := h.controller.Get(r.Context(), id)
+	slug := r.FormValue("slug")
+	if id != "" && slug != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var related bool
+	if v := r.FormValue("related"); v != "" {
+		var err error
+		if related, err = strconv.ParseBool(v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	locales := requestLocales(r)
+	var MovieDetails *moviemodel.MovieDetails
+	var err error
+	if slug != "" {
+		MovieDetails, err = h.controller.GetBySlug(r.Context(), slug, locales, related)
+	} else {
+		MovieDetails, err = h.controller.Get(r.Context(), id, locales, related)
+	}
 	if err != nil && errors.Is(err, movie.ErrNotFound) {
 		w.WriteHeader(http.StatusNotFound)
 		return
+	} else if err != nil && errors.Is(err, movie.ErrInvalidLocale) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	} else if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	if slug != "" && MovieDetails.Metadata.Slug != slug {
+		u := *r.URL
+		q := u.Query()
+		q.Set("slug", MovieDetails.Metadata.Slug)
+		u.RawQuery = q.Encode()
+		http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+		return
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
 	if err := json.NewEncoder(w).Encode(MovieDetails); err != nil {
 		log.Printf("Response Encode err: %v\n", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
+
+// requestLocales returns the locales a request prefers, most preferred first: the locale
+// parameter if set, or else the languages of the Accept-Language header.
+func requestLocales(r *http.Request) []string {
+	if locale := r.FormValue("locale"); locale != "" {
+		return []string{locale}
+	}
+	return model.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+}
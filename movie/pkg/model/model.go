@@ -5,4 +5,23 @@ import "movieexample.com/metadata/pkg/model"
 type MovieDetails struct {
 	Rating   *float64       `json:"rating,omitempty"`
 	Metadata model.Metadata `json:"metadata,omitempty"`
+	// Related lists the live movies related to this one when they were asked for.
+	Related []RelatedMovieDetails `json:"related,omitempty"`
+}
+
+// RelatedMovieDetails is a movie related to another one with its aggregated rating, which is nil
+// when it has no ratings yet.
+type RelatedMovieDetails struct {
+	Relation model.Relation `json:"relation"`
+	Rating   *float64       `json:"rating,omitempty"`
+	Metadata model.Metadata `json:"metadata"`
+}
+
+// LeaderboardMovie is a movie ranked on a leaderboard with the ratings that placed it there.
+type LeaderboardMovie struct {
+	Rank int64 `json:"rank"`
+	// Rating is the mean rating of the movie in the window of the leaderboard.
+	Rating   float64        `json:"rating"`
+	Count    int64          `json:"count"`
+	Metadata model.Metadata `json:"metadata"`
 }
@@ -13,6 +13,8 @@ func (c ContextKey) String() string {
 
 const (
 	ContextKeyRequestID ContextKey = "request_id"
+	ContextKeyAdmin     ContextKey = "admin"
+	ContextKeyAuthor    ContextKey = "author"
 )
 
 func WithRequestID(ctx context.Context, requestID string) context.Context {
@@ -27,6 +29,31 @@ func RequestIDFromContext(ctx context.Context) string {
 	return requestID
 }
 
+// WithAdmin marks the caller of the request as an administrator.
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ContextKeyAdmin, true)
+}
+
+// IsAdmin reports whether the caller of the request was marked as an administrator.
+func IsAdmin(ctx context.Context) bool {
+	admin, ok := ctx.Value(ContextKeyAdmin).(bool)
+	return ok && admin
+}
+
+// WithAuthor records who is making the changes of the request, for audit purposes.
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, ContextKeyAuthor, author)
+}
+
+// AuthorFromContext returns the author recorded with WithAuthor, or an empty string.
+func AuthorFromContext(ctx context.Context) string {
+	author, ok := ctx.Value(ContextKeyAuthor).(string)
+	if !ok {
+		return ""
+	}
+	return author
+}
+
 // WaitForCleanup waits for the provided sync.WaitGroup to be empty, or for the
 // provided context to be canceled. This is useful for waiting for a set of
 // asynchronous operations to complete before proceeding.
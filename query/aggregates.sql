@@ -0,0 +1,99 @@
+-- name: LockRatingAggregate :exec
+-- LockRatingAggregate creates the aggregate of a record if needed and locks it until the end of
+-- the transaction, so that writes to the ratings of the record apply their deltas one at a time.
+INSERT INTO rating_aggregates (record_id, record_type)
+VALUES ($1, $2)
+ON CONFLICT (record_id, record_type) DO UPDATE
+SET ratings_count = rating_aggregates.ratings_count;
+
+-- name: AddToRatingAggregate :exec
+UPDATE rating_aggregates
+SET ratings_count = ratings_count + sqlc.arg(count_delta),
+    ratings_sum = ratings_sum + sqlc.arg(sum_delta)
+WHERE record_id = sqlc.arg(record_id)
+  AND record_type = sqlc.arg(record_type);
+
+-- name: AddToRatingHistogram :exec
+INSERT INTO rating_histograms (record_id, record_type, value, ratings)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (record_id, record_type, value) DO UPDATE
+SET ratings = rating_histograms.ratings + EXCLUDED.ratings;
+
+-- name: GetRatingHistogram :many
+SELECT value, ratings
+FROM rating_histograms
+WHERE record_id = $1
+  AND record_type = $2
+  AND ratings > 0
+ORDER BY value;
+
+-- name: GetRatingHistograms :many
+SELECT record_id, value, ratings
+FROM rating_histograms
+WHERE record_type = sqlc.arg(record_type)
+  AND record_id = ANY(sqlc.arg(record_ids)::text[])
+  AND ratings > 0
+ORDER BY record_id, value;
+
+-- name: GetRecordTypeHistogram :many
+SELECT value, sum(ratings)::bigint AS ratings
+FROM rating_histograms
+WHERE record_type = $1
+  AND ratings > 0
+GROUP BY value
+ORDER BY value;
+
+-- name: ListRatingAggregates :many
+SELECT record_id, record_type, ratings_count, ratings_sum
+FROM rating_aggregates
+ORDER BY record_type, record_id;
+
+-- name: ListRatingHistograms :many
+SELECT record_id, record_type, value, ratings
+FROM rating_histograms
+WHERE ratings <> 0
+ORDER BY record_type, record_id, value;
+
+-- name: ComputeRatingHistograms :many
+-- ComputeRatingHistograms counts the raw ratings of every record by value.
+SELECT record_id, record_type, value::int AS value, count(*) AS ratings
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type, value
+ORDER BY record_type, record_id, value;
+
+-- name: LockRatingTables :exec
+-- LockRatingTables blocks writes to ratings and their aggregates until the end of the
+-- transaction. The aggregates are locked first, like writers do, so that neither waits on the other.
+LOCK TABLE rating_aggregates, rating_histograms, ratings IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: ClearRatingAggregates :exec
+DELETE FROM rating_aggregates;
+
+-- name: RebuildRatingAggregates :exec
+INSERT INTO rating_aggregates (record_id, record_type, ratings_count, ratings_sum)
+SELECT record_id, record_type, count(*), sum(value)
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type;
+
+-- name: RebuildRatingHistograms :exec
+INSERT INTO rating_histograms (record_id, record_type, value, ratings)
+SELECT record_id, record_type, value, count(*)
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type, value;
+
+-- name: ListTopRatedAllTime :many
+-- ListTopRatedAllTime ranks records by the mean of their aggregate, then by their number of ratings.
+SELECT record_id,
+       record_type,
+       ratings_count,
+       (ratings_sum::float8 / ratings_count)::float8 AS mean,
+       rank() OVER (ORDER BY ratings_sum::float8 / ratings_count DESC, ratings_count DESC) AS rank
+FROM rating_aggregates
+WHERE ratings_count > 0
+  AND ratings_count >= sqlc.arg(min_votes)
+  AND (sqlc.narg(record_type)::text IS NULL OR record_type = sqlc.narg(record_type))
+ORDER BY rank, record_type, record_id
+LIMIT sqlc.arg(max_records);
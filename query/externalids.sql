@@ -0,0 +1,36 @@
+-- name: ListMovieExternalIDs :many
+SELECT provider, external_id
+FROM movie_external_ids
+WHERE movie_id = $1
+ORDER BY provider;
+
+-- name: ListExternalIDsForMovies :many
+SELECT movie_id, provider, external_id
+FROM movie_external_ids
+WHERE movie_id = ANY(@movie_ids::varchar[])
+ORDER BY movie_id, provider;
+
+-- name: InsertMovieExternalID :exec
+INSERT INTO movie_external_ids (movie_id, provider, external_id)
+VALUES ($1, $2, $3);
+
+-- name: DeleteMovieExternalIDs :exec
+DELETE FROM movie_external_ids
+WHERE movie_id = $1;
+
+-- name: DeleteExternalIDsForMovies :exec
+DELETE FROM movie_external_ids
+WHERE movie_id = ANY(@movie_ids::varchar[]);
+
+-- name: CopyMovieExternalIDs :copyfrom
+INSERT INTO movie_external_ids (movie_id, provider, external_id)
+VALUES ($1, $2, $3);
+
+-- name: GetMovieIDByExternalID :one
+-- Soft deleted movies keep their external IDs but are not found.
+SELECT m.id
+FROM movie_external_ids e
+JOIN movie m ON m.id = e.movie_id
+WHERE e.provider = $1
+  AND e.external_id = $2
+  AND m.deleted_at IS NULL;
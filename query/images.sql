@@ -0,0 +1,10 @@
+-- name: InsertImage :one
+INSERT INTO movie_images (id, movie_id, kind, content_type, width, height, size_bytes, storage_key, variants)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING created_at;
+
+-- name: ListImages :many
+SELECT id, movie_id, kind, content_type, width, height, size_bytes, storage_key, variants, created_at
+FROM movie_images
+WHERE movie_id = $1
+ORDER BY created_at, id;
@@ -1,9 +1,194 @@
 -- name: GetMovie :one
-SELECT title, description, director 
+SELECT title, description, director, runtime_minutes, release_date, original_language, country, version
 FROM movie 
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+
+-- name: UpsertMovie :one
+INSERT INTO movie (id, title, description, director, runtime_minutes, release_date, original_language, country) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (id) DO UPDATE SET
+  title = EXCLUDED.title,
+  description = EXCLUDED.description,
+  director = EXCLUDED.director,
+  runtime_minutes = EXCLUDED.runtime_minutes,
+  release_date = EXCLUDED.release_date,
+  original_language = EXCLUDED.original_language,
+  country = EXCLUDED.country,
+  deleted_at = NULL,
+  version = movie.version + 1
+RETURNING version;
+
+-- name: InsertMovieIfAbsent :one
+INSERT INTO movie (id, title, description, director, runtime_minutes, release_date, original_language, country)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (id) DO NOTHING
+RETURNING version;
+
+-- name: UpdateMovieIfVersion :one
+UPDATE movie SET
+  title = $2,
+  description = $3,
+  director = $4,
+  runtime_minutes = $5,
+  release_date = $6,
+  original_language = $7,
+  country = $8,
+  deleted_at = NULL,
+  version = version + 1
+WHERE id = $1
+  AND version = @expected_version
+RETURNING version;
+
+-- name: UpdateMovieFields :one
+-- Only the columns whose set_ flag is true are written; the others keep their stored value.
+-- An expected_version of 0 skips the version check.
+UPDATE movie SET
+  title = CASE WHEN @set_title::boolean THEN sqlc.narg('title')::varchar ELSE title END,
+  description = CASE WHEN @set_description::boolean THEN sqlc.narg('description')::varchar ELSE description END,
+  director = CASE WHEN @set_director::boolean THEN sqlc.narg('director')::varchar ELSE director END,
+  runtime_minutes = CASE WHEN @set_runtime_minutes::boolean THEN sqlc.narg('runtime_minutes')::int ELSE runtime_minutes END,
+  release_date = CASE WHEN @set_release_date::boolean THEN sqlc.narg('release_date')::date ELSE release_date END,
+  original_language = CASE WHEN @set_original_language::boolean THEN sqlc.narg('original_language')::varchar ELSE original_language END,
+  country = CASE WHEN @set_country::boolean THEN sqlc.narg('country')::varchar ELSE country END,
+  version = version + 1
+WHERE id = @id
+  AND deleted_at IS NULL
+  AND (@expected_version::bigint = 0 OR version = @expected_version)
+RETURNING version;
+
+-- name: UpsertGenre :one
+INSERT INTO genres (name)
+VALUES ($1)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id;
+
+-- name: ListMovieGenres :many
+SELECT g.name
+FROM genres g
+JOIN movie_genres mg ON mg.genre_id = g.id
+WHERE mg.movie_id = $1
+ORDER BY g.name;
+
+-- name: InsertMovieGenre :exec
+INSERT INTO movie_genres (movie_id, genre_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteMovieGenres :exec
+DELETE FROM movie_genres
+WHERE movie_id = $1;
+
+-- name: ListMovieCredits :many
+SELECT person_name, role, character_name, billing_order
+FROM movie_credits
+WHERE movie_id = $1
+ORDER BY billing_order, person_name;
+
+-- name: InsertMovieCredit :exec
+INSERT INTO movie_credits (movie_id, person_name, role, character_name, billing_order)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: DeleteMovieCredits :exec
+DELETE FROM movie_credits
+WHERE movie_id = $1;
+
+-- name: ListMovies :many
+SELECT m.id, m.title, m.description, m.director, m.runtime_minutes, m.release_date, m.original_language, m.country, m.version
+FROM movie m
+WHERE m.deleted_at IS NULL
+  AND (sqlc.narg('director')::text IS NULL OR lower(m.director) = lower(sqlc.narg('director')::text))
+  AND (sqlc.narg('title_prefix')::text IS NULL OR m.title ILIKE sqlc.narg('title_prefix')::text || '%')
+  AND (sqlc.narg('genre')::text IS NULL OR EXISTS (
+    SELECT 1
+    FROM movie_genres mg
+    JOIN genres g ON g.id = mg.genre_id
+    WHERE mg.movie_id = m.id AND g.name = sqlc.narg('genre')::text
+  ))
+  AND (sqlc.narg('release_year_from')::int IS NULL OR EXTRACT(YEAR FROM m.release_date) >= sqlc.narg('release_year_from')::int)
+  AND (sqlc.narg('release_year_to')::int IS NULL OR EXTRACT(YEAR FROM m.release_date) <= sqlc.narg('release_year_to')::int)
+ORDER BY
+  CASE WHEN @sort_order::text = 'title' THEN m.title END ASC NULLS LAST,
+  CASE WHEN @sort_order::text = 'title_desc' THEN m.title END DESC NULLS LAST,
+  CASE WHEN @sort_order::text = 'release_date' THEN m.release_date END ASC NULLS LAST,
+  CASE WHEN @sort_order::text = 'release_date_desc' THEN m.release_date END DESC NULLS LAST,
+  m.id
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: ListMoviesAfter :many
+-- Keyset pagination over the primary key, so every page is an index range scan
+-- no matter how deep into the catalog it is.
+SELECT id, title, description, director, runtime_minutes, release_date, original_language, country, version
+FROM movie
+WHERE deleted_at IS NULL
+  AND id > @after_id
+ORDER BY id
+LIMIT @page_limit;
+
+-- name: GetMovies :many
+SELECT id, title, description, director, runtime_minutes, release_date, original_language, country, version
+FROM movie
+WHERE id = ANY(@movie_ids::varchar[])
+  AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: ListGenresForMovies :many
+SELECT mg.movie_id, g.name
+FROM movie_genres mg
+JOIN genres g ON g.id = mg.genre_id
+WHERE mg.movie_id = ANY(@movie_ids::varchar[])
+ORDER BY mg.movie_id, g.name;
+
+-- name: ListCreditsForMovies :many
+SELECT movie_id, person_name, role, character_name, billing_order
+FROM movie_credits
+WHERE movie_id = ANY(@movie_ids::varchar[])
+ORDER BY movie_id, billing_order, person_name;
+
+-- name: SearchMovies :many
+SELECT m.id, m.title, m.description, m.director, m.runtime_minutes, m.release_date, m.original_language, m.country, m.version,
+  ts_rank(m.search_vector, q.query)::float8 AS score
+FROM movie m, websearch_to_tsquery('english', @query::text) AS q(query)
+WHERE m.search_vector @@ q.query
+  AND m.deleted_at IS NULL
+ORDER BY score DESC, m.id
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: SoftDeleteMovie :execrows
+UPDATE movie
+SET deleted_at = now()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: UndeleteMovie :execrows
+UPDATE movie
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL;
+
+-- name: DeleteMovie :execrows
+DELETE FROM movie
 WHERE id = $1;
 
+-- name: UpsertGenres :many
+INSERT INTO genres (name)
+SELECT unnest(@names::varchar[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, name;
+
+-- name: DeleteGenresForMovies :exec
+DELETE FROM movie_genres
+WHERE movie_id = ANY(@movie_ids::varchar[]);
+
+-- name: DeleteCreditsForMovies :exec
+DELETE FROM movie_credits
+WHERE movie_id = ANY(@movie_ids::varchar[]);
+
+-- name: CopyMovieGenres :copyfrom
+INSERT INTO movie_genres (movie_id, genre_id)
+VALUES ($1, $2);
 
--- name: InsertMovie :exec
-INSERT INTO movie (id, title, description, director) 
-VALUES ($1, $2, $3, $4);
+-- name: CopyMovieCredits :copyfrom
+INSERT INTO movie_credits (movie_id, person_name, role, character_name, billing_order)
+VALUES ($1, $2, $3, $4, $5);
@@ -1,9 +1,92 @@
 -- name: GetRatings :many
-SELECT user_id, value
+SELECT user_id, value, created_at, updated_at
 FROM ratings
 WHERE record_id = $1
   AND record_type = $2;
 
--- name: InsertRating :execresult
+-- name: UpsertRating :one
 INSERT INTO ratings (record_id, record_type, user_id, value)
-VALUES ($1, $2, $3, $4);
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (record_id, record_type, user_id) DO UPDATE
+SET value = EXCLUDED.value,
+    updated_at = now()
+RETURNING (xmax = 0)::boolean AS created, created_at, updated_at;
+
+-- name: GetUserRating :one
+SELECT value, created_at, updated_at
+FROM ratings
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3;
+
+-- name: ListUserRatings :many
+-- ListUserRatings returns a page of the ratings of a user, most recently changed first, starting
+-- after the rating at the after_ cursor when it is set.
+SELECT record_id, record_type, value, created_at, updated_at
+FROM ratings
+WHERE user_id = sqlc.arg(user_id)
+  AND value IS NOT NULL
+  AND (sqlc.narg(record_type)::text IS NULL OR record_type = sqlc.narg(record_type))
+  AND (sqlc.narg(after_updated_at)::timestamptz IS NULL
+       OR (updated_at, record_type, record_id) < (sqlc.narg(after_updated_at)::timestamptz, sqlc.arg(after_record_type)::text, sqlc.arg(after_record_id)::text))
+ORDER BY updated_at DESC, record_type DESC, record_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: DeleteRating :execrows
+DELETE FROM ratings
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3;
+
+-- name: GetDecayedMean :one
+-- GetDecayedMean weighs every rating by 0.5 to the power of its age in half-lives.
+SELECT COALESCE(
+           sum(value * power(0.5, extract(EPOCH FROM sqlc.arg(now)::timestamptz - updated_at) / sqlc.arg(half_life_seconds)::float8))
+               / NULLIF(sum(power(0.5, extract(EPOCH FROM sqlc.arg(now)::timestamptz - updated_at) / sqlc.arg(half_life_seconds)::float8)), 0),
+           0)::float8 AS mean
+FROM ratings
+WHERE record_id = sqlc.arg(record_id)
+  AND record_type = sqlc.arg(record_type)
+  AND value IS NOT NULL;
+
+-- name: ListTopRatedSince :many
+-- ListTopRatedSince ranks records by the mean of the ratings given or changed since a time, then
+-- by their number.
+WITH totals AS (
+    SELECT record_id, record_type, count(*) AS ratings_count, avg(value)::float8 AS mean
+    FROM ratings
+    WHERE value IS NOT NULL
+      AND updated_at >= sqlc.arg(since)
+      AND (sqlc.narg(record_type)::text IS NULL OR record_type = sqlc.narg(record_type))
+    GROUP BY record_id, record_type
+    HAVING count(*) >= sqlc.arg(min_votes)::bigint
+)
+SELECT record_id,
+       record_type,
+       ratings_count,
+       mean,
+       rank() OVER (ORDER BY mean DESC, ratings_count DESC) AS rank
+FROM totals
+ORDER BY rank, record_type, record_id
+LIMIT sqlc.arg(max_records);
+
+-- name: ListTrending :many
+-- ListTrending ranks records by the number of ratings given or changed since a time, then by
+-- their mean.
+WITH totals AS (
+    SELECT record_id, record_type, count(*) AS ratings_count, avg(value)::float8 AS mean
+    FROM ratings
+    WHERE value IS NOT NULL
+      AND updated_at >= sqlc.arg(since)
+      AND (sqlc.narg(record_type)::text IS NULL OR record_type = sqlc.narg(record_type))
+    GROUP BY record_id, record_type
+    HAVING count(*) >= sqlc.arg(min_votes)::bigint
+)
+SELECT record_id,
+       record_type,
+       ratings_count,
+       mean,
+       rank() OVER (ORDER BY ratings_count DESC, mean DESC) AS rank
+FROM totals
+ORDER BY rank, record_type, record_id
+LIMIT sqlc.arg(max_records);
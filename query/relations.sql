@@ -0,0 +1,38 @@
+-- name: InsertMovieRelation :exec
+INSERT INTO movie_relations (movie_id, relation_type, related_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (movie_id, relation_type, related_id) DO NOTHING;
+
+-- name: DeleteMovieRelation :execrows
+DELETE FROM movie_relations
+WHERE movie_id = $1
+  AND relation_type = $2
+  AND related_id = $3;
+
+-- name: ListMovieRelations :many
+SELECT movie_id, relation_type, related_id
+FROM movie_relations
+WHERE movie_id = sqlc.arg(id)
+   OR related_id = sqlc.arg(id)
+ORDER BY relation_type, movie_id, related_id;
+
+-- name: LockRelationType :exec
+-- LockRelationType serializes the writers of relations of one type until the end of the
+-- transaction, so that two concurrent inserts cannot each close half of a cycle.
+SELECT pg_advisory_xact_lock(hashtext('movie_relations:' || sqlc.arg(relation_type)::text));
+
+-- name: RelationReaches :one
+-- RelationReaches reports whether to_id can be reached from from_id by following relations of
+-- one type forward. UNION drops the rows already seen, so the walk ends on cyclic data too.
+WITH RECURSIVE reachable(id) AS (
+    SELECT related_id
+    FROM movie_relations
+    WHERE movie_id = sqlc.arg(from_id)::varchar
+      AND relation_type = sqlc.arg(relation_type)::text
+    UNION
+    SELECT r.related_id
+    FROM movie_relations r
+    JOIN reachable ON r.movie_id = reachable.id
+    WHERE r.relation_type = sqlc.arg(relation_type)::text
+)
+SELECT EXISTS (SELECT 1 FROM reachable WHERE id = sqlc.arg(to_id)::varchar);
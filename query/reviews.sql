@@ -0,0 +1,103 @@
+-- name: PutReview :one
+-- PutReview sets the review on the rating of a user, and returns no rows if there is no such rating.
+UPDATE ratings
+SET review_title = $4,
+    review_text = $5,
+    review_spoiler = $6,
+    review_state = $7,
+    moderation_reason = $8,
+    reviewed_at = now()
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3
+RETURNING reviewed_at;
+
+-- name: UpsertReviewVote :execrows
+-- UpsertReviewVote records or changes the vote of a voter on a review, and affects no rows if the
+-- author has no approved review of the record.
+INSERT INTO review_votes (record_id, record_type, author_id, voter_id, helpful)
+SELECT r.record_id, r.record_type, r.user_id, sqlc.arg(voter_id)::text, sqlc.arg(helpful)::boolean
+FROM ratings r
+WHERE r.record_id = sqlc.arg(record_id)
+  AND r.record_type = sqlc.arg(record_type)
+  AND r.user_id = sqlc.arg(author_id)
+  AND r.review_state = 'approved'
+ON CONFLICT (record_id, record_type, author_id, voter_id) DO UPDATE
+SET helpful = EXCLUDED.helpful;
+
+-- name: ListReviewsNewest :many
+-- ListReviewsNewest returns a page of the reviews of a record in a moderation state, newest first,
+-- starting after the review at the after_ cursor when it is set. The votes are counted by a
+-- lateral subquery so that no column of ratings has to be grouped.
+SELECT r.user_id,
+       r.value,
+       r.review_title,
+       r.review_text,
+       r.review_spoiler,
+       r.review_state,
+       r.moderation_reason,
+       r.created_at,
+       r.updated_at,
+       r.reviewed_at,
+       v.helpful_votes,
+       v.unhelpful_votes
+FROM ratings r
+CROSS JOIN LATERAL (
+    SELECT count(*) FILTER (WHERE helpful) AS helpful_votes,
+           count(*) FILTER (WHERE NOT helpful) AS unhelpful_votes
+    FROM review_votes
+    WHERE record_id = r.record_id
+      AND record_type = r.record_type
+      AND author_id = r.user_id
+) v
+WHERE r.record_id = sqlc.arg(record_id)
+  AND r.record_type = sqlc.arg(record_type)
+  AND r.review_text IS NOT NULL
+  AND r.review_state = sqlc.arg(review_state)
+  AND (sqlc.narg(after_reviewed_at)::timestamptz IS NULL
+       OR (r.reviewed_at, r.user_id) < (sqlc.narg(after_reviewed_at)::timestamptz, sqlc.arg(after_user_id)::text))
+ORDER BY r.reviewed_at DESC, r.user_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListReviewsHelpful :many
+-- ListReviewsHelpful is ListReviewsNewest ordered by helpful votes, descending, then unhelpful
+-- votes, ascending, first. Negating the unhelpful votes lets one row comparison page over both.
+SELECT r.user_id,
+       r.value,
+       r.review_title,
+       r.review_text,
+       r.review_spoiler,
+       r.review_state,
+       r.moderation_reason,
+       r.created_at,
+       r.updated_at,
+       r.reviewed_at,
+       v.helpful_votes,
+       v.unhelpful_votes
+FROM ratings r
+CROSS JOIN LATERAL (
+    SELECT count(*) FILTER (WHERE helpful) AS helpful_votes,
+           count(*) FILTER (WHERE NOT helpful) AS unhelpful_votes
+    FROM review_votes
+    WHERE record_id = r.record_id
+      AND record_type = r.record_type
+      AND author_id = r.user_id
+) v
+WHERE r.record_id = sqlc.arg(record_id)
+  AND r.record_type = sqlc.arg(record_type)
+  AND r.review_text IS NOT NULL
+  AND r.review_state = sqlc.arg(review_state)
+  AND (sqlc.narg(after_reviewed_at)::timestamptz IS NULL
+       OR (v.helpful_votes, -v.unhelpful_votes, r.reviewed_at, r.user_id)
+          < (sqlc.arg(after_helpful_votes)::bigint, -sqlc.arg(after_unhelpful_votes)::bigint, sqlc.narg(after_reviewed_at)::timestamptz, sqlc.arg(after_user_id)::text))
+ORDER BY v.helpful_votes DESC, v.unhelpful_votes, r.reviewed_at DESC, r.user_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ModerateReview :execrows
+UPDATE ratings
+SET review_state = $4,
+    moderation_reason = $5
+WHERE record_id = $1
+  AND record_type = $2
+  AND user_id = $3
+  AND review_text IS NOT NULL;
@@ -0,0 +1,42 @@
+-- name: InsertRevision :one
+INSERT INTO movie_revisions (movie_id, version, author, changes, snapshot)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING created_at;
+
+-- name: GetLatestRevision :one
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetRevision :one
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+  AND version = $2;
+
+-- name: GetRevisionAsOf :one
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+  AND created_at <= @as_of
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: ListRevisions :many
+SELECT movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = $1
+ORDER BY version DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: ListLatestRevisions :many
+SELECT DISTINCT ON (movie_id) movie_id, version, author, created_at, changes, snapshot
+FROM movie_revisions
+WHERE movie_id = ANY(@movie_ids::varchar[])
+ORDER BY movie_id, version DESC;
+
+-- name: CopyRevisions :copyfrom
+INSERT INTO movie_revisions (movie_id, version, author, changes, snapshot)
+VALUES ($1, $2, $3, $4, $5);
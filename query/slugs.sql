@@ -0,0 +1,31 @@
+-- name: GetCurrentSlug :one
+SELECT slug
+FROM movie_slugs
+WHERE movie_id = $1
+  AND is_current;
+
+-- name: ListCurrentSlugsForMovies :many
+SELECT movie_id, slug
+FROM movie_slugs
+WHERE movie_id = ANY(@movie_ids::varchar[])
+  AND is_current;
+
+-- name: RetireCurrentSlug :exec
+UPDATE movie_slugs
+SET is_current = FALSE
+WHERE movie_id = $1
+  AND is_current
+  AND slug <> @keep::varchar;
+
+-- name: ClaimSlug :execrows
+-- A slug that belongs to another movie is left alone, so no row is affected.
+INSERT INTO movie_slugs (slug, movie_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE
+SET is_current = TRUE
+WHERE movie_slugs.movie_id = EXCLUDED.movie_id;
+
+-- name: ResolveSlug :one
+SELECT movie_id
+FROM movie_slugs
+WHERE slug = $1;
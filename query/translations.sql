@@ -0,0 +1,23 @@
+-- name: UpsertTranslation :exec
+INSERT INTO movie_translations (movie_id, locale, title, description)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (movie_id, locale) DO UPDATE
+SET title = EXCLUDED.title,
+    description = EXCLUDED.description;
+
+-- name: DeleteTranslation :execrows
+DELETE FROM movie_translations
+WHERE movie_id = $1
+  AND locale = $2;
+
+-- name: ListTranslations :many
+SELECT locale, title, description
+FROM movie_translations
+WHERE movie_id = $1
+ORDER BY locale;
+
+-- name: ListTranslationsForMovies :many
+SELECT movie_id, locale, title, description
+FROM movie_translations
+WHERE movie_id = ANY(@movie_ids::varchar[])
+ORDER BY movie_id, locale;
@@ -19,6 +19,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"movieexample.com/gen"
+	"movieexample.com/internal/grpcutil"
 	"movieexample.com/pkg/discovery"
 	"movieexample.com/pkg/discovery/consul"
 	memoryDiscovery "movieexample.com/pkg/discovery/memory"
@@ -26,8 +27,10 @@ import (
 	config "movieexample.com/rating/configs"
 	"movieexample.com/rating/internal/controller/rating"
 	grpcHandler "movieexample.com/rating/internal/handler/grpc"
+	"movieexample.com/rating/internal/moderation"
 	"movieexample.com/rating/internal/repository/memory"
 	"movieexample.com/rating/internal/repository/postgres"
+	"movieexample.com/rating/pkg/model"
 )
 
 const serviceName = "rating"
@@ -149,7 +152,37 @@ func main() {
 			defer postgres.CloseDB(repo)
 		}
 
-		controller := rating.NewController(repo, nil)
+		ranking := rating.DefaultRankingParams
+		if cfg.Ranking.PriorWeight > 0 {
+			ranking.PriorWeight = cfg.Ranking.PriorWeight
+		}
+		if cfg.Ranking.WilsonZ > 0 {
+			ranking.WilsonZ = cfg.Ranking.WilsonZ
+		}
+		if cfg.Ranking.HalfLife > 0 {
+			ranking.HalfLife = cfg.Ranking.HalfLife
+		}
+		if cfg.Ranking.PriorTTL != nil {
+			ranking.PriorTTL = *cfg.Ranking.PriorTTL
+		}
+		checks := moderation.DefaultChecks()
+		if len(cfg.Moderation.BlockedWords) > 0 {
+			checks = append(checks, moderation.WordList(cfg.Moderation.BlockedWords))
+		}
+		scales := make(map[model.RecordType]model.RatingScale, len(cfg.Scales))
+		for recordType, scale := range cfg.Scales {
+			scales[model.RecordType(recordType)] = model.RatingScale{
+				Min:       model.RatingValue(scale.Min),
+				Max:       model.RatingValue(scale.Max),
+				HalfStars: scale.HalfStars,
+			}
+		}
+		controller := rating.NewController(repo, nil,
+			rating.WithRanking(ranking),
+			rating.WithModerator(moderation.New(checks...)),
+			rating.WithRatingScales(scales),
+			rating.WithLogger(logger),
+		)
 		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
 		if err != nil {
 			logger.Fatal("Failed to listen", zap.Error(err))
@@ -159,6 +192,9 @@ func main() {
 				otelgrpc.WithPropagators(propagation.TraceContext{}),
 				otelgrpc.WithTracerProvider(tp),
 			)),
+			grpc.ChainUnaryInterceptor(
+				grpcutil.AdminUnaryInterceptor(cfg.Admin.Token),
+			),
 		)
 
 		reflection.Register(grpcServer)
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Config struct {
 	API        *APIConfig        `yaml:"http"`
 	Jaeger     *JaegerConfig     `yaml:"jaeger"`
@@ -8,6 +10,11 @@ type Config struct {
 	GRPC       *GRPCConfig       `yaml:"grpc"`
 	Host       string            `yaml:"host"`
 	Postgres   *PostgresConfig   `yaml:"mysql"`
+	Ranking    *RankingConfig    `yaml:"ranking"`
+	Admin      *AdminConfig      `yaml:"admin"`
+	Moderation *ModerationConfig `yaml:"moderation"`
+	// Scales maps record types to the scale of their ratings.
+	Scales map[string]ScaleConfig `yaml:"scales"`
 }
 
 type APIConfig struct {
@@ -39,3 +46,30 @@ type PostgresConfig struct {
 	Database string `yaml:"database"`
 	SslMode  string `yaml:"sslmode"`
 }
+
+// RankingConfig holds the parameters of the ranking formulas. Zero values keep the defaults,
+// except for PriorTTL, which keeps the default when nil and reads the prior on every score when zero.
+type RankingConfig struct {
+	PriorWeight float64        `yaml:"priorWeight"`
+	WilsonZ     float64        `yaml:"wilsonZ"`
+	HalfLife    time.Duration  `yaml:"halfLife"`
+	PriorTTL    *time.Duration `yaml:"priorTTL"`
+}
+
+type AdminConfig struct {
+	Token string `yaml:"token"`
+}
+
+// ModerationConfig configures the checks reviews go through. BlockedWords rejects the reviews
+// containing any of them, on top of the default checks.
+type ModerationConfig struct {
+	BlockedWords []string `yaml:"blockedWords"`
+}
+
+// ScaleConfig is the scale of the ratings of a record type, from Min to Max, counting half stars
+// if HalfStars is set.
+type ScaleConfig struct {
+	Min       int  `yaml:"min"`
+	Max       int  `yaml:"max"`
+	HalfStars bool `yaml:"halfStars"`
+}
@@ -35,6 +35,15 @@ func SetUpConfig() (*Config, error) {
 	postgresPassword := viperConfig.GetString("POSTGRES_PASSWORD")
 	postgresDatabase := viperConfig.GetString("POSTGRES_DATABASE")
 	postgresSslMode := viperConfig.GetString("POSTGRES_SSL_MODE")
+	rankingPriorWeight := viperConfig.GetFloat64("RANKING_PRIOR_WEIGHT")
+	rankingWilsonZ := viperConfig.GetFloat64("RANKING_WILSON_Z")
+	rankingHalfLife := viperConfig.GetDuration("RANKING_HALF_LIFE")
+	adminToken := viperConfig.GetString("ADMIN_TOKEN")
+	blockedWords := viperConfig.GetString("REVIEW_BLOCKED_WORDS")
+	scales, err := parseScales(viperConfig.GetString("RATING_SCALES"))
+	if err != nil {
+		return nil, err
+	}
 
 	cfg.API = &APIConfig{
 		Host: host,
@@ -59,6 +68,45 @@ func SetUpConfig() (*Config, error) {
 		Database: postgresDatabase,
 		SslMode:  postgresSslMode,
 	}
+	cfg.Ranking = &RankingConfig{
+		PriorWeight: rankingPriorWeight,
+		WilsonZ:     rankingWilsonZ,
+		HalfLife:    rankingHalfLife,
+	}
+	if viperConfig.IsSet("RANKING_PRIOR_TTL") {
+		rankingPriorTTL := viperConfig.GetDuration("RANKING_PRIOR_TTL")
+		cfg.Ranking.PriorTTL = &rankingPriorTTL
+	}
+	cfg.Admin = &AdminConfig{
+		Token: adminToken,
+	}
+	cfg.Moderation = &ModerationConfig{}
+	if blockedWords != "" {
+		cfg.Moderation.BlockedWords = strings.Split(blockedWords, ",")
+	}
+	cfg.Scales = scales
 
 	return cfg, nil
 }
+
+// parseScales parses rating scales written as comma separated type:min-max entries, with a
+// :half suffix for scales counting half stars, such as "movie:1-5,episode:1-10:half".
+func parseScales(s string) (map[string]ScaleConfig, error) {
+	scales := map[string]ScaleConfig{}
+	if s == "" {
+		return scales, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || len(parts) == 3 && parts[2] != "half" {
+			return nil, fmt.Errorf("invalid rating scale %q, want type:min-max or type:min-max:half", entry)
+		}
+		var scale ScaleConfig
+		if _, err := fmt.Sscanf(parts[1], "%d-%d", &scale.Min, &scale.Max); err != nil || scale.Min > scale.Max {
+			return nil, fmt.Errorf("invalid rating scale %q, want min-max with min at most max", entry)
+		}
+		scale.HalfStars = len(parts) == 3
+		scales[parts[0]] = scale
+	}
+	return scales, nil
+}
@@ -0,0 +1,54 @@
+package rating
+
+import (
+	"math"
+	"slices"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// aggregate computes the summary statistics of the ratings counted by histogram.
+func aggregate(histogram map[model.RatingValue]int64) *model.RatingAggregate {
+	agg := &model.RatingAggregate{Histogram: map[model.RatingValue]int64{}}
+	values := make([]model.RatingValue, 0, len(histogram))
+	var sum float64
+	for v, n := range histogram {
+		if n <= 0 {
+			continue
+		}
+		values = append(values, v)
+		agg.Histogram[v] = n
+		agg.Count += n
+		sum += float64(v) * float64(n)
+	}
+	if agg.Count == 0 {
+		return agg
+	}
+	slices.Sort(values)
+
+	agg.Mean = sum / float64(agg.Count)
+	var squares float64
+	for _, v := range values {
+		d := float64(v) - agg.Mean
+		squares += d * d * float64(histogram[v])
+	}
+	agg.StdDev = math.Sqrt(squares / float64(agg.Count))
+
+	// the median is the mean of the ratings at the middle positions, which are the same one
+	// for an odd count
+	lo, hi := (agg.Count-1)/2, agg.Count/2
+	agg.Median = (float64(nth(values, histogram, lo)) + float64(nth(values, histogram, hi))) / 2
+	return agg
+}
+
+// nth returns the rating at the zero-based position i when the ratings counted by histogram are
+// sorted, given their distinct values in ascending order.
+func nth(values []model.RatingValue, histogram map[model.RatingValue]int64, i int64) model.RatingValue {
+	for _, v := range values {
+		if i < histogram[v] {
+			return v
+		}
+		i -= histogram[v]
+	}
+	return values[len(values)-1]
+}
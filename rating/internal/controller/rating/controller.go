@@ -3,7 +3,11 @@ package rating
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"go.uber.org/zap"
+	"movieexample.com/rating/internal/moderation"
 	"movieexample.com/rating/internal/repository"
 	"movieexample.com/rating/pkg/model"
 )
@@ -13,12 +17,59 @@ var (
 	ErrNotFound = errors.New("rating not found for the given record")
 )
 
+// ErrBatchTooLarge is returned when more than MaxBatchSize records are requested at once.
+var ErrBatchTooLarge = errors.New("too many records in batch")
+
+// MaxBatchSize caps the number of records whose aggregates are read in one batch.
+const MaxBatchSize = 1000
+
+// ErrUnknownEventType is logged by StartIngestion for events of a type it cannot apply.
+var ErrUnknownEventType = errors.New("unknown rating event type")
+
 // ratingRepository is an interface that defines the methods for interacting with a rating storage system.
 // The Get method retrieves a list of ratings for the given record ID and record type.
-// The Put method stores a new rating for the given record ID and record type.
+// The GetUserRating method retrieves the rating a user gave the record, or repository.ErrNotFound.
+// The Put method stores the rating of a user for the given record ID and record type, replacing
+// the previous rating of that user, and reports whether it was created rather than replaced.
+// The DeleteRating method removes the rating a user gave the record, or returns repository.ErrNotFound.
+// The GetHistogram method counts the ratings of the record by value, without loading every rating,
+// GetHistograms does the same for several records of a type at once, leaving out those that have not been rated,
+// and GetRecordTypeHistogram does the same for all records of a type. Repositories may keep these
+// counts up to date as ratings are written rather than count them on every read.
+// The GetDecayedMean method returns the mean rating of the record with every rating weighed by
+// 0.5 to the power of its age at now in half-lives, or 0 if there are no ratings.
+// The ListTopRated and ListTrending methods rank the records that pass the filters of the query,
+// by mean then count and by count then mean, with ties sharing a rank. The window of the query ends at now.
+// The ListUserRatings method returns up to limit ratings of the user after the cursor, or from the
+// first one for a nil cursor, ordered as model.UserRatingsCursor describes, with their record ID
+// and type set.
+// The PutReview method sets the review on the rating of the user, in the moderation state of the
+// review, and its ReviewedAt, or returns repository.ErrNotFound if there is no such rating; votes
+// on a previous review are kept.
+// The ListReviews method returns up to limit ratings of the record that have a review in the given
+// state after the cursor, or from the first if it is nil, in the given order as described by
+// model.ReviewsCursor, with their review and its vote counts set.
+// The VoteReview method records or replaces the vote of the voter on the approved review of the
+// author, or returns repository.ErrNotFound if there is no such review. Deleting a rating deletes
+// its review and the votes on it.
+// The ModerateReview method sets the moderation state of the review of the user, or returns
+// repository.ErrNotFound if there is no such review.
 type Repository interface {
 	Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error)
-	Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error
+	GetUserRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) (*model.Rating, error)
+	Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) (bool, error)
+	DeleteRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) error
+	GetHistogram(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (map[model.RatingValue]int64, error)
+	GetHistograms(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]map[model.RatingValue]int64, error)
+	GetRecordTypeHistogram(ctx context.Context, recordType model.RecordType) (map[model.RatingValue]int64, error)
+	GetDecayedMean(ctx context.Context, recordID model.RecordID, recordType model.RecordType, halfLife time.Duration, now time.Time) (float64, error)
+	ListTopRated(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error)
+	ListTrending(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error)
+	ListUserRatings(ctx context.Context, userID model.UserID, recordType model.RecordType, after *model.UserRatingsCursor, limit int) ([]model.Rating, error)
+	PutReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, review *model.Review) error
+	ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, state model.ReviewState, sort model.ReviewSort, after *model.ReviewsCursor, limit int) ([]model.Rating, error)
+	VoteReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, authorID, voterID model.UserID, helpful bool) error
+	ModerateReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, state model.ReviewState, reason string) error
 }
 
 type rateIngester interface {
@@ -27,49 +78,159 @@ type rateIngester interface {
 
 // Controller is a struct that holds a ratingRepository, which is used to interact with a rating storage system.
 type Controller struct {
-	repo     Repository
-	ingester rateIngester
+	repo      Repository
+	ingester  rateIngester
+	ranking   RankingParams
+	priors    priorCache
+	moderator *moderation.Moderator
+	scales    map[model.RecordType]model.RatingScale
+	logger    *zap.Logger
 }
 
 // NewController creates a new instance of the Controller struct with the provided ratingRepository.
-func NewController(repo Repository, ingester rateIngester) *Controller {
-	return &Controller{
-		repo:     repo,
-		ingester: ingester,
+func NewController(repo Repository, ingester rateIngester, opts ...Option) *Controller {
+	c := &Controller{
+		repo:      repo,
+		ingester:  ingester,
+		ranking:   DefaultRankingParams,
+		moderator: moderation.New(moderation.DefaultChecks()...),
+		logger:    zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// GetAggregateRating retrieves the aggregate rating for the given record ID and record type. It calculates the average of all the ratings for the given record.
+// GetAggregateRating retrieves the aggregate rating for the given record ID and record type. It calculates the count, mean,
+// median and standard deviation of the ratings for the given record from their histogram.
+// A non-empty ranking also scores the record with that formula, see model.RankingFormula, and
+// returns ErrInvalidRanking for an unknown one.
 // If no ratings are found for the given record, it returns ErrNotFound.
-func (c *Controller) GetAggregateRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
-	ratings, err := c.repo.Get(ctx, recordID, recordType)
+func (c *Controller) GetAggregateRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, ranking model.RankingFormula) (*model.RatingAggregate, error) {
+	histogram, err := c.repo.GetHistogram(ctx, recordID, recordType)
 	if err != nil && errors.Is(err, repository.ErrNotFound) {
-		return 0, ErrNotFound
+		return nil, ErrNotFound
 	} else if err != nil {
-		return 0, err
+		return nil, err
 	}
+	agg := aggregate(histogram)
+	if agg.Count == 0 {
+		return nil, ErrNotFound
+	}
+	if ranking != "" {
+		score, err := c.score(ctx, recordID, recordType, agg, ranking)
+		if err != nil {
+			return nil, err
+		}
+		agg.Score, agg.Ranking = &score, ranking
+	}
+	return agg, nil
+}
 
-	sum := float64(0)
-	for _, r := range ratings {
-		sum += float64(r.Value)
+// GetAggregateRatings retrieves the aggregate ratings of several records of a type with one
+// repository read, as GetAggregateRating would without a ranking. Records that have not been
+// rated are left out of the result. It returns ErrBatchTooLarge for more than MaxBatchSize records.
+func (c *Controller) GetAggregateRatings(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]*model.RatingAggregate, error) {
+	if len(recordIDs) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d records, at most %d", ErrBatchTooLarge, len(recordIDs), MaxBatchSize)
 	}
-	return sum / float64(len(ratings)), nil
+	if len(recordIDs) == 0 {
+		return map[model.RecordID]*model.RatingAggregate{}, nil
+	}
+	histograms, err := c.repo.GetHistograms(ctx, recordIDs, recordType)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[model.RecordID]*model.RatingAggregate, len(histograms))
+	for id, histogram := range histograms {
+		if agg := aggregate(histogram); agg.Count > 0 {
+			res[id] = agg
+		}
+	}
+	return res, nil
 }
 
-// PutRating stores a new rating for the given record ID and record type.
-func (c *Controller) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+// PutRating stores the rating of a user for the given record ID and record type. A user has at
+// most one rating per record, so rating again replaces the previous rating. It reports whether
+// the rating was created rather than replaced. A value outside the scale of the record type, see
+// GetRatingScale, returns ErrInvalidRatingValue.
+func (c *Controller) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) (bool, error) {
+	if err := c.checkRatingValue(recordType, rating.Value); err != nil {
+		return false, err
+	}
 	return c.repo.Put(ctx, recordID, recordType, rating)
 }
 
+// GetUserRating retrieves the rating a user gave the given record. If the user has not rated
+// the record, it returns ErrNotFound.
+func (c *Controller) GetUserRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) (*model.Rating, error) {
+	rating, err := c.repo.GetUserRating(ctx, recordID, recordType, userID)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return rating, nil
+}
+
+// DeleteRating removes the rating a user gave the given record. If the user has not rated the
+// record, it returns ErrNotFound.
+func (c *Controller) DeleteRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) error {
+	err := c.repo.DeleteRating(ctx, recordID, recordType, userID)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// WithLogger sets the logger the controller reports skipped rating events to. By default
+// nothing is logged.
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Controller) {
+		c.logger = logger
+	}
+}
+
+// StartIngestion applies the rating events of the ingester until its channel is closed. Put
+// events store the rating of the user and delete events remove it; deleting a rating that does
+// not exist is not an error, so redelivered events are harmless. Events that can never be
+// applied, of an unknown type or with a value outside the rating scale, are logged and skipped
+// so that one bad event does not stop ingestion; it stops at any other error.
 func (c *Controller) StartIngestion(ctx context.Context) error {
 	ch, err := c.ingester.Ingest(ctx)
 	if err != nil {
 		return err
 	}
 	for e := range ch {
-		if err := c.PutRating(ctx, e.RecordID, e.RecordType, &model.Rating{UserID: e.UserID, Value: e.Value}); err != nil {
+		err := c.applyEvent(ctx, e)
+		if err != nil && (errors.Is(err, ErrUnknownEventType) || errors.Is(err, ErrInvalidRatingValue)) {
+			c.logger.Warn("Skipping rating event",
+				zap.String("recordID", string(e.RecordID)),
+				zap.String("recordType", string(e.RecordType)),
+				zap.String("userID", string(e.UserID)),
+				zap.Error(err),
+			)
+		} else if err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// applyEvent applies a rating event according to its type.
+func (c *Controller) applyEvent(ctx context.Context, e model.RatingEvent) error {
+	switch e.EventType {
+	case model.RatingEventPut, "":
+		_, err := c.PutRating(ctx, e.RecordID, e.RecordType, &model.Rating{UserID: e.UserID, Value: e.Value})
+		return err
+	case model.RatingEventDelete:
+		err := c.DeleteRating(ctx, e.RecordID, e.RecordType, e.UserID)
+		if err != nil && errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownEventType, e.EventType)
+	}
+}
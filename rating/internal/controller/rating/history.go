@@ -0,0 +1,66 @@
+package rating
+
+import (
+	"context"
+	"errors"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// ErrInvalidPageSize is returned when a negative page size is requested.
+var ErrInvalidPageSize = errors.New("invalid page size")
+
+const (
+	// DefaultPageSize is used when a listing does not specify a page size.
+	DefaultPageSize = 20
+	// MaxPageSize caps the number of ratings returned in a single page.
+	MaxPageSize = 100
+)
+
+// ListUserRatings returns a page of the ratings of a user, most recently changed first, along with
+// the token of the next page, which is empty on the last page. An empty record type lists ratings
+// of all types. A zero page size returns DefaultPageSize ratings and larger ones are capped at
+// MaxPageSize. Page tokens hold the position of the last rating of their page, so ratings
+// changed while paging do not shift the next page, and are only valid for the user and record
+// type they were issued for.
+func (c *Controller) ListUserRatings(ctx context.Context, userID model.UserID, recordType model.RecordType, pageSize int, pageToken string) ([]model.Rating, string, error) {
+	pageSize, err := normalizePageSize(pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := userRatingsQuery{UserID: userID, RecordType: recordType}
+	var cursor model.UserRatingsCursor
+	var after *model.UserRatingsCursor
+	if ok, err := decodePageToken(pageToken, query, &cursor); err != nil {
+		return nil, "", err
+	} else if ok {
+		after = &cursor
+	}
+
+	res, err := c.repo.ListUserRatings(ctx, userID, recordType, after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(res) > pageSize {
+		res = res[:pageSize]
+		last := res[pageSize-1]
+		next = encodePageToken(model.UserRatingsCursor{UpdatedAt: last.UpdatedAt, RecordType: last.RecordType, RecordID: last.MovieID}, query)
+	}
+	return res, next, nil
+}
+
+// normalizePageSize returns the page size to list with: DefaultPageSize for zero and at most
+// MaxPageSize, or ErrInvalidPageSize if it is negative.
+func normalizePageSize(pageSize int) (int, error) {
+	switch {
+	case pageSize < 0:
+		return 0, ErrInvalidPageSize
+	case pageSize == 0:
+		return DefaultPageSize, nil
+	default:
+		return min(pageSize, MaxPageSize), nil
+	}
+}
@@ -0,0 +1,63 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// ErrInvalidLeaderboard is returned for leaderboard queries with a negative minimum of votes,
+// window or limit.
+var ErrInvalidLeaderboard = errors.New("invalid leaderboard query")
+
+const (
+	defaultLeaderboardLimit = 20
+	maxLeaderboardLimit     = 100
+	// defaultTrendingWindow is the time window of trending records when the query has none.
+	defaultTrendingWindow = 7 * 24 * time.Hour
+)
+
+// ListTopRated returns the records with the highest mean rating, counting only the ratings in the
+// time window of the query if it has one. Records with the same mean are ranked by their number of
+// ratings. A zero limit returns 20 records and larger limits are capped at 100, as page sizes are.
+func (c *Controller) ListTopRated(ctx context.Context, query model.LeaderboardQuery) ([]model.LeaderboardEntry, error) {
+	query, err := leaderboardQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.repo.ListTopRated(ctx, query, time.Now().UTC())
+}
+
+// ListTrending returns the records with the most ratings given or changed in the time window of
+// the query, or in the last week if it has none. Records with as many ratings are ranked by their
+// mean rating. The limit is as for ListTopRated.
+func (c *Controller) ListTrending(ctx context.Context, query model.LeaderboardQuery) ([]model.LeaderboardEntry, error) {
+	query, err := leaderboardQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if query.Window == 0 {
+		query.Window = defaultTrendingWindow
+	}
+	return c.repo.ListTrending(ctx, query, time.Now().UTC())
+}
+
+// leaderboardQuery validates a leaderboard query, fills in the default limit and caps it.
+func leaderboardQuery(query model.LeaderboardQuery) (model.LeaderboardQuery, error) {
+	switch {
+	case query.MinVotes < 0:
+		return query, fmt.Errorf("%w: negative minimum of votes %d", ErrInvalidLeaderboard, query.MinVotes)
+	case query.Window < 0:
+		return query, fmt.Errorf("%w: negative window %s", ErrInvalidLeaderboard, query.Window)
+	case query.Limit < 0:
+		return query, fmt.Errorf("%w: negative limit %d", ErrInvalidLeaderboard, query.Limit)
+	case query.Limit == 0:
+		query.Limit = defaultLeaderboardLimit
+	default:
+		query.Limit = min(query.Limit, maxLeaderboardLimit)
+	}
+	return query, nil
+}
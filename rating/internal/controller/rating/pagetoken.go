@@ -0,0 +1,75 @@
+package rating
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// ErrInvalidPageToken is returned when a page token cannot be decoded or was
+// issued for a different listing.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// pageToken is the decoded form of the opaque token handed out to clients.
+// After is the position of the last item of the previous page, in a form that
+// depends on the listing, so that writes between pages neither skip nor repeat items.
+// Query fingerprints the parameters of the request so a token cannot be replayed
+// against a different listing.
+type pageToken struct {
+	After json.RawMessage `json:"a"`
+	Query string          `json:"q"`
+}
+
+// userRatingsQuery holds the parameters of a ListUserRatings call that a page token is bound to.
+type userRatingsQuery struct {
+	UserID     model.UserID     `json:"u"`
+	RecordType model.RecordType `json:"t"`
+}
+
+// reviewsQuery holds the parameters of a ListReviews call that a page token is bound to.
+type reviewsQuery struct {
+	RecordID   model.RecordID    `json:"r"`
+	RecordType model.RecordType  `json:"t"`
+	State      model.ReviewState `json:"st"`
+	Sort       model.ReviewSort  `json:"s"`
+}
+
+func queryFingerprint(query any) string {
+	b, _ := json.Marshal(query)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodePageToken(after any, query any) string {
+	a, _ := json.Marshal(after)
+	b, _ := json.Marshal(pageToken{After: a, Query: queryFingerprint(query)})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken decodes the position stored in token into after and reports whether there
+// was one. An empty token starts at the first page.
+func decodePageToken(token string, query any, after any) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	var t pageToken
+	if err := json.Unmarshal(b, &t); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	if t.Query != queryFingerprint(query) {
+		return false, ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(t.After, after); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	return true, nil
+}
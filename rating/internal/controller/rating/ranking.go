@@ -0,0 +1,128 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// ErrInvalidRanking is returned when an unknown ranking formula is asked for.
+var ErrInvalidRanking = errors.New("invalid ranking formula")
+
+// RankingParams configures the ranking formulas.
+type RankingParams struct {
+	// PriorWeight is the number of ratings at the global mean that the Bayesian average adds to
+	// every record. The larger it is, the more ratings a record needs to move away from it.
+	PriorWeight float64
+	// WilsonZ is the z-score of the confidence level of the Wilson lower bound, such as 1.96 for 95%.
+	WilsonZ float64
+	// HalfLife is the age at which a rating counts half as much in the time-decayed average.
+	HalfLife time.Duration
+	// PriorTTL is how long the global mean of a record type is kept as the Bayesian prior before
+	// it is read again. Zero reads it on every score.
+	PriorTTL time.Duration
+}
+
+// DefaultRankingParams are the ranking parameters of a Controller created without WithRanking.
+var DefaultRankingParams = RankingParams{
+	PriorWeight: 10,
+	WilsonZ:     1.96,
+	HalfLife:    30 * 24 * time.Hour,
+	PriorTTL:    5 * time.Minute,
+}
+
+// Option configures optional parts of a Controller.
+type Option func(*Controller)
+
+// WithRanking sets the parameters of the ranking formulas.
+func WithRanking(params RankingParams) Option {
+	return func(c *Controller) {
+		c.ranking = params
+	}
+}
+
+// score computes the ranking score of a record with the given aggregate under the formula.
+func (c *Controller) score(ctx context.Context, recordID model.RecordID, recordType model.RecordType, agg *model.RatingAggregate, formula model.RankingFormula) (float64, error) {
+	switch formula {
+	case model.RankingBayesian:
+		prior, err := c.prior(ctx, recordType)
+		if err != nil {
+			return 0, err
+		}
+		return bayesianAverage(agg.Mean, agg.Count, prior, c.ranking.PriorWeight), nil
+	case model.RankingWilson:
+		scale := c.GetRatingScale(recordType)
+		return wilsonLowerBound(agg.Mean, agg.Count, scale.Min, scale.Max, c.ranking.WilsonZ), nil
+	case model.RankingTimeDecay:
+		return c.repo.GetDecayedMean(ctx, recordID, recordType, c.ranking.HalfLife, time.Now().UTC())
+	default:
+		return 0, fmt.Errorf("%w %q, want bayesian, wilson or time_decay", ErrInvalidRanking, formula)
+	}
+}
+
+// priorCache holds the global mean of each record type, read at most once per PriorTTL.
+type priorCache struct {
+	sync.Mutex
+	means map[model.RecordType]cachedPrior
+}
+
+type cachedPrior struct {
+	mean    float64
+	expires time.Time
+}
+
+// prior returns the global mean of the ratings of the record type, the prior of the Bayesian
+// average. Reading it counts every rating of the type, so it is cached for PriorTTL; a score may
+// use a prior that is up to that old.
+func (c *Controller) prior(ctx context.Context, recordType model.RecordType) (float64, error) {
+	now := time.Now()
+	c.priors.Lock()
+	cached, ok := c.priors.means[recordType]
+	c.priors.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.mean, nil
+	}
+
+	global, err := c.repo.GetRecordTypeHistogram(ctx, recordType)
+	if err != nil {
+		return 0, err
+	}
+	mean := aggregate(global).Mean
+	if c.ranking.PriorTTL > 0 {
+		c.priors.Lock()
+		if c.priors.means == nil {
+			c.priors.means = map[model.RecordType]cachedPrior{}
+		}
+		c.priors.means[recordType] = cachedPrior{mean: mean, expires: now.Add(c.ranking.PriorTTL)}
+		c.priors.Unlock()
+	}
+	return mean, nil
+}
+
+// bayesianAverage shrinks the mean of n ratings towards prior as if there were weight more
+// ratings at prior.
+func bayesianAverage(mean float64, n int64, prior, weight float64) float64 {
+	if weight+float64(n) == 0 {
+		return prior
+	}
+	return (weight*prior + mean*float64(n)) / (weight + float64(n))
+}
+
+// wilsonLowerBound returns the lower bound of the Wilson score interval of the share of the
+// scale from lo to hi that n ratings with the given mean reach, mapped back onto the scale.
+func wilsonLowerBound(mean float64, n int64, lo, hi model.RatingValue, z float64) float64 {
+	if n == 0 || hi <= lo {
+		return float64(lo)
+	}
+	p := (mean - float64(lo)) / float64(hi-lo)
+	p = math.Min(math.Max(p, 0), 1)
+	total := float64(n)
+	z2 := z * z
+	bound := (p + z2/(2*total) - z*math.Sqrt((p*(1-p)+z2/(4*total))/total)) / (1 + z2/total)
+	return float64(lo) + bound*float64(hi-lo)
+}
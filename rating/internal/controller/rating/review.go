@@ -0,0 +1,153 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"movieexample.com/rating/internal/moderation"
+	"movieexample.com/rating/internal/repository"
+	"movieexample.com/rating/pkg/model"
+)
+
+var (
+	// ErrInvalidReview is returned for reviews without text or with a title or text too long.
+	ErrInvalidReview = errors.New("invalid review")
+	// ErrInvalidReviewSort is returned when listing reviews in an unknown order.
+	ErrInvalidReviewSort = errors.New("invalid review sort")
+	// ErrInvalidReviewState is returned for unknown moderation states.
+	ErrInvalidReviewState = errors.New("invalid review state")
+	// ErrReviewNotFound is returned when voting on or moderating a review that does not exist.
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrOwnReview is returned when a user votes on their own review.
+	ErrOwnReview = errors.New("cannot vote on own review")
+)
+
+const (
+	// MaxReviewTitleLength is the number of characters a review title has at most.
+	MaxReviewTitleLength = 200
+	// MaxReviewTextLength is the number of characters a review text has at most.
+	MaxReviewTextLength = 10000
+)
+
+// WithModerator sets the moderator reviews go through as they are written, in place of one running
+// moderation.DefaultChecks.
+func WithModerator(m *moderation.Moderator) Option {
+	return func(c *Controller) {
+		c.moderator = m
+	}
+}
+
+// PutReview writes the review of a user for the given record, replacing their previous review
+// and keeping the votes on it. The review is attached to the rating of the user, so if they have
+// not rated the record, it returns ErrNotFound. The title and text are trimmed of surrounding
+// spaces, and a review without text or with a title or text too long returns ErrInvalidReview.
+// The review is then moderated, which sets its state and the reasons for it: only approved
+// reviews are listed by default, so a rewritten review is hidden again until it passes.
+func (c *Controller) PutReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, review *model.Review) error {
+	review.Title, review.Text = strings.TrimSpace(review.Title), strings.TrimSpace(review.Text)
+	switch {
+	case review.Text == "":
+		return fmt.Errorf("%w: empty text", ErrInvalidReview)
+	case utf8.RuneCountInString(review.Title) > MaxReviewTitleLength:
+		return fmt.Errorf("%w: title longer than %d characters", ErrInvalidReview, MaxReviewTitleLength)
+	case utf8.RuneCountInString(review.Text) > MaxReviewTextLength:
+		return fmt.Errorf("%w: text longer than %d characters", ErrInvalidReview, MaxReviewTextLength)
+	}
+	review.State, review.ModerationReason = c.moderator.Moderate(ctx, review)
+	err := c.repo.PutReview(ctx, recordID, recordType, userID, review)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ListReviews returns a page of the ratings of a record that have a review in the given moderation
+// state, approved if empty, with their review and its votes, along with the token of the next page.
+// Reviews are listed newest first by default, or most helpful first with model.ReviewSortHelpful;
+// other orders return ErrInvalidReviewSort. Page sizes are as for ListUserRatings, and page tokens
+// are only valid for the record, state and order they were issued for.
+func (c *Controller) ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, state model.ReviewState, sort model.ReviewSort, pageSize int, pageToken string) ([]model.Rating, string, error) {
+	if state == "" {
+		state = model.ReviewApproved
+	} else if !validReviewState(state) {
+		return nil, "", fmt.Errorf("%w %q", ErrInvalidReviewState, state)
+	}
+	switch sort {
+	case "":
+		sort = model.ReviewSortNewest
+	case model.ReviewSortNewest, model.ReviewSortHelpful:
+	default:
+		return nil, "", fmt.Errorf("%w %q", ErrInvalidReviewSort, sort)
+	}
+	pageSize, err := normalizePageSize(pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := reviewsQuery{RecordID: recordID, RecordType: recordType, State: state, Sort: sort}
+	var cursor model.ReviewsCursor
+	var after *model.ReviewsCursor
+	if ok, err := decodePageToken(pageToken, query, &cursor); err != nil {
+		return nil, "", err
+	} else if ok {
+		after = &cursor
+	}
+
+	res, err := c.repo.ListReviews(ctx, recordID, recordType, state, sort, after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(res) > pageSize {
+		res = res[:pageSize]
+		last := res[pageSize-1]
+		next = encodePageToken(model.ReviewsCursor{
+			HelpfulVotes:   last.Review.HelpfulVotes,
+			UnhelpfulVotes: last.Review.UnhelpfulVotes,
+			ReviewedAt:     last.Review.ReviewedAt,
+			UserID:         last.UserID,
+		}, query)
+	}
+	return res, next, nil
+}
+
+// VoteReview records whether a user found the review the author wrote of the given record
+// helpful. A user has at most one vote per review, so voting again replaces the previous vote.
+// It returns ErrOwnReview if the user is the author, and ErrReviewNotFound if there is no approved review.
+func (c *Controller) VoteReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, authorID, voterID model.UserID, helpful bool) error {
+	if authorID == voterID {
+		return ErrOwnReview
+	}
+	err := c.repo.VoteReview(ctx, recordID, recordType, authorID, voterID, helpful)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrReviewNotFound
+	}
+	return err
+}
+
+// ModerateReview sets the moderation state of the review a user wrote of the given record, as a
+// moderator decides it, with the reason for it. It returns ErrInvalidReviewState for an unknown
+// state and ErrReviewNotFound if there is no review.
+func (c *Controller) ModerateReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, state model.ReviewState, reason string) error {
+	if !validReviewState(state) {
+		return fmt.Errorf("%w %q", ErrInvalidReviewState, state)
+	}
+	err := c.repo.ModerateReview(ctx, recordID, recordType, userID, state, strings.TrimSpace(reason))
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrReviewNotFound
+	}
+	return err
+}
+
+func validReviewState(state model.ReviewState) bool {
+	switch state {
+	case model.ReviewPending, model.ReviewApproved, model.ReviewRejected:
+		return true
+	default:
+		return false
+	}
+}
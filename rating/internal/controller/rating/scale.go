@@ -0,0 +1,37 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// ErrInvalidRatingValue is returned when a rating value is outside the scale of its record type.
+var ErrInvalidRatingValue = errors.New("invalid rating value")
+
+// DefaultRatingScale is the scale of the record types a Controller has no scale configured for.
+var DefaultRatingScale = model.RatingScale{Min: 1, Max: 5}
+
+// WithRatingScales sets the rating scales of record types. Other record types keep DefaultRatingScale.
+func WithRatingScales(scales map[model.RecordType]model.RatingScale) Option {
+	return func(c *Controller) {
+		c.scales = scales
+	}
+}
+
+// GetRatingScale returns the scale of the ratings of a record type.
+func (c *Controller) GetRatingScale(recordType model.RecordType) model.RatingScale {
+	if scale, ok := c.scales[recordType]; ok {
+		return scale
+	}
+	return DefaultRatingScale
+}
+
+// checkRatingValue returns ErrInvalidRatingValue if v is outside the scale of the record type.
+func (c *Controller) checkRatingValue(recordType model.RecordType, v model.RatingValue) error {
+	if scale := c.GetRatingScale(recordType); !scale.Contains(v) {
+		return fmt.Errorf("%w %d, want %d to %d", ErrInvalidRatingValue, v, scale.Min, scale.Max)
+	}
+	return nil
+}
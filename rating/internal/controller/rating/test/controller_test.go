@@ -2,19 +2,25 @@ package controller_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	gen "movieexample.com/gen/mock/rating/repository"
 	"movieexample.com/rating/internal/controller/rating"
+	"movieexample.com/rating/internal/moderation"
+	"movieexample.com/rating/internal/repository"
+	"movieexample.com/rating/internal/repository/memory"
 	"movieexample.com/rating/pkg/model"
 )
 
 func TestControllerPut(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	repoMock := gen.NewMockratingRepository(ctrl)
+	repoMock := gen.NewMockRepository(ctrl)
 	c := rating.NewController(repoMock, nil)
 
 	ctx := context.Background()
@@ -24,32 +30,439 @@ func TestControllerPut(t *testing.T) {
 		Value:  5,
 	}
 	recordType := model.RecordTypeMovie
-	repoMock.EXPECT().Put(ctx, model.RecordID(id), recordType, &rating).Return(nil)
-	err := c.PutRating(ctx, model.RecordID(id), recordType, &rating)
+	repoMock.EXPECT().Put(ctx, model.RecordID(id), recordType, &rating).Return(true, nil)
+	created, err := c.PutRating(ctx, model.RecordID(id), recordType, &rating)
 	assert.NoError(t, err)
+	assert.True(t, created)
+}
+
+func TestControllerRatingScales(t *testing.T) {
+	repo := memory.New()
+	episodes := model.RatingScale{Min: 1, Max: 10, HalfStars: true}
+	c := rating.NewController(repo, nil, rating.WithRatingScales(map[model.RecordType]model.RatingScale{
+		"episode": episodes,
+	}))
+	ctx := context.Background()
+
+	assert.Equal(t, episodes, c.GetRatingScale("episode"))
+	assert.Equal(t, rating.DefaultRatingScale, c.GetRatingScale(model.RecordTypeMovie))
+	for _, tt := range []struct {
+		recordType model.RecordType
+		value      model.RatingValue
+		wantErr    bool
+	}{
+		{"episode", 10, false},
+		{"episode", 1, false},
+		{"episode", 0, true},
+		{"episode", 11, true},
+		{model.RecordTypeMovie, 5, false},
+		{model.RecordTypeMovie, 6, true},
+		{model.RecordTypeMovie, -1, true},
+	} {
+		_, err := c.PutRating(ctx, "1", tt.recordType, &model.Rating{UserID: "user0", Value: tt.value})
+		if tt.wantErr {
+			assert.ErrorIs(t, err, rating.ErrInvalidRatingValue, "%s rated %d", tt.recordType, tt.value)
+		} else {
+			assert.NoError(t, err, "%s rated %d", tt.recordType, tt.value)
+		}
+	}
+	r, err := repo.GetUserRating(ctx, "1", model.RecordTypeMovie, "user0")
+	assert.NoError(t, err)
+	assert.Equal(t, model.RatingValue(5), r.Value)
+}
+
+func TestControllerGetUserRating(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockRepository(ctrl)
+	c := rating.NewController(repoMock, nil)
+	ctx := context.Background()
+	recordType := model.RecordTypeMovie
+
+	repoMock.EXPECT().GetUserRating(ctx, model.RecordID("id"), recordType, model.UserID("user0")).Return(&model.Rating{UserID: "user0", Value: 4}, nil)
+	res, err := c.GetUserRating(ctx, model.RecordID("id"), recordType, model.UserID("user0"))
+	assert.NoError(t, err)
+	assert.Equal(t, model.RatingValue(4), res.Value)
+
+	repoMock.EXPECT().GetUserRating(ctx, model.RecordID("id"), recordType, model.UserID("user1")).Return(nil, repository.ErrNotFound)
+	_, err = c.GetUserRating(ctx, model.RecordID("id"), recordType, model.UserID("user1"))
+	assert.ErrorIs(t, err, rating.ErrNotFound)
 }
 
 func TestControllerAgg(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	repoMock := gen.NewMockratingRepository(ctrl)
+	repoMock := gen.NewMockRepository(ctrl)
 	c := rating.NewController(repoMock, nil)
 	ctx := context.Background()
 	id := "id"
-	ratings := []model.Rating{
+	recordType := model.RecordTypeMovie
+
+	tests := []struct {
+		name      string
+		histogram map[model.RatingValue]int64
+		want      *model.RatingAggregate
+	}{
+		{
+			name:      "single value",
+			histogram: map[model.RatingValue]int64{5: 2},
+			want:      &model.RatingAggregate{Count: 2, Mean: 5, Median: 5, Histogram: map[model.RatingValue]int64{5: 2}},
+		},
 		{
-			UserID: model.UserID(id),
-			Value:  5,
+			name:      "odd count",
+			histogram: map[model.RatingValue]int64{1: 1, 4: 1, 5: 1},
+			want:      &model.RatingAggregate{Count: 3, Mean: 10.0 / 3, Median: 4, StdDev: 1.699673171197595, Histogram: map[model.RatingValue]int64{1: 1, 4: 1, 5: 1}},
 		},
 		{
-			UserID: model.UserID(id),
-			Value:  5,
+			name:      "even count",
+			histogram: map[model.RatingValue]int64{2: 2, 3: 1, 5: 1},
+			want:      &model.RatingAggregate{Count: 4, Mean: 3, Median: 2.5, StdDev: 1.224744871391589, Histogram: map[model.RatingValue]int64{2: 2, 3: 1, 5: 1}},
 		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock.EXPECT().GetHistogram(ctx, model.RecordID(id), recordType).Return(tt.histogram, nil)
+			res, err := c.GetAggregateRating(ctx, model.RecordID(id), recordType, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Count, res.Count)
+			assert.InDelta(t, tt.want.Mean, res.Mean, 1e-9)
+			assert.InDelta(t, tt.want.Median, res.Median, 1e-9)
+			assert.InDelta(t, tt.want.StdDev, res.StdDev, 1e-9)
+			assert.Equal(t, tt.want.Histogram, res.Histogram)
+			assert.Nil(t, res.Score)
+		})
+	}
+
+	repoMock.EXPECT().GetHistogram(ctx, model.RecordID(id), recordType).Return(map[model.RatingValue]int64{}, nil)
+	_, err := c.GetAggregateRating(ctx, model.RecordID(id), recordType, "")
+	assert.ErrorIs(t, err, rating.ErrNotFound)
+}
+
+func TestControllerAggBatch(t *testing.T) {
+	repo := memory.New()
+	c := rating.NewController(repo, nil)
+	ctx := context.Background()
+	for i, value := range []model.RatingValue{5, 3, 4} {
+		id := model.RecordID(fmt.Sprint(i % 2))
+		_, err := c.PutRating(ctx, id, model.RecordTypeMovie, &model.Rating{UserID: model.UserID(fmt.Sprint("user", i)), Value: value})
+		assert.NoError(t, err)
+	}
+
+	res, err := c.GetAggregateRatings(ctx, []model.RecordID{"0", "1", "2"}, model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, int64(2), res["0"].Count)
+	assert.InDelta(t, 4.5, res["0"].Mean, 1e-9)
+	assert.Equal(t, int64(1), res["1"].Count)
+	assert.InDelta(t, 3, res["1"].Mean, 1e-9)
+
+	_, err = c.GetAggregateRatings(ctx, make([]model.RecordID, rating.MaxBatchSize+1), model.RecordTypeMovie)
+	assert.ErrorIs(t, err, rating.ErrBatchTooLarge)
+}
 
+func TestControllerRanking(t *testing.T) {
+	repo := memory.New()
+	c := rating.NewController(repo, nil, rating.WithRanking(rating.RankingParams{
+		PriorWeight: 10,
+		WilsonZ:     1.96,
+		HalfLife:    time.Hour,
+	}), rating.WithRatingScales(map[model.RecordType]model.RatingScale{
+		model.RecordTypeMovie: {Min: 1, Max: 5},
+	}))
+	ctx := context.Background()
 	recordType := model.RecordTypeMovie
-	repoMock.EXPECT().Get(ctx, model.RecordID(id), recordType).Return(ratings, nil)
-	res, err := c.GetAggregateRating(ctx, model.RecordID(id), recordType)
+
+	// one perfect rating against fifty ratings averaging 4.8, in a catalog averaging about 3.6
+	_, err := repo.Put(ctx, "single", recordType, &model.Rating{UserID: "user0", Value: 5})
+	assert.NoError(t, err)
+	for i := range 100 {
+		userID := model.UserID(fmt.Sprintf("user%d", i))
+		_, err := repo.Put(ctx, "other", recordType, &model.Rating{UserID: userID, Value: 3})
+		assert.NoError(t, err)
+		if i >= 50 {
+			continue
+		}
+		v := model.RatingValue(5)
+		if i%5 == 0 {
+			v = 4
+		}
+		_, err = repo.Put(ctx, "many", recordType, &model.Rating{UserID: userID, Value: v})
+		assert.NoError(t, err)
+	}
+
+	for _, ranking := range []model.RankingFormula{model.RankingBayesian, model.RankingWilson} {
+		t.Run(string(ranking), func(t *testing.T) {
+			single, err := c.GetAggregateRating(ctx, "single", recordType, ranking)
+			assert.NoError(t, err)
+			many, err := c.GetAggregateRating(ctx, "many", recordType, ranking)
+			assert.NoError(t, err)
+			assert.Equal(t, ranking, single.Ranking)
+			assert.Greater(t, single.Mean, many.Mean)
+			assert.Less(t, *single.Score, *many.Score)
+			assert.Less(t, *many.Score, many.Mean)
+		})
+	}
+
+	t.Run(string(model.RankingTimeDecay), func(t *testing.T) {
+		res, err := c.GetAggregateRating(ctx, "many", recordType, model.RankingTimeDecay)
+		assert.NoError(t, err)
+		assert.InDelta(t, res.Mean, *res.Score, 1e-3)
+	})
+
+	_, err = c.GetAggregateRating(ctx, "single", recordType, "popularity")
+	assert.ErrorIs(t, err, rating.ErrInvalidRanking)
+}
+
+func TestControllerBayesianPriorCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockRepository(ctrl)
+	c := rating.NewController(repoMock, nil)
+	ctx := context.Background()
+
+	repoMock.EXPECT().GetHistogram(ctx, model.RecordID("1"), model.RecordTypeMovie).Return(map[model.RatingValue]int64{5: 2}, nil).Times(2)
+	repoMock.EXPECT().GetRecordTypeHistogram(ctx, model.RecordTypeMovie).Return(map[model.RatingValue]int64{3: 10}, nil).Times(1)
+	for range 2 {
+		res, err := c.GetAggregateRating(ctx, "1", model.RecordTypeMovie, model.RankingBayesian)
+		assert.NoError(t, err)
+		assert.InDelta(t, (10*3.0+2*5.0)/12, *res.Score, 1e-9)
+	}
+}
+
+func TestControllerLeaderboards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockRepository(ctrl)
+	c := rating.NewController(repoMock, nil)
+	ctx := context.Background()
+	entries := []model.LeaderboardEntry{{Rank: 1, RecordID: "1", RecordType: model.RecordTypeMovie, Count: 3, Mean: 4}}
+
+	repoMock.EXPECT().ListTopRated(ctx, model.LeaderboardQuery{MinVotes: 3, Limit: 20}, gomock.Any()).Return(entries, nil)
+	res, err := c.ListTopRated(ctx, model.LeaderboardQuery{MinVotes: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, entries, res)
+
+	repoMock.EXPECT().ListTrending(ctx, model.LeaderboardQuery{RecordType: model.RecordTypeMovie, Window: 7 * 24 * time.Hour, Limit: 100}, gomock.Any()).Return(entries, nil)
+	res, err = c.ListTrending(ctx, model.LeaderboardQuery{RecordType: model.RecordTypeMovie, Limit: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, entries, res)
+
+	repoMock.EXPECT().ListTopRated(ctx, model.LeaderboardQuery{Limit: 100}, gomock.Any()).Return(entries, nil)
+	_, err = c.ListTopRated(ctx, model.LeaderboardQuery{Limit: 1000})
+	assert.NoError(t, err, "limits are capped rather than rejected")
+
+	for _, query := range []model.LeaderboardQuery{{MinVotes: -1}, {Window: -time.Hour}, {Limit: -1}} {
+		_, err := c.ListTopRated(ctx, query)
+		assert.ErrorIs(t, err, rating.ErrInvalidLeaderboard)
+	}
+}
+
+func TestControllerListUserRatings(t *testing.T) {
+	repo := memory.New()
+	c := rating.NewController(repo, nil)
+	ctx := context.Background()
+	for _, id := range []model.RecordID{"1", "2", "3"} {
+		_, err := repo.Put(ctx, id, model.RecordTypeMovie, &model.Rating{UserID: "user0", Value: 4})
+		assert.NoError(t, err)
+	}
+
+	var seen []model.RecordID
+	ratings, next, err := c.ListUserRatings(ctx, "user0", model.RecordTypeMovie, 2, "")
+	assert.NoError(t, err)
+	assert.Len(t, ratings, 2)
+	assert.NotEmpty(t, next)
+	for _, r := range ratings {
+		seen = append(seen, r.MovieID)
+	}
+	ratings, last, err := c.ListUserRatings(ctx, "user0", model.RecordTypeMovie, 2, next)
+	assert.NoError(t, err)
+	assert.Len(t, ratings, 1)
+	assert.Empty(t, last)
+	seen = append(seen, ratings[0].MovieID)
+	assert.ElementsMatch(t, []model.RecordID{"1", "2", "3"}, seen)
+
+	_, _, err = c.ListUserRatings(ctx, "user1", model.RecordTypeMovie, 2, next)
+	assert.ErrorIs(t, err, rating.ErrInvalidPageToken)
+	_, _, err = c.ListUserRatings(ctx, "user0", model.RecordTypeMovie, -1, "")
+	assert.ErrorIs(t, err, rating.ErrInvalidPageSize)
+}
+
+func TestControllerListUserRatingsStableUnderWrites(t *testing.T) {
+	repo := memory.New()
+	c := rating.NewController(repo, nil)
+	ctx := context.Background()
+	for _, id := range []model.RecordID{"1", "2", "3", "4"} {
+		_, err := repo.Put(ctx, id, model.RecordTypeMovie, &model.Rating{UserID: "user0", Value: 4})
+		assert.NoError(t, err)
+		time.Sleep(time.Millisecond)
+	}
+
+	ratings, next, err := c.ListUserRatings(ctx, "user0", "", 2, "")
+	assert.NoError(t, err)
+	seen := []model.RecordID{ratings[0].MovieID, ratings[1].MovieID}
+	assert.Equal(t, []model.RecordID{"4", "3"}, seen)
+
+	// rating an older record again moves it to the first page; an offset would then repeat "3"
+	_, err = repo.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user0", Value: 5})
+	assert.NoError(t, err)
+	ratings, next, err = c.ListUserRatings(ctx, "user0", "", 2, next)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+	for _, r := range ratings {
+		seen = append(seen, r.MovieID)
+	}
+	assert.Equal(t, []model.RecordID{"4", "3", "2"}, seen)
+
+	_, _, err = c.ListUserRatings(ctx, "user0", "", -1, "")
+	assert.ErrorIs(t, err, rating.ErrInvalidPageSize)
+}
+
+func TestControllerReviews(t *testing.T) {
+	repo := memory.New()
+	c := rating.NewController(repo, nil)
+	ctx := context.Background()
+	const text = "Great pacing and a memorable score."
+	assert.ErrorIs(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user0", &model.Review{Text: text}), rating.ErrNotFound)
+	for _, userID := range []model.UserID{"user0", "user1", "user2"} {
+		_, err := repo.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: userID, Value: 4})
+		assert.NoError(t, err)
+	}
+
+	assert.ErrorIs(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user0", &model.Review{Text: "  "}), rating.ErrInvalidReview)
+	long := strings.Repeat("é", rating.MaxReviewTitleLength+1)
+	assert.ErrorIs(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user0", &model.Review{Title: long, Text: text}), rating.ErrInvalidReview)
+	review := &model.Review{Title: " Loved it ", Text: text + "\n"}
+	assert.NoError(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user0", review))
+	assert.Equal(t, "Loved it", review.Title)
+	assert.Equal(t, text, review.Text)
+	assert.Equal(t, model.ReviewApproved, review.State)
+	assert.False(t, review.ReviewedAt.IsZero())
+	assert.NoError(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user1", &model.Review{Text: "Fine, if a little long."}))
+
+	assert.ErrorIs(t, c.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user0", true), rating.ErrOwnReview)
+	assert.ErrorIs(t, c.VoteReview(ctx, "1", model.RecordTypeMovie, "user2", "user0", true), rating.ErrReviewNotFound)
+	assert.NoError(t, c.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user2", true))
+
+	reviews, next, err := c.ListReviews(ctx, "1", model.RecordTypeMovie, "", model.ReviewSortHelpful, 1, "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, next)
+	assert.Equal(t, model.UserID("user0"), reviews[0].UserID)
+	assert.Equal(t, int64(1), reviews[0].Review.HelpfulVotes)
+	reviews, last, err := c.ListReviews(ctx, "1", model.RecordTypeMovie, "", model.ReviewSortHelpful, 1, next)
+	assert.NoError(t, err)
+	assert.Empty(t, last)
+	assert.Equal(t, model.UserID("user1"), reviews[0].UserID)
+
+	_, _, err = c.ListReviews(ctx, "1", model.RecordTypeMovie, "", "", 1, next)
+	assert.ErrorIs(t, err, rating.ErrInvalidPageToken)
+	_, _, err = c.ListReviews(ctx, "1", model.RecordTypeMovie, "", "oldest", 1, "")
+	assert.ErrorIs(t, err, rating.ErrInvalidReviewSort)
+}
+
+func TestControllerListReviewsStableUnderWrites(t *testing.T) {
+	repo := memory.New()
+	c := rating.NewController(repo, nil)
+	ctx := context.Background()
+	for _, userID := range []model.UserID{"user0", "user1", "user2", "user3"} {
+		_, err := repo.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: userID, Value: 4})
+		assert.NoError(t, err)
+	}
+	for _, userID := range []model.UserID{"user0", "user1", "user2"} {
+		assert.NoError(t, c.PutReview(ctx, "1", model.RecordTypeMovie, userID, &model.Review{Text: "Great pacing and a memorable score."}))
+		time.Sleep(time.Millisecond)
+	}
+
+	reviews, next, err := c.ListReviews(ctx, "1", model.RecordTypeMovie, "", "", 2, "")
+	assert.NoError(t, err)
+	seen := []model.UserID{reviews[0].UserID, reviews[1].UserID}
+	assert.Equal(t, []model.UserID{"user2", "user1"}, seen)
+
+	// a new review goes to the first page; an offset would then repeat "user1"
+	assert.NoError(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user3", &model.Review{Text: "Great pacing and a memorable score."}))
+	reviews, next, err = c.ListReviews(ctx, "1", model.RecordTypeMovie, "", "", 2, next)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+	for _, r := range reviews {
+		seen = append(seen, r.UserID)
+	}
+	assert.Equal(t, []model.UserID{"user2", "user1", "user0"}, seen)
+}
+
+func TestControllerModeration(t *testing.T) {
+	repo := memory.New()
+	c := rating.NewController(repo, nil, rating.WithModerator(moderation.New(
+		moderation.MinLength(10),
+		moderation.WordList([]string{"spoilerbot"}),
+	)))
+	ctx := context.Background()
+	for _, userID := range []model.UserID{"user0", "user1", "user2"} {
+		_, err := repo.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: userID, Value: 4})
+		assert.NoError(t, err)
+	}
+
+	short := &model.Review{Text: "Meh"}
+	assert.NoError(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user0", short))
+	assert.Equal(t, model.ReviewPending, short.State)
+	assert.NotEmpty(t, short.ModerationReason)
+	blocked := &model.Review{Text: "Follow SpoilerBot for the ending"}
+	assert.NoError(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user1", blocked))
+	assert.Equal(t, model.ReviewRejected, blocked.State)
+	assert.NoError(t, c.PutReview(ctx, "1", model.RecordTypeMovie, "user2", &model.Review{Text: "A solid thriller."}))
+
+	approved, _, err := c.ListReviews(ctx, "1", model.RecordTypeMovie, "", "", 0, "")
+	assert.NoError(t, err)
+	assert.Len(t, approved, 1)
+	assert.Equal(t, model.UserID("user2"), approved[0].UserID)
+	pending, _, err := c.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewPending, "", 0, "")
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.ErrorIs(t, c.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user2", true), rating.ErrReviewNotFound)
+
+	_, _, err = c.ListReviews(ctx, "1", model.RecordTypeMovie, "hidden", "", 0, "")
+	assert.ErrorIs(t, err, rating.ErrInvalidReviewState)
+	assert.ErrorIs(t, c.ModerateReview(ctx, "1", model.RecordTypeMovie, "user0", "hidden", ""), rating.ErrInvalidReviewState)
+	assert.ErrorIs(t, c.ModerateReview(ctx, "2", model.RecordTypeMovie, "user0", model.ReviewApproved, ""), rating.ErrReviewNotFound)
+	assert.NoError(t, c.ModerateReview(ctx, "1", model.RecordTypeMovie, "user0", model.ReviewApproved, " short but fine "))
+	approved, _, err = c.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, "", 0, "")
+	assert.NoError(t, err)
+	assert.Len(t, approved, 2)
+	assert.NoError(t, c.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user2", true))
+}
+
+func TestControllerIngestion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ingesterMock := gen.NewMockrateIngester(ctrl)
+	repo := memory.New()
+	c := rating.NewController(repo, ingesterMock)
+	ctx := context.Background()
+
+	events := make(chan model.RatingEvent, 5)
+	events <- model.RatingEvent{UserID: "user0", RecordID: "1", RecordType: model.RecordTypeMovie, Value: 5}
+	events <- model.RatingEvent{UserID: "user1", RecordID: "1", RecordType: model.RecordTypeMovie, Value: 3, EventType: model.RatingEventPut}
+	events <- model.RatingEvent{UserID: "user0", RecordID: "1", RecordType: model.RecordTypeMovie, EventType: model.RatingEventDelete}
+	events <- model.RatingEvent{UserID: "user0", RecordID: "1", RecordType: model.RecordTypeMovie, EventType: model.RatingEventDelete}
+	close(events)
+	ingesterMock.EXPECT().Ingest(ctx).Return(events, nil)
+
+	assert.NoError(t, c.StartIngestion(ctx))
+	ratings, err := repo.Get(ctx, "1", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Len(t, ratings, 1)
+	assert.Equal(t, model.UserID("user1"), ratings[0].UserID)
+	assert.Equal(t, model.RatingValue(3), ratings[0].Value)
+
+	// bad events are skipped and the good ones after them still applied
+	events = make(chan model.RatingEvent, 3)
+	events <- model.RatingEvent{UserID: "user0", RecordID: "1", RecordType: model.RecordTypeMovie, EventType: "upsert"}
+	events <- model.RatingEvent{UserID: "user2", RecordID: "1", RecordType: model.RecordTypeMovie, Value: 11}
+	events <- model.RatingEvent{UserID: "user3", RecordID: "1", RecordType: model.RecordTypeMovie, Value: 4}
+	close(events)
+	ingesterMock.EXPECT().Ingest(ctx).Return(events, nil)
+	assert.NoError(t, c.StartIngestion(ctx))
+	ratings, err = repo.Get(ctx, "1", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Len(t, ratings, 2)
+	_, err = repo.GetUserRating(ctx, "1", model.RecordTypeMovie, "user3")
 	assert.NoError(t, err)
-	assert.Equal(t, float64(5), res)
 }
@@ -6,7 +6,9 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"movieexample.com/gen"
+	"movieexample.com/pkg/utilities"
 	"movieexample.com/rating/internal/controller/rating"
 	"movieexample.com/rating/pkg/model"
 )
@@ -22,27 +24,227 @@ func New(ctrl *rating.Controller) *Handler {
 	return &Handler{ctrl: ctrl}
 }
 
-// GetAggregatedRating returns the aggregated rating for a record.
+// GetAggregatedRating returns the aggregated rating for a record: the mean rating with the
+// count, histogram, median and standard deviation of the ratings, and a ranking score if asked for.
 func (h *Handler) GetAggregatedRating(ctx context.Context, req *gen.GetAggregatedRatingRequest) (*gen.GetAggregatedRatingResponse, error) {
 	if req == nil || req.RecordId == "" || req.RecordType == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
 	}
-	v, err := h.ctrl.GetAggregateRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType))
+	v, err := h.ctrl.GetAggregateRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), model.RankingFormula(req.Ranking))
 	if err != nil && errors.Is(err, rating.ErrNotFound) {
 		return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+	} else if err != nil && errors.Is(err, rating.ErrInvalidRanking) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s", err.Error())
 	}
-	return &gen.GetAggregatedRatingResponse{RatingValue: v}, nil
+	return model.AggregateToProto(v), nil
 }
 
-// PutRating writes a rating for a given record.
+// BatchGetAggregatedRatings returns the aggregated ratings of several records of a type.
+func (h *Handler) BatchGetAggregatedRatings(ctx context.Context, req *gen.BatchGetAggregatedRatingsRequest) (*gen.BatchGetAggregatedRatingsResponse, error) {
+	if req == nil || req.RecordType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty record type")
+	}
+	ids := make([]model.RecordID, len(req.RecordIds))
+	for i, id := range req.RecordIds {
+		ids[i] = model.RecordID(id)
+	}
+	aggs, err := h.ctrl.GetAggregateRatings(ctx, ids, model.RecordType(req.RecordType))
+	if err != nil && errors.Is(err, rating.ErrBatchTooLarge) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	res := &gen.BatchGetAggregatedRatingsResponse{Ratings: make(map[string]*gen.GetAggregatedRatingResponse, len(aggs))}
+	for id, agg := range aggs {
+		res.Ratings[string(id)] = model.AggregateToProto(agg)
+	}
+	return res, nil
+}
+
+// PutRating writes the rating of a user for a given record, replacing their previous rating.
 func (h *Handler) PutRating(ctx context.Context, req *gen.PutRatingRequest) (*gen.PutRatingResponse, error) {
 	if req == nil || req.RecordId == "" || req.UserId == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id or record id")
 	}
-	if err := h.ctrl.PutRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), &model.Rating{UserID: model.UserID(req.UserId), Value: model.RatingValue(req.RatingValue)}); err != nil {
-		return nil, err
+	created, err := h.ctrl.PutRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), &model.Rating{UserID: model.UserID(req.UserId), Value: model.RatingValue(req.RatingValue)})
+	if err != nil && errors.Is(err, rating.ErrInvalidRatingValue) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	return &gen.PutRatingResponse{Created: created}, nil
+}
+
+// GetRatingScale returns the scale of the ratings of a record type.
+func (h *Handler) GetRatingScale(_ context.Context, req *gen.GetRatingScaleRequest) (*gen.GetRatingScaleResponse, error) {
+	if req == nil || req.RecordType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty record type")
+	}
+	scale := h.ctrl.GetRatingScale(model.RecordType(req.RecordType))
+	return &gen.GetRatingScaleResponse{Min: int32(scale.Min), Max: int32(scale.Max), HalfStars: scale.HalfStars}, nil
+}
+
+// GetUserRating returns the rating a user gave a record.
+func (h *Handler) GetUserRating(ctx context.Context, req *gen.GetUserRatingRequest) (*gen.GetUserRatingResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id or record id")
+	}
+	r, err := h.ctrl.GetUserRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), model.UserID(req.UserId))
+	if err != nil && errors.Is(err, rating.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	return &gen.GetUserRatingResponse{RatingValue: int32(r.Value)}, nil
+}
+
+// DeleteRating removes the rating a user gave a record.
+func (h *Handler) DeleteRating(ctx context.Context, req *gen.DeleteRatingRequest) (*gen.DeleteRatingResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id or record id")
+	}
+	err := h.ctrl.DeleteRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), model.UserID(req.UserId))
+	if err != nil && errors.Is(err, rating.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	return &gen.DeleteRatingResponse{}, nil
+}
+
+// ListTopRated returns the records with the highest mean rating.
+func (h *Handler) ListTopRated(ctx context.Context, req *gen.ListTopRatedRequest) (*gen.ListTopRatedResponse, error) {
+	if req == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req")
+	}
+	entries, err := h.ctrl.ListTopRated(ctx, model.LeaderboardQuery{
+		RecordType: model.RecordType(req.RecordType),
+		MinVotes:   req.MinVotes,
+		Window:     req.Window.AsDuration(),
+		Limit:      int(req.Limit),
+	})
+	if err != nil && errors.Is(err, rating.ErrInvalidLeaderboard) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	return &gen.ListTopRatedResponse{Entries: model.LeaderboardToProto(entries)}, nil
+}
+
+// ListTrending returns the records with the most ratings in a recent time window.
+func (h *Handler) ListTrending(ctx context.Context, req *gen.ListTrendingRequest) (*gen.ListTrendingResponse, error) {
+	if req == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req")
+	}
+	entries, err := h.ctrl.ListTrending(ctx, model.LeaderboardQuery{
+		RecordType: model.RecordType(req.RecordType),
+		MinVotes:   req.MinVotes,
+		Window:     req.Window.AsDuration(),
+		Limit:      int(req.Limit),
+	})
+	if err != nil && errors.Is(err, rating.ErrInvalidLeaderboard) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	return &gen.ListTrendingResponse{Entries: model.LeaderboardToProto(entries)}, nil
+}
+
+// ListUserRatings returns a page of the ratings a user gave, most recently changed first.
+func (h *Handler) ListUserRatings(ctx context.Context, req *gen.ListUserRatingsRequest) (*gen.ListUserRatingsResponse, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id")
+	}
+	ratings, next, err := h.ctrl.ListUserRatings(ctx, model.UserID(req.UserId), model.RecordType(req.RecordType), int(req.PageSize), req.PageToken)
+	if err != nil && (errors.Is(err, rating.ErrInvalidPageToken) || errors.Is(err, rating.ErrInvalidPageSize)) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	res := &gen.ListUserRatingsResponse{NextPageToken: next}
+	for i := range ratings {
+		res.Ratings = append(res.Ratings, model.UserRatingToProto(&ratings[i]))
+	}
+	return res, nil
+}
+
+// PutReview writes the review of a user for a record they rated, replacing their previous review.
+func (h *Handler) PutReview(ctx context.Context, req *gen.PutReviewRequest) (*gen.PutReviewResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id or record id")
+	}
+	review := &model.Review{Title: req.Title, Text: req.Text, Spoiler: req.Spoiler}
+	err := h.ctrl.PutReview(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), model.UserID(req.UserId), review)
+	if err != nil && errors.Is(err, rating.ErrNotFound) {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s", err.Error())
+	} else if err != nil && errors.Is(err, rating.ErrInvalidReview) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	return &gen.PutReviewResponse{
+		ReviewedAt:       timestamppb.New(review.ReviewedAt),
+		State:            string(review.State),
+		ModerationReason: review.ModerationReason,
+	}, nil
+}
+
+// ListReviews returns a page of the approved reviews of a record, newest or most helpful first.
+// Admins can list the reviews in other moderation states.
+func (h *Handler) ListReviews(ctx context.Context, req *gen.ListReviewsRequest) (*gen.ListReviewsResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
+	}
+	state := model.ReviewState(req.State)
+	if state != "" && state != model.ReviewApproved && !utilities.IsAdmin(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "listing reviews that are not approved requires admin access")
+	}
+	ratings, next, err := h.ctrl.ListReviews(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), state, model.ReviewSort(req.Sort), int(req.PageSize), req.PageToken)
+	if err != nil && (errors.Is(err, rating.ErrInvalidReviewState) || errors.Is(err, rating.ErrInvalidReviewSort) || errors.Is(err, rating.ErrInvalidPageToken) || errors.Is(err, rating.ErrInvalidPageSize)) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	res := &gen.ListReviewsResponse{NextPageToken: next}
+	for i := range ratings {
+		res.Reviews = append(res.Reviews, model.ReviewToProto(&ratings[i]))
+	}
+	return res, nil
+}
+
+// VoteReview records whether a user found the review of another user helpful.
+func (h *Handler) VoteReview(ctx context.Context, req *gen.VoteReviewRequest) (*gen.VoteReviewResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.UserId == "" || req.AuthorId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id, author id or record id")
+	}
+	err := h.ctrl.VoteReview(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), model.UserID(req.AuthorId), model.UserID(req.UserId), req.Helpful)
+	if err != nil && errors.Is(err, rating.ErrReviewNotFound) {
+		return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+	} else if err != nil && errors.Is(err, rating.ErrOwnReview) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	return &gen.VoteReviewResponse{}, nil
+}
+
+// ModerateReview sets the moderation state of a review. It requires admin access.
+func (h *Handler) ModerateReview(ctx context.Context, req *gen.ModerateReviewRequest) (*gen.ModerateReviewResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id or record id")
+	}
+	if !utilities.IsAdmin(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "moderating reviews requires admin access")
+	}
+	err := h.ctrl.ModerateReview(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), model.UserID(req.UserId), model.ReviewState(req.State), req.Reason)
+	if err != nil && errors.Is(err, rating.ErrReviewNotFound) {
+		return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+	} else if err != nil && errors.Is(err, rating.ErrInvalidReviewState) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
 	}
-	return &gen.PutRatingResponse{}, nil
+	return &gen.ModerateReviewResponse{}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"movieexample.com/rating/internal/controller/rating"
 	"movieexample.com/rating/pkg/model"
@@ -24,8 +25,13 @@ func NewHandler(ctrl *rating.Controller) *Handler {
 }
 
 // Handle processes an HTTP request related to the rating functionality. It supports
-// GET requests to retrieve the aggregate rating for a given record ID and type, and
-// PUT requests to update the rating for a given record ID, type, and user ID.
+// GET requests to retrieve the aggregate rating for a given record ID and type, written as a
+// model.RatingAggregate with the count, mean, median, standard deviation and histogram, or the rating
+// of a single user with a userId parameter, and PUT requests to update the rating for a given
+// record ID, type, and user ID. A PUT value must be an integer on the scale of the type, or the
+// request answers 400 Bad Request. A PUT answers 201 Created when the user had not rated the
+// record yet and 200 OK when their previous rating was replaced. DELETE requests remove the rating of
+// the user given by userId and answer 204 No Content, or 404 Not Found if there is none.
 func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	// Handle the HTTP request here
 	recorID := model.RecordID(r.FormValue("id"))
@@ -41,10 +47,31 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 	switch r.Method {
 	case http.MethodGet:
-		v, err := h.ctrl.GetAggregateRating(r.Context(), recorID, recordType)
+		if userID := model.UserID(r.FormValue("userId")); userID != "" {
+			v, err := h.ctrl.GetUserRating(r.Context(), recorID, recordType, userID)
+			if err != nil && errors.Is(err, rating.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			} else if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if err := json.NewEncoder(w).Encode(v.Value); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			return
+		}
+		v, err := h.ctrl.GetAggregateRating(r.Context(), recorID, recordType, model.RankingFormula(r.FormValue("ranking")))
 		if err != nil && errors.Is(err, rating.ErrNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			return
+		} else if err != nil && errors.Is(err, rating.ErrInvalidRanking) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 		if err := json.NewEncoder(w).Encode(v); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -52,19 +79,295 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) {
 		}
 	case http.MethodPut:
 		userID := model.UserID(r.FormValue("userId"))
-		v, err := strconv.ParseFloat(r.FormValue("value"), 64)
+		if userID == "" {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+		v, err := strconv.Atoi(r.FormValue("value"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if err := h.ctrl.PutRating(r.Context(), recorID, recordType, &model.Rating{
+		created, err := h.ctrl.PutRating(r.Context(), recorID, recordType, &model.Rating{
 			UserID: userID,
 			Value:  model.RatingValue(v),
-		}); err != nil {
+		})
+		if err != nil && errors.Is(err, rating.ErrInvalidRatingValue) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		if created {
+			w.WriteHeader(http.StatusCreated)
+		}
+	case http.MethodDelete:
+		userID := model.UserID(r.FormValue("userId"))
+		if userID == "" {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+		err := h.ctrl.DeleteRating(r.Context(), recorID, recordType, userID)
+		if err != nil && errors.Is(err, rating.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
 	default:
 		w.WriteHeader(http.StatusBadRequest)
 	}
 }
+
+// RatingScale handles GET requests for the scale of the ratings of the record type given by type,
+// written as a model.RatingScale.
+func (h *Handler) RatingScale(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	recordType := model.RecordType(r.FormValue("type"))
+	if recordType == "" {
+		http.Error(w, "Invalid record type", http.StatusBadRequest)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(h.ctrl.GetRatingScale(recordType)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Aggregates handles GET requests for the aggregate ratings of the records of type given by the
+// repeated id parameter, written as a map from record ID to model.RatingAggregate. Records that
+// have not been rated are left out. More than rating.MaxBatchSize IDs answer 400 Bad Request.
+func (h *Handler) Aggregates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	recordType := model.RecordType(r.FormValue("type"))
+	if recordType == "" {
+		http.Error(w, "Invalid record type", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var ids []model.RecordID
+	for _, id := range r.Form["id"] {
+		ids = append(ids, model.RecordID(id))
+	}
+	aggs, err := h.ctrl.GetAggregateRatings(r.Context(), ids, recordType)
+	if err != nil && errors.Is(err, rating.ErrBatchTooLarge) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(aggs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Leaderboard handles GET requests for a leaderboard of records, top_rated or trending as given by
+// the leaderboard parameter, written as a list of model.LeaderboardEntry. The type, minVotes,
+// window and limit parameters fill in the model.LeaderboardQuery, with the window written as a
+// duration such as 168h.
+func (h *Handler) Leaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	query := model.LeaderboardQuery{RecordType: model.RecordType(r.FormValue("type"))}
+	var err error
+	if v := r.FormValue("minVotes"); v != "" {
+		if query.MinVotes, err = strconv.ParseInt(v, 10, 64); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.FormValue("window"); v != "" {
+		if query.Window, err = time.ParseDuration(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.FormValue("limit"); v != "" {
+		if query.Limit, err = strconv.Atoi(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var entries []model.LeaderboardEntry
+	switch model.Leaderboard(r.FormValue("leaderboard")) {
+	case model.LeaderboardTopRated:
+		entries, err = h.ctrl.ListTopRated(r.Context(), query)
+	case model.LeaderboardTrending:
+		entries, err = h.ctrl.ListTrending(r.Context(), query)
+	default:
+		http.Error(w, "Invalid leaderboard, want top_rated or trending", http.StatusBadRequest)
+		return
+	}
+	if err != nil && errors.Is(err, rating.ErrInvalidLeaderboard) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// userRatingsResponse is the body of a ListUserRatings response.
+type userRatingsResponse struct {
+	Ratings       []model.Rating `json:"ratings"`
+	NextPageToken string         `json:"next_page_token,omitempty"`
+}
+
+// ListUserRatings handles GET requests for a page of the ratings of the user given by userId,
+// most recently changed first, optionally restricted to the records of type. Paging reads
+// page_size and page_token.
+func (h *Handler) ListUserRatings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	userID := model.UserID(r.FormValue("userId"))
+	if userID == "" {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	var pageSize int
+	if v := r.FormValue("page_size"); v != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	ratings, next, err := h.ctrl.ListUserRatings(r.Context(), userID, model.RecordType(r.FormValue("type")), pageSize, r.FormValue("page_token"))
+	if err != nil && (errors.Is(err, rating.ErrInvalidPageToken) || errors.Is(err, rating.ErrInvalidPageSize)) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(userRatingsResponse{Ratings: ratings, NextPageToken: next}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// reviewsResponse is the body of a ListReviews response.
+type reviewsResponse struct {
+	Reviews       []model.Rating `json:"reviews"`
+	NextPageToken string         `json:"next_page_token,omitempty"`
+}
+
+// Reviews handles the reviews of the record given by id and type. GET requests list a page of the
+// approved ones, ordered as given by sort, as ratings with their model.Review; paging reads
+// page_size and page_token. PUT requests write the review of the user given by userId from the
+// title, text and spoiler parameters and answer with the moderated model.Review, or 409 Conflict
+// if the user has not rated the record.
+func (h *Handler) Reviews(w http.ResponseWriter, r *http.Request) {
+	recordID := model.RecordID(r.FormValue("id"))
+	recordType := model.RecordType(r.FormValue("type"))
+	if recordID == "" || recordType == "" {
+		http.Error(w, "Invalid record ID or type", http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		var pageSize int
+		if v := r.FormValue("page_size"); v != "" {
+			var err error
+			if pageSize, err = strconv.Atoi(v); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		ratings, next, err := h.ctrl.ListReviews(r.Context(), recordID, recordType, model.ReviewApproved, model.ReviewSort(r.FormValue("sort")), pageSize, r.FormValue("page_token"))
+		if err != nil && (errors.Is(err, rating.ErrInvalidReviewSort) || errors.Is(err, rating.ErrInvalidPageToken) || errors.Is(err, rating.ErrInvalidPageSize)) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(reviewsResponse{Reviews: ratings, NextPageToken: next}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	case http.MethodPut:
+		userID := model.UserID(r.FormValue("userId"))
+		if userID == "" {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+		spoiler, _ := strconv.ParseBool(r.FormValue("spoiler"))
+		review := &model.Review{Title: r.FormValue("title"), Text: r.FormValue("text"), Spoiler: spoiler}
+		err := h.ctrl.PutReview(r.Context(), recordID, recordType, userID, review)
+		if err != nil && errors.Is(err, rating.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		} else if err != nil && errors.Is(err, rating.ErrInvalidReview) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(review); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// VoteReview handles PUT requests voting whether the review the user given by authorId wrote of
+// the record given by id and type was helpful, as given by the helpful parameter, to the user
+// given by userId. It answers 204 No Content, or 404 Not Found if there is no such review.
+func (h *Handler) VoteReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	recordID := model.RecordID(r.FormValue("id"))
+	recordType := model.RecordType(r.FormValue("type"))
+	authorID := model.UserID(r.FormValue("authorId"))
+	userID := model.UserID(r.FormValue("userId"))
+	if recordID == "" || recordType == "" || authorID == "" || userID == "" {
+		http.Error(w, "Invalid record ID, type, author ID or user ID", http.StatusBadRequest)
+		return
+	}
+	helpful, err := strconv.ParseBool(r.FormValue("helpful"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = h.ctrl.VoteReview(r.Context(), recordID, recordType, authorID, userID, helpful)
+	if err != nil && errors.Is(err, rating.ErrReviewNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil && errors.Is(err, rating.ErrOwnReview) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// DefaultChecks returns the heuristics applied to every review unless configured otherwise:
+// reviews shorter than 20 characters or written mostly in capital letters are flagged, and
+// reviews with more than 2 links are rejected.
+func DefaultChecks() []Check {
+	return []Check{
+		MinLength(20),
+		AllCaps(20, 0.7),
+		LinkSpam(2),
+	}
+}
+
+// WordList rejects reviews whose title or text contains any of the words, ignoring case. Words
+// only match whole words of the review, so a blocked word inside a longer one is let through.
+func WordList(words []string) Check {
+	blocked := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			blocked[w] = struct{}{}
+		}
+	}
+	return CheckFunc(func(_ context.Context, review *model.Review) Result {
+		for _, w := range splitWords(review.Title + " " + review.Text) {
+			if _, ok := blocked[w]; ok {
+				return Result{Verdict: Reject, Reason: fmt.Sprintf("contains blocked word %q", w)}
+			}
+		}
+		return Result{}
+	})
+}
+
+// linkPattern matches web addresses, with or without a scheme.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkSpam rejects reviews with more than maxLinks links in their title and text.
+func LinkSpam(maxLinks int) Check {
+	return CheckFunc(func(_ context.Context, review *model.Review) Result {
+		n := len(linkPattern.FindAllStringIndex(review.Title+" "+review.Text, -1))
+		if n > maxLinks {
+			return Result{Verdict: Reject, Reason: fmt.Sprintf("%d links, at most %d allowed", n, maxLinks)}
+		}
+		return Result{}
+	})
+}
+
+// MinLength flags reviews whose text is shorter than n characters.
+func MinLength(n int) Check {
+	return CheckFunc(func(_ context.Context, review *model.Review) Result {
+		if utf8.RuneCountInString(review.Text) < n {
+			return Result{Verdict: Flag, Reason: fmt.Sprintf("shorter than %d characters", n)}
+		}
+		return Result{}
+	})
+}
+
+// AllCaps flags reviews with at least minLetters letters in their title and text of which more
+// than maxShare are capital letters.
+func AllCaps(minLetters int, maxShare float64) Check {
+	return CheckFunc(func(_ context.Context, review *model.Review) Result {
+		var letters, upper int
+		for _, r := range review.Title + review.Text {
+			switch {
+			case unicode.IsUpper(r):
+				upper++
+				letters++
+			case unicode.IsLetter(r):
+				letters++
+			}
+		}
+		if letters >= minLetters && float64(upper) > maxShare*float64(letters) {
+			return Result{Verdict: Flag, Reason: "mostly capital letters"}
+		}
+		return Result{}
+	})
+}
+
+// splitWords splits s into lower case words of letters and digits.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
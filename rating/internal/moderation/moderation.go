@@ -0,0 +1,78 @@
+// Package moderation decides whether reviews can be shown by running them through a set of
+// checks. Checks are pluggable: anything implementing Check can be added to a Moderator, next
+// to or instead of the heuristics of this package.
+package moderation
+
+import (
+	"context"
+	"strings"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// Verdict is the outcome of a check. Verdicts are ordered from the most lenient to the strictest.
+type Verdict int
+
+const (
+	// Pass lets the review through as far as the check is concerned.
+	Pass Verdict = iota
+	// Flag holds the review back for a moderator to decide.
+	Flag
+	// Reject turns the review down.
+	Reject
+)
+
+// Result is the verdict of a check along with the reason for it, which is empty for Pass.
+type Result struct {
+	Verdict Verdict
+	Reason  string
+}
+
+// Check inspects a review before it is stored.
+type Check interface {
+	Check(ctx context.Context, review *model.Review) Result
+}
+
+// CheckFunc adapts a function to the Check interface.
+type CheckFunc func(ctx context.Context, review *model.Review) Result
+
+// Check calls f.
+func (f CheckFunc) Check(ctx context.Context, review *model.Review) Result {
+	return f(ctx, review)
+}
+
+// Moderator runs a review through its checks.
+type Moderator struct {
+	checks []Check
+}
+
+// New returns a Moderator running the given checks. A Moderator without checks approves every review.
+func New(checks ...Check) *Moderator {
+	return &Moderator{checks: checks}
+}
+
+// Moderate runs every check on the review and returns the state matching the strictest verdict:
+// rejected if any check rejects it, pending if any flags it and approved otherwise, along with the
+// reasons of the checks that did not pass it.
+func (m *Moderator) Moderate(ctx context.Context, review *model.Review) (model.ReviewState, string) {
+	verdict := Pass
+	var reasons []string
+	for _, check := range m.checks {
+		res := check.Check(ctx, review)
+		if res.Verdict == Pass {
+			continue
+		}
+		verdict = max(verdict, res.Verdict)
+		reasons = append(reasons, res.Reason)
+	}
+	var state model.ReviewState
+	switch verdict {
+	case Reject:
+		state = model.ReviewRejected
+	case Flag:
+		state = model.ReviewPending
+	default:
+		state = model.ReviewApproved
+	}
+	return state, strings.Join(reasons, "; ")
+}
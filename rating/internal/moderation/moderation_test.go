@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"movieexample.com/rating/pkg/model"
+)
+
+func TestChecks(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		check  Check
+		review model.Review
+		want   Verdict
+	}{
+		{"word list match", WordList([]string{"Scam"}), model.Review{Text: "What a scam, avoid."}, Reject},
+		{"word list in title", WordList([]string{"scam"}), model.Review{Title: "SCAM", Text: "Avoid."}, Reject},
+		{"word list inside word", WordList([]string{"scam"}), model.Review{Text: "Scampi for dinner."}, Pass},
+		{"links under limit", LinkSpam(2), model.Review{Text: "See https://example.com and www.example.org"}, Pass},
+		{"links over limit", LinkSpam(2), model.Review{Text: "http://a.com http://b.com WWW.c.com"}, Reject},
+		{"too short", MinLength(20), model.Review{Text: "Good."}, Flag},
+		{"long enough", MinLength(5), model.Review{Text: "Good."}, Pass},
+		{"all caps", AllCaps(10, 0.7), model.Review{Text: "BEST MOVIE EVER MADE!!!"}, Flag},
+		{"some caps", AllCaps(10, 0.7), model.Review{Text: "The BEST movie I have seen this year."}, Pass},
+		{"all caps too short to tell", AllCaps(10, 0.7), model.Review{Text: "WOW"}, Pass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.check.Check(ctx, &tt.review)
+			assert.Equal(t, tt.want, res.Verdict)
+			assert.Equal(t, tt.want != Pass, res.Reason != "")
+		})
+	}
+}
+
+func TestModerate(t *testing.T) {
+	ctx := context.Background()
+	m := New(DefaultChecks()...)
+
+	state, reason := m.Moderate(ctx, &model.Review{Text: "A slow start, but the last act makes up for it."})
+	assert.Equal(t, model.ReviewApproved, state)
+	assert.Empty(t, reason)
+
+	state, reason = m.Moderate(ctx, &model.Review{Text: "MEH"})
+	assert.Equal(t, model.ReviewPending, state)
+	assert.NotEmpty(t, reason)
+
+	// the strictest verdict wins and every reason is kept
+	state, reason = m.Moderate(ctx, &model.Review{Text: "www.a www.b www.c"})
+	assert.Equal(t, model.ReviewRejected, state)
+	assert.Contains(t, reason, "links")
+	assert.Contains(t, reason, "characters")
+
+	state, _ = New().Moderate(ctx, &model.Review{Text: "MEH"})
+	assert.Equal(t, model.ReviewApproved, state)
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// ListTopRated ranks records by their mean rating, then by their number of ratings. Without a time
+// window it walks the index kept sorted by Put and DeleteRating; with one it counts the ratings
+// in the window.
+func (r *Repository) ListTopRated(_ context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if query.Window == 0 {
+		return leaderboard(r.topRated, query), nil
+	}
+	aggs := r.window(query, now)
+	slices.SortFunc(aggs, compareTopRated)
+	return leaderboard(aggs, query), nil
+}
+
+// ListTrending ranks records by their number of ratings in the time window, then by their mean.
+func (r *Repository) ListTrending(_ context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	r.RLock()
+	defer r.RUnlock()
+	aggs := r.window(query, now)
+	slices.SortFunc(aggs, compareTrending)
+	return leaderboard(aggs, query), nil
+}
+
+// reindex moves the aggregate to its place in the top rated index after change updates it.
+// The caller must hold the write lock.
+func (r *Repository) reindex(agg *aggregate, change func()) {
+	if i, ok := slices.BinarySearchFunc(r.topRated, agg, compareTopRated); ok {
+		r.topRated = slices.Delete(r.topRated, i, i+1)
+	}
+	change()
+	if agg.count > 0 {
+		i, _ := slices.BinarySearchFunc(r.topRated, agg, compareTopRated)
+		r.topRated = slices.Insert(r.topRated, i, agg)
+	}
+}
+
+// window returns the totals of the ratings of every record of the query type given or changed
+// in its window before now.
+func (r *Repository) window(query model.LeaderboardQuery, now time.Time) []*aggregate {
+	since := now.Add(-query.Window)
+	var res []*aggregate
+	for recordType, records := range r.data {
+		if query.RecordType != "" && recordType != query.RecordType {
+			continue
+		}
+		for recordID, ratings := range records {
+			agg := newAggregate(recordID, recordType)
+			for _, rating := range ratings {
+				if !rating.UpdatedAt.Before(since) {
+					agg.add(rating.Value, 1)
+				}
+			}
+			if agg.count > 0 {
+				res = append(res, agg)
+			}
+		}
+	}
+	return res
+}
+
+// leaderboard ranks the sorted aggregates that pass the filters of the query, up to its limit.
+// Aggregates with the same count and sum tie, which matches both orders.
+func leaderboard(aggs []*aggregate, query model.LeaderboardQuery) []model.LeaderboardEntry {
+	var res []model.LeaderboardEntry
+	var prev *aggregate
+	for _, agg := range aggs {
+		if len(res) == query.Limit {
+			break
+		}
+		if (query.RecordType != "" && agg.recordType != query.RecordType) || agg.count < query.MinVotes {
+			continue
+		}
+		rank := int64(len(res)) + 1
+		if prev != nil && prev.count == agg.count && prev.sum == agg.sum {
+			rank = res[len(res)-1].Rank
+		}
+		res = append(res, model.LeaderboardEntry{
+			Rank:       rank,
+			RecordID:   agg.recordID,
+			RecordType: agg.recordType,
+			Count:      agg.count,
+			Mean:       float64(agg.sum) / float64(agg.count),
+		})
+		prev = agg
+	}
+	return res
+}
+
+// compareTopRated orders aggregates by mean descending, then by count descending and then by
+// record type and ID. The means are compared by cross multiplication to stay exact.
+func compareTopRated(a, b *aggregate) int {
+	return cmp.Or(
+		cmp.Compare(b.sum*a.count, a.sum*b.count),
+		cmp.Compare(b.count, a.count),
+		cmp.Compare(a.recordType, b.recordType),
+		cmp.Compare(a.recordID, b.recordID),
+	)
+}
+
+// compareTrending orders aggregates by count descending, then by mean descending and then by
+// record type and ID.
+func compareTrending(a, b *aggregate) int {
+	return cmp.Or(
+		cmp.Compare(b.count, a.count),
+		cmp.Compare(b.sum*a.count, a.sum*b.count),
+		cmp.Compare(a.recordType, b.recordType),
+		cmp.Compare(a.recordID, b.recordID),
+	)
+}
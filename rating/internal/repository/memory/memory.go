@@ -1,7 +1,13 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"time"
 
 	"movieexample.com/rating/internal/repository"
 	"movieexample.com/rating/pkg/model"
@@ -9,38 +15,248 @@ import (
 
 // Repository is an in-memory implementation of the rating.Repository interface.
 // It stores ratings in a nested map, with the outer map keyed by RecordType
-// and the inner map keyed by RecordID, storing a slice of Rating values.
+// and the inner map keyed by RecordID, storing a slice of Rating values with
+// at most one rating per user. The aggregate of every record is kept up to date
+// alongside, the way the database repository maintains it, and indexed by mean
+// rating for the top rated leaderboard. The records each user rated are indexed
+// by user for listing their ratings. Reviews and the votes on them are kept
+// apart from the ratings, keyed by record and author, and go away with the rating.
 type Repository struct {
-	data map[model.RecordType]map[model.RecordID][]model.Rating
+	sync.RWMutex
+	data       map[model.RecordType]map[model.RecordID][]model.Rating
+	aggregates map[model.RecordType]map[model.RecordID]*aggregate
+	topRated   []*aggregate
+	byUser     map[model.UserID]map[recordKey]struct{}
+	reviews    map[reviewKey]*model.Review
+	votes      map[reviewKey]map[model.UserID]bool
+}
+
+// recordKey identifies a record across record types.
+type recordKey struct {
+	recordType model.RecordType
+	recordID   model.RecordID
+}
+
+// aggregate holds the running totals of the ratings of a record.
+type aggregate struct {
+	recordID   model.RecordID
+	recordType model.RecordType
+	count      int64
+	sum        int64
+	histogram  map[model.RatingValue]int64
 }
 
 // New returns a new in-memory implementation of the rating.Repository interface.
 // It initializes the data map to store ratings by RecordType and RecordID.
 func New() *Repository {
 	return &Repository{
-		data: map[model.RecordType]map[model.RecordID][]model.Rating{},
+		data:       map[model.RecordType]map[model.RecordID][]model.Rating{},
+		aggregates: map[model.RecordType]map[model.RecordID]*aggregate{},
+		byUser:     map[model.UserID]map[recordKey]struct{}{},
+		reviews:    map[reviewKey]*model.Review{},
+		votes:      map[reviewKey]map[model.UserID]bool{},
 	}
 }
 
 // Get retrieves the ratings for the specified record ID and record type. If no
 // ratings are found for the given record, it returns an ErrNotFound error.
 func (r *Repository) Get(_ context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
+	r.RLock()
+	defer r.RUnlock()
 	if _, ok := r.data[recordType]; !ok {
 		return nil, repository.ErrNotFound
 	}
 	if ratings, ok := r.data[recordType][recordID]; !ok || len(ratings) == 0 {
 		return nil, repository.ErrNotFound
 	}
-	return r.data[recordType][recordID], nil
+	return slices.Clone(r.data[recordType][recordID]), nil
 }
 
-// Put stores the provided rating for the specified record ID and record type.
-// If the record type or record ID does not exist in the repository, it will
-// create new entries for them.
-func (r *Repository) Put(_ context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+// GetUserRating retrieves the rating the user gave the specified record. If the
+// user has not rated it, it returns an ErrNotFound error.
+func (r *Repository) GetUserRating(_ context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) (*model.Rating, error) {
+	r.RLock()
+	defer r.RUnlock()
+	ratings := r.data[recordType][recordID]
+	i := slices.IndexFunc(ratings, func(rating model.Rating) bool { return rating.UserID == userID })
+	if i < 0 {
+		return nil, repository.ErrNotFound
+	}
+	rating := ratings[i]
+	return &rating, nil
+}
+
+// Put stores the provided rating for the specified record ID and record type,
+// replacing the previous rating of the same user, and sets its timestamps. It
+// reports whether the rating was added rather than replaced. If the record type
+// or record ID does not exist in the repository, it will create new entries for them.
+func (r *Repository) Put(_ context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) (bool, error) {
+	r.Lock()
+	defer r.Unlock()
 	if _, ok := r.data[recordType]; !ok {
 		r.data[recordType] = map[model.RecordID][]model.Rating{}
 	}
-	r.data[recordType][recordID] = append(r.data[recordType][recordID], *rating)
+	rating.UpdatedAt = time.Now().UTC()
+	rating.CreatedAt = rating.UpdatedAt
+	agg := r.aggregate(recordID, recordType)
+	ratings := r.data[recordType][recordID]
+	if i := slices.IndexFunc(ratings, func(old model.Rating) bool { return old.UserID == rating.UserID }); i >= 0 {
+		rating.CreatedAt = ratings[i].CreatedAt
+		r.reindex(agg, func() {
+			agg.add(ratings[i].Value, -1)
+			agg.add(rating.Value, 1)
+		})
+		ratings[i] = *rating
+		return false, nil
+	}
+	r.reindex(agg, func() { agg.add(rating.Value, 1) })
+	r.data[recordType][recordID] = append(ratings, *rating)
+	if _, ok := r.byUser[rating.UserID]; !ok {
+		r.byUser[rating.UserID] = map[recordKey]struct{}{}
+	}
+	r.byUser[rating.UserID][recordKey{recordType, recordID}] = struct{}{}
+	return true, nil
+}
+
+// DeleteRating removes the rating the user gave the specified record. If the
+// user has not rated it, it returns an ErrNotFound error.
+func (r *Repository) DeleteRating(_ context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) error {
+	r.Lock()
+	defer r.Unlock()
+	ratings := r.data[recordType][recordID]
+	i := slices.IndexFunc(ratings, func(rating model.Rating) bool { return rating.UserID == userID })
+	if i < 0 {
+		return repository.ErrNotFound
+	}
+	agg := r.aggregate(recordID, recordType)
+	r.reindex(agg, func() { agg.add(ratings[i].Value, -1) })
+	r.data[recordType][recordID] = slices.Delete(ratings, i, i+1)
+	delete(r.byUser[userID], recordKey{recordType, recordID})
+	delete(r.reviews, reviewKey{recordKey{recordType, recordID}, userID})
+	delete(r.votes, reviewKey{recordKey{recordType, recordID}, userID})
 	return nil
 }
+
+// ListUserRatings returns a page of the ratings of the user after the cursor, most recently changed
+// first, found through the index by user. An empty record type lists ratings of all types.
+func (r *Repository) ListUserRatings(_ context.Context, userID model.UserID, recordType model.RecordType, after *model.UserRatingsCursor, limit int) ([]model.Rating, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var res []model.Rating
+	for key := range r.byUser[userID] {
+		if recordType != "" && key.recordType != recordType {
+			continue
+		}
+		ratings := r.data[key.recordType][key.recordID]
+		i := slices.IndexFunc(ratings, func(rating model.Rating) bool { return rating.UserID == userID })
+		if i < 0 {
+			continue
+		}
+		rating := ratings[i]
+		rating.MovieID, rating.RecordType = key.recordID, key.recordType
+		if after != nil && compareUserRatings(userRatingsCursor(rating), *after) >= 0 {
+			continue
+		}
+		res = append(res, rating)
+	}
+	slices.SortFunc(res, func(a, b model.Rating) int {
+		return compareUserRatings(userRatingsCursor(b), userRatingsCursor(a))
+	})
+	return res[:min(limit, len(res))], nil
+}
+
+func userRatingsCursor(rating model.Rating) model.UserRatingsCursor {
+	return model.UserRatingsCursor{UpdatedAt: rating.UpdatedAt, RecordType: rating.RecordType, RecordID: rating.MovieID}
+}
+
+// compareUserRatings compares cursors by updated at, record type and record ID, ascending; the
+// ratings of a user are listed in the reverse order.
+func compareUserRatings(a, b model.UserRatingsCursor) int {
+	return cmp.Or(
+		a.UpdatedAt.Compare(b.UpdatedAt),
+		cmp.Compare(a.RecordType, b.RecordType),
+		cmp.Compare(a.RecordID, b.RecordID),
+	)
+}
+
+// GetHistogram returns the histogram of the specified record kept up to date by
+// Put and DeleteRating.
+func (r *Repository) GetHistogram(_ context.Context, recordID model.RecordID, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if agg, ok := r.aggregates[recordType][recordID]; ok {
+		return maps.Clone(agg.histogram), nil
+	}
+	return map[model.RatingValue]int64{}, nil
+}
+
+// GetHistograms returns the histograms of the specified records that have been rated.
+func (r *Repository) GetHistograms(_ context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]map[model.RatingValue]int64, error) {
+	r.RLock()
+	defer r.RUnlock()
+	histograms := map[model.RecordID]map[model.RatingValue]int64{}
+	for _, id := range recordIDs {
+		if agg, ok := r.aggregates[recordType][id]; ok && agg.count > 0 {
+			histograms[id] = maps.Clone(agg.histogram)
+		}
+	}
+	return histograms, nil
+}
+
+// GetRecordTypeHistogram adds up the histograms of all records of the type.
+func (r *Repository) GetRecordTypeHistogram(_ context.Context, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	r.RLock()
+	defer r.RUnlock()
+	histogram := map[model.RatingValue]int64{}
+	for _, agg := range r.aggregates[recordType] {
+		for v, n := range agg.histogram {
+			histogram[v] += n
+		}
+	}
+	return histogram, nil
+}
+
+// GetDecayedMean returns the mean rating of the specified record with every rating
+// weighed by 0.5 to the power of its age at now in half-lives, or 0 if there are no ratings.
+func (r *Repository) GetDecayedMean(_ context.Context, recordID model.RecordID, recordType model.RecordType, halfLife time.Duration, now time.Time) (float64, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var sum, weights float64
+	for _, rating := range r.data[recordType][recordID] {
+		w := math.Pow(0.5, now.Sub(rating.UpdatedAt).Seconds()/halfLife.Seconds())
+		sum += float64(rating.Value) * w
+		weights += w
+	}
+	if weights == 0 {
+		return 0, nil
+	}
+	return sum / weights, nil
+}
+
+// aggregate returns the aggregate of the specified record, creating it if needed.
+// The caller must hold the write lock.
+func (r *Repository) aggregate(recordID model.RecordID, recordType model.RecordType) *aggregate {
+	if _, ok := r.aggregates[recordType]; !ok {
+		r.aggregates[recordType] = map[model.RecordID]*aggregate{}
+	}
+	agg, ok := r.aggregates[recordType][recordID]
+	if !ok {
+		agg = newAggregate(recordID, recordType)
+		r.aggregates[recordType][recordID] = agg
+	}
+	return agg
+}
+
+func newAggregate(recordID model.RecordID, recordType model.RecordType) *aggregate {
+	return &aggregate{recordID: recordID, recordType: recordType, histogram: map[model.RatingValue]int64{}}
+}
+
+// add counts delta more ratings of value v.
+func (a *aggregate) add(v model.RatingValue, delta int64) {
+	a.count += delta
+	a.sum += delta * int64(v)
+	a.histogram[v] += delta
+	if a.histogram[v] == 0 {
+		delete(a.histogram, v)
+	}
+}
@@ -3,8 +3,10 @@ package memory
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"movieexample.com/rating/internal/repository"
 	"movieexample.com/rating/pkg/model"
 )
 
@@ -78,7 +80,7 @@ func TestRepository_Put(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.r.Put(tt.args.in0, tt.args.recordID, tt.args.recordType, tt.args.rating)
+			_, err := tt.r.Put(tt.args.in0, tt.args.recordID, tt.args.recordType, tt.args.rating)
 			assert.Equal(t, tt.wantErr, err != nil)
 		})
 	}
@@ -94,6 +96,297 @@ func TestPutToGet(t *testing.T) {
 		MovieID: "1",
 		Value:   1,
 	}
-	err := repo.Put(context.Background(), ratingRecord.MovieID, model.RecordTypeMovie, ratingRecord)
+	_, err := repo.Put(context.Background(), ratingRecord.MovieID, model.RecordTypeMovie, ratingRecord)
 	assert.NoError(t, err)
 }
+
+func TestPutReplacesUserRating(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+
+	created, err := r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user0", Value: 5})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	created, err = r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user1", Value: 3})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	created, err = r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user0", Value: 1})
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	ratings, err := r.Get(ctx, "1", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Rating{{UserID: "user0", Value: 1}, {UserID: "user1", Value: 3}}, withoutTimes(t, ratings))
+
+	rating, err := r.GetUserRating(ctx, "1", model.RecordTypeMovie, "user0")
+	assert.NoError(t, err)
+	assert.Equal(t, model.RatingValue(1), rating.Value)
+	_, err = r.GetUserRating(ctx, "1", model.RecordTypeMovie, "user2")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.NoError(t, r.DeleteRating(ctx, "1", model.RecordTypeMovie, "user0"))
+	assert.ErrorIs(t, r.DeleteRating(ctx, "1", model.RecordTypeMovie, "user0"), repository.ErrNotFound)
+	ratings, err = r.Get(ctx, "1", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Rating{{UserID: "user1", Value: 3}}, withoutTimes(t, ratings))
+}
+
+func TestGetDecayedMean(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	now := time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC)
+	halfLife := 24 * time.Hour
+	r.data[model.RecordTypeMovie] = map[model.RecordID][]model.Rating{
+		"1": {
+			{UserID: "user0", Value: 5, UpdatedAt: now},
+			{UserID: "user1", Value: 2, UpdatedAt: now.Add(-halfLife)},
+		},
+	}
+
+	mean, err := r.GetDecayedMean(ctx, "1", model.RecordTypeMovie, halfLife, now)
+	assert.NoError(t, err)
+	assert.InDelta(t, 4, mean, 1e-9)
+
+	mean, err = r.GetDecayedMean(ctx, "2", model.RecordTypeMovie, halfLife, now)
+	assert.NoError(t, err)
+	assert.Zero(t, mean)
+}
+
+// withoutTimes checks that every rating has its timestamps set and clears them for comparison.
+func withoutTimes(t *testing.T, ratings []model.Rating) []model.Rating {
+	t.Helper()
+	for i := range ratings {
+		assert.False(t, ratings[i].CreatedAt.IsZero())
+		assert.False(t, ratings[i].UpdatedAt.Before(ratings[i].CreatedAt))
+		ratings[i].CreatedAt, ratings[i].UpdatedAt = time.Time{}, time.Time{}
+	}
+	return ratings
+}
+
+func TestListUserRatings(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	for _, p := range []struct {
+		recordID   model.RecordID
+		recordType model.RecordType
+		userID     model.UserID
+		value      model.RatingValue
+	}{
+		{"1", model.RecordTypeMovie, "user0", 5},
+		{"2", model.RecordTypeMovie, "user0", 3},
+		{"3", "show", "user0", 4},
+		{"1", model.RecordTypeMovie, "user1", 2},
+		{"1", model.RecordTypeMovie, "user0", 4},
+	} {
+		_, err := r.Put(ctx, p.recordID, p.recordType, &model.Rating{UserID: p.userID, Value: p.value})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, r.DeleteRating(ctx, "2", model.RecordTypeMovie, "user0"))
+	// order the ratings by time regardless of the clock resolution
+	base := time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC)
+	movie, show := &r.data[model.RecordTypeMovie]["1"][0], &r.data["show"]["3"][0]
+	movie.CreatedAt, movie.UpdatedAt = base, base.Add(2*time.Hour)
+	show.CreatedAt, show.UpdatedAt = base, base.Add(time.Hour)
+
+	ratings, err := r.ListUserRatings(ctx, "user0", "", nil, 10)
+	assert.NoError(t, err)
+	first, second := userRatingsCursor(ratings[0]), userRatingsCursor(ratings[1])
+	assert.Equal(t, []model.Rating{
+		{UserID: "user0", MovieID: "1", RecordType: model.RecordTypeMovie, Value: 4},
+		{UserID: "user0", MovieID: "3", RecordType: "show", Value: 4},
+	}, withoutTimes(t, ratings))
+
+	ratings, err = r.ListUserRatings(ctx, "user0", "show", nil, 10)
+	assert.NoError(t, err)
+	assert.Len(t, ratings, 1)
+	ratings, err = r.ListUserRatings(ctx, "user0", "", &first, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RecordID("3"), ratings[0].MovieID)
+	ratings, err = r.ListUserRatings(ctx, "user0", "", &second, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, ratings)
+}
+
+func TestPutKeepsCreatedAt(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	first := &model.Rating{UserID: "user0", Value: 5}
+	_, err := r.Put(ctx, "1", model.RecordTypeMovie, first)
+	assert.NoError(t, err)
+	second := &model.Rating{UserID: "user0", Value: 3}
+	_, err = r.Put(ctx, "1", model.RecordTypeMovie, second)
+	assert.NoError(t, err)
+	assert.Equal(t, first.CreatedAt, second.CreatedAt)
+	assert.False(t, second.UpdatedAt.Before(first.UpdatedAt))
+}
+
+func TestAggregateFollowsWrites(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+
+	for _, rating := range []model.Rating{{UserID: "user0", Value: 5}, {UserID: "user1", Value: 3}, {UserID: "user0", Value: 3}, {UserID: "user2", Value: 1}} {
+		_, err := r.Put(ctx, "1", model.RecordTypeMovie, &rating)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, r.DeleteRating(ctx, "1", model.RecordTypeMovie, "user2"))
+
+	histogram, err := r.GetHistogram(ctx, "1", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Equal(t, map[model.RatingValue]int64{3: 2}, histogram)
+	agg := r.aggregates[model.RecordTypeMovie]["1"]
+	assert.Equal(t, int64(2), agg.count)
+	assert.Equal(t, int64(6), agg.sum)
+
+	histogram, err = r.GetHistogram(ctx, "2", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Empty(t, histogram)
+
+	histograms, err := r.GetHistograms(ctx, []model.RecordID{"1", "2"}, model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Equal(t, map[model.RecordID]map[model.RatingValue]int64{"1": {3: 2}}, histograms)
+}
+
+func TestLeaderboards(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	put := func(recordID model.RecordID, recordType model.RecordType, userID model.UserID, v model.RatingValue) {
+		t.Helper()
+		_, err := r.Put(ctx, recordID, recordType, &model.Rating{UserID: userID, Value: v})
+		assert.NoError(t, err)
+	}
+	put("1", model.RecordTypeMovie, "user0", 5)
+	put("2", model.RecordTypeMovie, "user0", 4)
+	put("2", model.RecordTypeMovie, "user1", 5)
+	put("3", model.RecordTypeMovie, "user0", 5)
+	put("3", model.RecordTypeMovie, "user1", 4)
+	put("4", model.RecordTypeMovie, "user0", 2)
+	put("4", model.RecordTypeMovie, "user1", 3)
+	put("4", model.RecordTypeMovie, "user2", 1)
+	put("5", "show", "user0", 5)
+
+	entries, err := r.ListTopRated(ctx, model.LeaderboardQuery{RecordType: model.RecordTypeMovie, Limit: 10}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.LeaderboardEntry{
+		{Rank: 1, RecordID: "1", RecordType: model.RecordTypeMovie, Count: 1, Mean: 5},
+		{Rank: 2, RecordID: "2", RecordType: model.RecordTypeMovie, Count: 2, Mean: 4.5},
+		{Rank: 2, RecordID: "3", RecordType: model.RecordTypeMovie, Count: 2, Mean: 4.5},
+		{Rank: 4, RecordID: "4", RecordType: model.RecordTypeMovie, Count: 3, Mean: 2},
+	}, entries)
+
+	// the index follows replaced and deleted ratings
+	put("4", model.RecordTypeMovie, "user2", 5)
+	assert.NoError(t, r.DeleteRating(ctx, "3", model.RecordTypeMovie, "user0"))
+	entries, err = r.ListTopRated(ctx, model.LeaderboardQuery{MinVotes: 2, Limit: 2}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.LeaderboardEntry{
+		{Rank: 1, RecordID: "2", RecordType: model.RecordTypeMovie, Count: 2, Mean: 4.5},
+		{Rank: 2, RecordID: "4", RecordType: model.RecordTypeMovie, Count: 3, Mean: 10.0 / 3},
+	}, entries)
+
+	// ratings older than the window do not count
+	r.data[model.RecordTypeMovie]["4"][0].UpdatedAt = now.Add(-48 * time.Hour)
+	entries, err = r.ListTrending(ctx, model.LeaderboardQuery{RecordType: model.RecordTypeMovie, Window: 24 * time.Hour, Limit: 10}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.LeaderboardEntry{
+		{Rank: 1, RecordID: "2", RecordType: model.RecordTypeMovie, Count: 2, Mean: 4.5},
+		{Rank: 2, RecordID: "4", RecordType: model.RecordTypeMovie, Count: 2, Mean: 4},
+		{Rank: 3, RecordID: "1", RecordType: model.RecordTypeMovie, Count: 1, Mean: 5},
+		{Rank: 4, RecordID: "3", RecordType: model.RecordTypeMovie, Count: 1, Mean: 4},
+	}, entries)
+	entries, err = r.ListTopRated(ctx, model.LeaderboardQuery{RecordType: model.RecordTypeMovie, MinVotes: 2, Window: 24 * time.Hour, Limit: 10}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.LeaderboardEntry{
+		{Rank: 1, RecordID: "2", RecordType: model.RecordTypeMovie, Count: 2, Mean: 4.5},
+		{Rank: 2, RecordID: "4", RecordType: model.RecordTypeMovie, Count: 2, Mean: 4},
+	}, entries)
+}
+
+func TestReviews(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	assert.ErrorIs(t, r.PutReview(ctx, "1", model.RecordTypeMovie, "user0", &model.Review{Text: "Great"}), repository.ErrNotFound)
+	for _, userID := range []model.UserID{"user0", "user1", "user2", "user3"} {
+		_, err := r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: userID, Value: 4})
+		assert.NoError(t, err)
+	}
+	for _, userID := range []model.UserID{"user0", "user1", "user2"} {
+		assert.NoError(t, r.PutReview(ctx, "1", model.RecordTypeMovie, userID, &model.Review{Text: "Review by " + string(userID), State: model.ReviewApproved}))
+	}
+	// order the reviews by time regardless of the clock resolution
+	base := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+	for i, userID := range []model.UserID{"user0", "user1", "user2"} {
+		r.reviews[reviewKey{recordKey{model.RecordTypeMovie, "1"}, userID}].ReviewedAt = base.Add(time.Duration(i) * time.Hour)
+	}
+	assert.ErrorIs(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user3", "user0", true), repository.ErrNotFound)
+	assert.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user1", true))
+	assert.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user2", false))
+	assert.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user2", true))
+	assert.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user1", "user0", false))
+
+	authors := func(ratings []model.Rating) []model.UserID {
+		var res []model.UserID
+		for _, rating := range ratings {
+			res = append(res, rating.UserID)
+		}
+		return res
+	}
+	newest, err := r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortNewest, nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserID{"user2", "user1", "user0"}, authors(newest))
+	helpful, err := r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortHelpful, nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserID{"user0", "user2", "user1"}, authors(helpful))
+	assert.Equal(t, int64(2), helpful[0].Review.HelpfulVotes)
+	assert.Equal(t, int64(0), helpful[0].Review.UnhelpfulVotes)
+	assert.Equal(t, int64(1), helpful[2].Review.UnhelpfulVotes)
+	cursor := reviewsCursor(helpful[0])
+	page, err := r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortHelpful, &cursor, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserID{"user2"}, authors(page))
+	// reviews written at the same time are ordered by author, descending, and paged past exactly
+	r.reviews[reviewKey{recordKey{model.RecordTypeMovie, "1"}, "user1"}].ReviewedAt = base
+	cursor = model.ReviewsCursor{ReviewedAt: base, UserID: "user1"}
+	page, err = r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortNewest, &cursor, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserID{"user0"}, authors(page))
+
+	// rewriting a review keeps its votes and deleting the rating deletes both
+	assert.NoError(t, r.PutReview(ctx, "1", model.RecordTypeMovie, "user0", &model.Review{Text: "Still great", Spoiler: true, State: model.ReviewApproved}))
+	helpful, err = r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortHelpful, nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Still great", helpful[0].Review.Text)
+	assert.True(t, helpful[0].Review.Spoiler)
+	assert.Equal(t, int64(2), helpful[0].Review.HelpfulVotes)
+	assert.NoError(t, r.DeleteRating(ctx, "1", model.RecordTypeMovie, "user0"))
+	_, err = r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user0", Value: 2})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user1", true), repository.ErrNotFound)
+	newest, err = r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortNewest, nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserID{"user2", "user1"}, authors(newest))
+}
+
+func TestModerateReview(t *testing.T) {
+	r := New()
+	ctx := context.Background()
+	assert.ErrorIs(t, r.ModerateReview(ctx, "1", model.RecordTypeMovie, "user0", model.ReviewApproved, ""), repository.ErrNotFound)
+	_, err := r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user0", Value: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, r.PutReview(ctx, "1", model.RecordTypeMovie, "user0", &model.Review{Text: "Short", State: model.ReviewPending, ModerationReason: "too short"}))
+	assert.ErrorIs(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user1", true), repository.ErrNotFound)
+	approved, err := r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortNewest, nil, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, approved)
+	pending, err := r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewPending, model.ReviewSortNewest, nil, 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "too short", pending[0].Review.ModerationReason)
+
+	assert.NoError(t, r.ModerateReview(ctx, "1", model.RecordTypeMovie, "user0", model.ReviewApproved, "fine"))
+	approved, err = r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortNewest, nil, 10)
+	assert.NoError(t, err)
+	assert.Len(t, approved, 1)
+	assert.Equal(t, "fine", approved[0].Review.ModerationReason)
+	assert.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user1", true))
+}
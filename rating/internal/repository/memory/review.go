@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"movieexample.com/rating/internal/repository"
+	"movieexample.com/rating/pkg/model"
+)
+
+// reviewKey identifies the review a user wrote of a record.
+type reviewKey struct {
+	recordKey
+	userID model.UserID
+}
+
+// PutReview sets the review on the rating the user gave the specified record, in the
+// moderation state of the review, and sets its time. If the user has not rated it, it
+// returns an ErrNotFound error.
+func (r *Repository) PutReview(_ context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, review *model.Review) error {
+	r.Lock()
+	defer r.Unlock()
+	if !slices.ContainsFunc(r.data[recordType][recordID], func(rating model.Rating) bool { return rating.UserID == userID }) {
+		return repository.ErrNotFound
+	}
+	review.ReviewedAt = time.Now().UTC()
+	stored := *review
+	stored.HelpfulVotes, stored.UnhelpfulVotes = 0, 0
+	r.reviews[reviewKey{recordKey{recordType, recordID}, userID}] = &stored
+	return nil
+}
+
+// ListReviews returns a page of the ratings of the specified record that have a
+// review in the given state after the cursor, in the given order, counting the votes on each review.
+func (r *Repository) ListReviews(_ context.Context, recordID model.RecordID, recordType model.RecordType, state model.ReviewState, sort model.ReviewSort, after *model.ReviewsCursor, limit int) ([]model.Rating, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var res []model.Rating
+	for _, rating := range r.data[recordType][recordID] {
+		key := reviewKey{recordKey{recordType, recordID}, rating.UserID}
+		stored, ok := r.reviews[key]
+		if !ok || stored.State != state {
+			continue
+		}
+		review := *stored
+		for _, helpful := range r.votes[key] {
+			if helpful {
+				review.HelpfulVotes++
+			} else {
+				review.UnhelpfulVotes++
+			}
+		}
+		rating.MovieID, rating.Review = recordID, &review
+		if after != nil && compareReviews(sort, reviewsCursor(rating), *after) <= 0 {
+			continue
+		}
+		res = append(res, rating)
+	}
+	slices.SortFunc(res, func(a, b model.Rating) int {
+		return compareReviews(sort, reviewsCursor(a), reviewsCursor(b))
+	})
+	return res[:min(limit, len(res))], nil
+}
+
+func reviewsCursor(rating model.Rating) model.ReviewsCursor {
+	return model.ReviewsCursor{
+		HelpfulVotes:   rating.Review.HelpfulVotes,
+		UnhelpfulVotes: rating.Review.UnhelpfulVotes,
+		ReviewedAt:     rating.Review.ReviewedAt,
+		UserID:         rating.UserID,
+	}
+}
+
+// compareReviews compares the positions of two reviews in the listing in the given order,
+// negative when a comes first.
+func compareReviews(sort model.ReviewSort, a, b model.ReviewsCursor) int {
+	if sort == model.ReviewSortHelpful {
+		if c := cmp.Or(
+			cmp.Compare(b.HelpfulVotes, a.HelpfulVotes),
+			cmp.Compare(a.UnhelpfulVotes, b.UnhelpfulVotes),
+		); c != 0 {
+			return c
+		}
+	}
+	return cmp.Or(
+		b.ReviewedAt.Compare(a.ReviewedAt),
+		cmp.Compare(b.UserID, a.UserID),
+	)
+}
+
+// VoteReview records whether the voter found the review the author wrote of the
+// specified record helpful, replacing their previous vote. If there is no such
+// approved review, it returns an ErrNotFound error.
+func (r *Repository) VoteReview(_ context.Context, recordID model.RecordID, recordType model.RecordType, authorID, voterID model.UserID, helpful bool) error {
+	r.Lock()
+	defer r.Unlock()
+	key := reviewKey{recordKey{recordType, recordID}, authorID}
+	if review, ok := r.reviews[key]; !ok || review.State != model.ReviewApproved {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.votes[key]; !ok {
+		r.votes[key] = map[model.UserID]bool{}
+	}
+	r.votes[key][voterID] = helpful
+	return nil
+}
+
+// ModerateReview sets the moderation state of the review the user wrote of the
+// specified record. If there is no such review, it returns an ErrNotFound error.
+func (r *Repository) ModerateReview(_ context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, state model.ReviewState, reason string) error {
+	r.Lock()
+	defer r.Unlock()
+	key := reviewKey{recordKey{recordType, recordID}, userID}
+	review, ok := r.reviews[key]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	moderated := *review
+	moderated.State, moderated.ModerationReason = state, reason
+	r.reviews[key] = &moderated
+	return nil
+}
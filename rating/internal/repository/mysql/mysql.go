@@ -3,6 +3,9 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
+	"time"
 
 	// Import the MySQL driver
 	_ "github.com/go-sql-driver/mysql"
@@ -15,9 +18,10 @@ type Repository struct {
 	db *sql.DB
 }
 
-// New creates a new MySQL-based rating repository.
+// New creates a new MySQL-based rating repository. The database is expected to have the tables
+// of schema.sql, whose keys the upserts of Put and VoteReview rely on.
 func New() (*Repository, error) {
-	db, err := sql.Open("mysql", "root:password@/movieexample")
+	db, err := sql.Open("mysql", "root:password@/movieexample?parseTime=true")
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +30,7 @@ func New() (*Repository, error) {
 
 // Get retrieves all ratings for a given record.
 func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT user_id, value FROM rating WHERE record_id = ? AND record_type = ?", recordID, recordType)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, value, created_at, updated_at FROM rating WHERE record_id = ? AND record_type = ?", recordID, recordType)
 	if err != nil {
 		return nil, err
 	}
@@ -35,12 +39,15 @@ func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordTyp
 	for rows.Next() {
 		var userID string
 		var value int32
-		if err := rows.Scan(&userID, &value); err != nil {
+		var createdAt, updatedAt time.Time
+		if err := rows.Scan(&userID, &value, &createdAt, &updatedAt); err != nil {
 			return nil, err
 		}
 		res = append(res, model.Rating{
-			UserID: model.UserID(userID),
-			Value:  model.RatingValue(value),
+			UserID:    model.UserID(userID),
+			Value:     model.RatingValue(value),
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
 		})
 	}
 	if len(res) == 0 {
@@ -49,9 +56,319 @@ func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordTyp
 	return res, nil
 }
 
-// Put adds a rating for a given record.
-func (r *Repository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
-	_, err := r.db.ExecContext(ctx, "INSERT INTO rating (record_id, record_type, user_id, value) VALUES (?, ?, ?, ?)",
-		recordID, recordType, rating.UserID, rating.Value)
+// Put adds or replaces the rating the user gave the record, sets its timestamps and reports
+// whether it was added. It relies on a unique key on (record_id, record_type, user_id).
+func (r *Repository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) (bool, error) {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(ctx, "INSERT INTO rating (record_id, record_type, user_id, value, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE value = VALUES(value), updated_at = VALUES(updated_at)",
+		recordID, recordType, rating.UserID, rating.Value, now, now)
+	if err != nil {
+		return false, err
+	}
+	// MySQL counts an inserted row as 1, an updated one as 2 and an unchanged one as 0
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	rating.CreatedAt, rating.UpdatedAt = now, now
+	if n != 1 {
+		err := r.db.QueryRowContext(ctx, "SELECT created_at FROM rating WHERE record_id = ? AND record_type = ? AND user_id = ?", recordID, recordType, rating.UserID).Scan(&rating.CreatedAt)
+		if err != nil {
+			return false, err
+		}
+	}
+	return n == 1, nil
+}
+
+// GetUserRating retrieves the rating a user gave a record.
+func (r *Repository) GetUserRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) (*model.Rating, error) {
+	var value int32
+	var createdAt, updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, "SELECT value, created_at, updated_at FROM rating WHERE record_id = ? AND record_type = ? AND user_id = ?", recordID, recordType, userID).Scan(&value, &createdAt, &updatedAt)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &model.Rating{UserID: userID, MovieID: recordID, Value: model.RatingValue(value), CreatedAt: createdAt, UpdatedAt: updatedAt}, nil
+}
+
+// DeleteRating removes the rating a user gave a record.
+func (r *Repository) DeleteRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM rating WHERE record_id = ? AND record_type = ? AND user_id = ?", recordID, recordType, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetHistogram counts the ratings of a record by value with a GROUP BY.
+func (r *Repository) GetHistogram(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT value, COUNT(*) FROM rating WHERE record_id = ? AND record_type = ? AND value IS NOT NULL GROUP BY value", recordID, recordType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	histogram := map[model.RatingValue]int64{}
+	for rows.Next() {
+		var value int32
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		histogram[model.RatingValue(value)] = count
+	}
+	return histogram, rows.Err()
+}
+
+// GetHistograms counts the ratings of the records by value with a single GROUP BY.
+func (r *Repository) GetHistograms(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]map[model.RatingValue]int64, error) {
+	histograms := map[model.RecordID]map[model.RatingValue]int64{}
+	if len(recordIDs) == 0 {
+		return histograms, nil
+	}
+	args := []any{recordType}
+	for _, id := range recordIDs {
+		args = append(args, id)
+	}
+	query := "SELECT record_id, value, COUNT(*) FROM rating WHERE record_type = ? AND record_id IN (?" +
+		strings.Repeat(", ?", len(recordIDs)-1) + ") AND value IS NOT NULL GROUP BY record_id, value"
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var value int32
+		var count int64
+		if err := rows.Scan(&id, &value, &count); err != nil {
+			return nil, err
+		}
+		if histograms[model.RecordID(id)] == nil {
+			histograms[model.RecordID(id)] = map[model.RatingValue]int64{}
+		}
+		histograms[model.RecordID(id)][model.RatingValue(value)] = count
+	}
+	return histograms, rows.Err()
+}
+
+// GetRecordTypeHistogram counts the ratings of all records of a type by value.
+func (r *Repository) GetRecordTypeHistogram(ctx context.Context, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT value, COUNT(*) FROM rating WHERE record_type = ? AND value IS NOT NULL GROUP BY value", recordType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	histogram := map[model.RatingValue]int64{}
+	for rows.Next() {
+		var value int32
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		histogram[model.RatingValue(value)] = count
+	}
+	return histogram, rows.Err()
+}
+
+// GetDecayedMean returns the mean rating of a record with every rating weighed by 0.5 to the
+// power of its age at now in half-lives, or 0 if there are no ratings.
+func (r *Repository) GetDecayedMean(ctx context.Context, recordID model.RecordID, recordType model.RecordType, halfLife time.Duration, now time.Time) (float64, error) {
+	const query = `SELECT COALESCE(SUM(value * w) / NULLIF(SUM(w), 0), 0) FROM (
+		SELECT value, POW(0.5, TIMESTAMPDIFF(SECOND, updated_at, ?) / ?) AS w
+		FROM rating WHERE record_id = ? AND record_type = ? AND value IS NOT NULL) AS weighted`
+	var mean float64
+	err := r.db.QueryRowContext(ctx, query, now.UTC(), halfLife.Seconds(), recordID, recordType).Scan(&mean)
+	return mean, err
+}
+
+// ListTopRated ranks records by the mean of their ratings in the time window with a window query.
+func (r *Repository) ListTopRated(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	return r.leaderboard(ctx, query, now, "mean DESC, ratings DESC")
+}
+
+// ListTrending ranks records by their number of ratings in the time window with a window query.
+func (r *Repository) ListTrending(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	return r.leaderboard(ctx, query, now, "ratings DESC, mean DESC")
+}
+
+// leaderboard ranks the records with ratings in the window of the query in the given order. A zero
+// window counts all ratings.
+func (r *Repository) leaderboard(ctx context.Context, query model.LeaderboardQuery, now time.Time, order string) ([]model.LeaderboardEntry, error) {
+	var since time.Time
+	if query.Window > 0 {
+		since = now.Add(-query.Window)
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT record_id, record_type, ratings, mean, RANK() OVER (ORDER BY `+order+`) AS ranking FROM (
+		SELECT record_id, record_type, COUNT(*) AS ratings, AVG(value) AS mean
+		FROM rating
+		WHERE value IS NOT NULL AND updated_at >= ? AND (? = '' OR record_type = ?)
+		GROUP BY record_id, record_type
+		HAVING COUNT(*) >= ?) AS totals
+		ORDER BY ranking, record_type, record_id
+		LIMIT ?`,
+		since.UTC(), query.RecordType, query.RecordType, query.MinVotes, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []model.LeaderboardEntry
+	for rows.Next() {
+		var e model.LeaderboardEntry
+		if err := rows.Scan(&e.RecordID, &e.RecordType, &e.Count, &e.Mean, &e.Rank); err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
+
+// ListUserRatings returns a page of the ratings of a user after the cursor, most recently changed
+// first. It relies on an index on (user_id, updated_at).
+func (r *Repository) ListUserRatings(ctx context.Context, userID model.UserID, recordType model.RecordType, after *model.UserRatingsCursor, limit int) ([]model.Rating, error) {
+	query := `SELECT record_id, record_type, value, created_at, updated_at FROM rating
+		WHERE user_id = ? AND value IS NOT NULL AND (? = '' OR record_type = ?)`
+	args := []any{userID, recordType, recordType}
+	if after != nil {
+		query += " AND (updated_at, record_type, record_id) < (?, ?, ?)"
+		args = append(args, after.UpdatedAt, after.RecordType, after.RecordID)
+	}
+	query += " ORDER BY updated_at DESC, record_type DESC, record_id DESC LIMIT ?"
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []model.Rating
+	for rows.Next() {
+		rating := model.Rating{UserID: userID}
+		if err := rows.Scan(&rating.MovieID, &rating.RecordType, &rating.Value, &rating.CreatedAt, &rating.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, rating)
+	}
+	return res, rows.Err()
+}
+
+// PutReview sets the review on the rating a user gave a record, in the moderation state of the
+// review, or returns repository.ErrNotFound if the user has not rated it.
+func (r *Repository) PutReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, review *model.Review) error {
+	now := time.Now().UTC()
+	title := sql.NullString{String: review.Title, Valid: review.Title != ""}
+	reason := sql.NullString{String: review.ModerationReason, Valid: review.ModerationReason != ""}
+	res, err := r.db.ExecContext(ctx, "UPDATE rating SET review_title = ?, review_text = ?, review_spoiler = ?, review_state = ?, moderation_reason = ?, reviewed_at = ? WHERE record_id = ? AND record_type = ? AND user_id = ?",
+		title, review.Text, review.Spoiler, review.State, reason, now, recordID, recordType, userID)
+	if err != nil {
+		return err
+	}
+	// MySQL counts a row rewritten with the same values as unaffected, so tell those apart
+	// from a missing rating
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if _, err := r.GetUserRating(ctx, recordID, recordType, userID); err != nil {
+			return err
+		}
+	}
+	review.ReviewedAt = now
+	return nil
+}
+
+// ListReviews returns a page of the ratings of a record that have a review in the given state
+// after the cursor, in the given order, counting the votes on each review with correlated
+// subqueries so that no column of rating has to be grouped.
+func (r *Repository) ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, state model.ReviewState, sort model.ReviewSort, after *model.ReviewsCursor, limit int) ([]model.Rating, error) {
+	query := `SELECT user_id, value, review_title, review_text, review_spoiler, review_state, moderation_reason, created_at, updated_at, reviewed_at, helpful_votes, unhelpful_votes
+		FROM (SELECT r.user_id, r.value, r.review_title, r.review_text, r.review_spoiler, r.review_state, r.moderation_reason, r.created_at, r.updated_at, r.reviewed_at,
+			(SELECT COUNT(*) FROM review_vote v WHERE v.record_id = r.record_id AND v.record_type = r.record_type AND v.author_id = r.user_id AND v.helpful) AS helpful_votes,
+			(SELECT COUNT(*) FROM review_vote v WHERE v.record_id = r.record_id AND v.record_type = r.record_type AND v.author_id = r.user_id AND NOT v.helpful) AS unhelpful_votes
+			FROM rating r
+			WHERE r.record_id = ? AND r.record_type = ? AND r.review_text IS NOT NULL AND r.review_state = ?) reviews`
+	args := []any{recordID, recordType, state}
+	order := " ORDER BY reviewed_at DESC, user_id DESC"
+	if sort == model.ReviewSortHelpful {
+		if after != nil {
+			query += " WHERE (helpful_votes, -unhelpful_votes, reviewed_at, user_id) < (?, ?, ?, ?)"
+			args = append(args, after.HelpfulVotes, -after.UnhelpfulVotes, after.ReviewedAt, after.UserID)
+		}
+		order = " ORDER BY helpful_votes DESC, unhelpful_votes, reviewed_at DESC, user_id DESC"
+	} else if after != nil {
+		query += " WHERE (reviewed_at, user_id) < (?, ?)"
+		args = append(args, after.ReviewedAt, after.UserID)
+	}
+	rows, err := r.db.QueryContext(ctx, query+order+" LIMIT ?", append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []model.Rating
+	for rows.Next() {
+		rating := model.Rating{MovieID: recordID, Review: &model.Review{}}
+		var title, reason sql.NullString
+		if err := rows.Scan(&rating.UserID, &rating.Value, &title, &rating.Review.Text, &rating.Review.Spoiler, &rating.Review.State, &reason,
+			&rating.CreatedAt, &rating.UpdatedAt, &rating.Review.ReviewedAt, &rating.Review.HelpfulVotes, &rating.Review.UnhelpfulVotes); err != nil {
+			return nil, err
+		}
+		rating.Review.Title, rating.Review.ModerationReason = title.String, reason.String
+		res = append(res, rating)
+	}
+	return res, rows.Err()
+}
+
+// VoteReview records whether a voter found the review an author wrote of a record helpful,
+// replacing their previous vote, or returns repository.ErrNotFound if there is no such approved
+// review. It relies on a unique key on (record_id, record_type, author_id, voter_id).
+func (r *Repository) VoteReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, authorID, voterID model.UserID, helpful bool) error {
+	var reviewed bool
+	err := r.db.QueryRowContext(ctx, "SELECT review_text IS NOT NULL AND review_state = ? FROM rating WHERE record_id = ? AND record_type = ? AND user_id = ?",
+		model.ReviewApproved, recordID, recordType, authorID).Scan(&reviewed)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	if !reviewed {
+		return repository.ErrNotFound
+	}
+	_, err = r.db.ExecContext(ctx, "INSERT INTO review_vote (record_id, record_type, author_id, voter_id, helpful) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE helpful = VALUES(helpful)",
+		recordID, recordType, authorID, voterID, helpful)
 	return err
 }
+
+// ModerateReview sets the moderation state of the review a user wrote of a record, or returns
+// repository.ErrNotFound if there is no such review.
+func (r *Repository) ModerateReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, state model.ReviewState, reason string) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE rating SET review_state = ?, moderation_reason = ? WHERE record_id = ? AND record_type = ? AND user_id = ? AND review_text IS NOT NULL",
+		state, sql.NullString{String: reason, Valid: reason != ""}, recordID, recordType, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// tell a review already in the state apart from a missing one, as for PutReview
+		var reviewed bool
+		err := r.db.QueryRowContext(ctx, "SELECT review_text IS NOT NULL FROM rating WHERE record_id = ? AND record_type = ? AND user_id = ?", recordID, recordType, userID).Scan(&reviewed)
+		if err != nil && errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+		if !reviewed {
+			return repository.ErrNotFound
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+-- Schema of the MySQL rating repository, for MySQL 8.0.16 or later. The PostgreSQL repository is
+-- migrated with the goose migrations in schema/ instead.
+CREATE TABLE
+    IF NOT EXISTS rating (
+        record_id VARCHAR(255) NOT NULL,
+        record_type VARCHAR(255) NOT NULL,
+        user_id VARCHAR(255) NOT NULL,
+        value INT,
+        created_at DATETIME(6) NOT NULL,
+        updated_at DATETIME(6) NOT NULL,
+        review_title TEXT,
+        review_text TEXT,
+        review_spoiler BOOLEAN NOT NULL DEFAULT FALSE,
+        review_state VARCHAR(16),
+        moderation_reason TEXT,
+        reviewed_at DATETIME(6),
+        -- Put upserts on this key with ON DUPLICATE KEY UPDATE
+        UNIQUE KEY rating_record_user_key (record_id, record_type, user_id),
+        KEY rating_user_updated_idx (user_id, updated_at),
+        KEY rating_updated_idx (updated_at),
+        KEY rating_review_state_idx (record_id, record_type, review_state, reviewed_at),
+        CHECK (review_state IN ('pending', 'approved', 'rejected'))
+    );
+
+CREATE TABLE
+    IF NOT EXISTS review_vote (
+        record_id VARCHAR(255) NOT NULL,
+        record_type VARCHAR(255) NOT NULL,
+        author_id VARCHAR(255) NOT NULL,
+        voter_id VARCHAR(255) NOT NULL,
+        helpful BOOLEAN NOT NULL,
+        -- VoteReview replaces the vote of a voter on this key with ON DUPLICATE KEY UPDATE
+        PRIMARY KEY (record_id, record_type, author_id, voter_id),
+        -- deleting a rating deletes its review and the votes on it
+        FOREIGN KEY (record_id, record_type, author_id) REFERENCES rating (record_id, record_type, user_id) ON DELETE CASCADE,
+        CHECK (author_id <> voter_id)
+    );
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/rating/pkg/model"
+)
+
+// lockUserRating locks the aggregate of the record for the rest of the transaction and returns
+// the current value of the rating of the user, which is not valid if there is none. Every write to
+// the ratings of a record takes the lock first, so the value cannot change before the transaction ends.
+func lockUserRating(ctx context.Context, q *dbGen.Queries, recordID model.RecordID, recordType model.RecordType, userID model.UserID) (pgtype.Int4, error) {
+	err := q.LockRatingAggregate(ctx, dbGen.LockRatingAggregateParams{
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+	})
+	if err != nil {
+		return pgtype.Int4{}, err
+	}
+	row, err := q.GetUserRating(ctx, dbGen.GetUserRatingParams{
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+		UserID:     string(userID),
+	})
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.Int4{}, nil
+	} else if err != nil {
+		return pgtype.Int4{}, err
+	}
+
+	return row.Value, nil
+}
+
+// updateAggregate applies the change of a rating from old to value to the aggregate of the record.
+// An invalid old means the rating was added and an invalid value that it was deleted.
+func updateAggregate(ctx context.Context, q *dbGen.Queries, recordID model.RecordID, recordType model.RecordType, old, value pgtype.Int4) error {
+	if old == value {
+		return nil
+	}
+
+	var count, sum int64
+	for _, change := range []struct {
+		value pgtype.Int4
+		delta int64
+	}{{old, -1}, {value, 1}} {
+		if !change.value.Valid {
+			continue
+		}
+		count += change.delta
+		sum += change.delta * int64(change.value.Int32)
+		err := q.AddToRatingHistogram(ctx, dbGen.AddToRatingHistogramParams{
+			RecordID:   string(recordID),
+			RecordType: string(recordType),
+			Value:      change.value.Int32,
+			Ratings:    change.delta,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return q.AddToRatingAggregate(ctx, dbGen.AddToRatingAggregateParams{
+		CountDelta: count,
+		SumDelta:   sum,
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+	})
+}
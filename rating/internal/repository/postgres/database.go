@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib" // Import the PostgreSQL driver
@@ -11,6 +14,7 @@ import (
 
 	config "movieexample.com/rating/configs"
 	"movieexample.com/rating/internal/controller/rating"
+	"movieexample.com/rating/internal/repository"
 	"movieexample.com/rating/pkg/model"
 )
 
@@ -56,20 +60,45 @@ func ConnectSQL(ctx context.Context, config *config.Config) (rating.Repository,
 	return &repo{db: db, q: *dbGen.New(db)}, nil
 }
 
-func (r *repo) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
-	_, err := r.q.InsertRating(ctx, dbGen.InsertRatingParams{
-		RecordID:   pgtype.Text{String: string(recordID), Valid: true},
-		RecordType: pgtype.Text{String: string(recordType), Valid: true},
-		UserID:     pgtype.Text{String: string(rating.UserID), Valid: true},
-		Value:      pgtype.Int4{Int32: int32(rating.Value), Valid: true},
+// Put adds or replaces the rating the user gave the record, sets its timestamps and reports
+// whether it was added. The aggregate of the record is updated in the same transaction.
+func (r *repo) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op once committed
+
+	q := r.q.WithTx(tx)
+	old, err := lockUserRating(ctx, q, recordID, recordType, rating.UserID)
+	if err != nil {
+		return false, err
+	}
+	value := pgtype.Int4{Int32: int32(rating.Value), Valid: true}
+	row, err := q.UpsertRating(ctx, dbGen.UpsertRatingParams{
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+		UserID:     string(rating.UserID),
+		Value:      value,
 	})
-	return err
+	if err != nil {
+		return false, err
+	}
+	if err := updateAggregate(ctx, q, recordID, recordType, old, value); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	rating.CreatedAt, rating.UpdatedAt = row.CreatedAt.Time, row.UpdatedAt.Time
+
+	return row.Created, nil
 }
 
 func (r *repo) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
 	data, err := r.q.GetRatings(ctx, dbGen.GetRatingsParams{
-		RecordID:   pgtype.Text{String: string(recordID), Valid: true},
-		RecordType: pgtype.Text{String: string(recordType), Valid: true},
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
 	})
 	if err != nil {
 		return nil, err
@@ -77,10 +106,169 @@ func (r *repo) Get(ctx context.Context, recordID model.RecordID, recordType mode
 	var ratings []model.Rating
 	for _, d := range data {
 		ratings = append(ratings, model.Rating{
-			UserID: model.UserID(d.UserID.String),
-			Value:  model.RatingValue(d.Value.Int32),
+			UserID:    model.UserID(d.UserID),
+			Value:     model.RatingValue(d.Value.Int32),
+			CreatedAt: d.CreatedAt.Time,
+			UpdatedAt: d.UpdatedAt.Time,
 		})
 	}
 
 	return ratings, nil
 }
+
+// GetUserRating returns the rating the user gave the record, or repository.ErrNotFound.
+func (r *repo) GetUserRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) (*model.Rating, error) {
+	row, err := r.q.GetUserRating(ctx, dbGen.GetUserRatingParams{
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+		UserID:     string(userID),
+	})
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &model.Rating{
+		UserID:    userID,
+		MovieID:   recordID,
+		Value:     model.RatingValue(row.Value.Int32),
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+	}, nil
+}
+
+// DeleteRating removes the rating the user gave the record, or returns repository.ErrNotFound.
+// The aggregate of the record is updated in the same transaction.
+func (r *repo) DeleteRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op once committed
+
+	q := r.q.WithTx(tx)
+	old, err := lockUserRating(ctx, q, recordID, recordType, userID)
+	if err != nil {
+		return err
+	}
+	n, err := q.DeleteRating(ctx, dbGen.DeleteRatingParams{
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+		UserID:     string(userID),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	if err := updateAggregate(ctx, q, recordID, recordType, old, pgtype.Int4{}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetHistogram returns the histogram of the record maintained by Put and DeleteRating, so that
+// aggregates do not need to read every rating.
+func (r *repo) GetHistogram(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	rows, err := r.q.GetRatingHistogram(ctx, dbGen.GetRatingHistogramParams{
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+	})
+	if err != nil {
+		return nil, err
+	}
+	histogram := make(map[model.RatingValue]int64, len(rows))
+	for _, row := range rows {
+		histogram[model.RatingValue(row.Value)] = row.Ratings
+	}
+
+	return histogram, nil
+}
+
+// GetHistograms returns the maintained histograms of the records of the type in one query.
+// Records that have not been rated are left out.
+func (r *repo) GetHistograms(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) (map[model.RecordID]map[model.RatingValue]int64, error) {
+	ids := make([]string, len(recordIDs))
+	for i, id := range recordIDs {
+		ids[i] = string(id)
+	}
+	rows, err := r.q.GetRatingHistograms(ctx, dbGen.GetRatingHistogramsParams{
+		RecordType: string(recordType),
+		RecordIds:  ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	histograms := map[model.RecordID]map[model.RatingValue]int64{}
+	for _, row := range rows {
+		id := model.RecordID(row.RecordID)
+		if histograms[id] == nil {
+			histograms[id] = map[model.RatingValue]int64{}
+		}
+		histograms[id][model.RatingValue(row.Value)] = row.Ratings
+	}
+
+	return histograms, nil
+}
+
+// GetRecordTypeHistogram adds up the histograms of all records of the type.
+func (r *repo) GetRecordTypeHistogram(ctx context.Context, recordType model.RecordType) (map[model.RatingValue]int64, error) {
+	rows, err := r.q.GetRecordTypeHistogram(ctx, string(recordType))
+	if err != nil {
+		return nil, err
+	}
+	histogram := make(map[model.RatingValue]int64, len(rows))
+	for _, row := range rows {
+		histogram[model.RatingValue(row.Value)] = row.Ratings
+	}
+
+	return histogram, nil
+}
+
+// GetDecayedMean returns the mean rating of the record with every rating weighed by 0.5 to the
+// power of its age at now in half-lives, or 0 if there are no ratings.
+func (r *repo) GetDecayedMean(ctx context.Context, recordID model.RecordID, recordType model.RecordType, halfLife time.Duration, now time.Time) (float64, error) {
+	return r.q.GetDecayedMean(ctx, dbGen.GetDecayedMeanParams{
+		Now:             pgtype.Timestamptz{Time: now, Valid: true},
+		HalfLifeSeconds: halfLife.Seconds(),
+		RecordID:        string(recordID),
+		RecordType:      string(recordType),
+	})
+}
+
+// ListUserRatings returns a page of the ratings of the user after the cursor, most recently changed
+// first, with a keyset condition rather than an offset. An empty record type lists ratings of all
+// types.
+func (r *repo) ListUserRatings(ctx context.Context, userID model.UserID, recordType model.RecordType, after *model.UserRatingsCursor, limit int) ([]model.Rating, error) {
+	params := dbGen.ListUserRatingsParams{
+		UserID:     string(userID),
+		RecordType: pgtype.Text{String: string(recordType), Valid: recordType != ""},
+		PageLimit:  int32(limit),
+	}
+	if after != nil {
+		params.AfterUpdatedAt = pgtype.Timestamptz{Time: after.UpdatedAt, Valid: true}
+		params.AfterRecordType = string(after.RecordType)
+		params.AfterRecordID = string(after.RecordID)
+	}
+	rows, err := r.q.ListUserRatings(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Rating, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, model.Rating{
+			UserID:     userID,
+			MovieID:    model.RecordID(row.RecordID),
+			RecordType: model.RecordType(row.RecordType),
+			Value:      model.RatingValue(row.Value.Int32),
+			CreatedAt:  row.CreatedAt.Time,
+			UpdatedAt:  row.UpdatedAt.Time,
+		})
+	}
+
+	return res, nil
+}
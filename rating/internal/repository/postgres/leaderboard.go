@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/rating/pkg/model"
+)
+
+// ListTopRated ranks records by their mean rating with a window query. Without a time window it
+// reads the aggregates maintained by Put and DeleteRating instead of the ratings.
+func (r *repo) ListTopRated(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	recordType := pgtype.Text{String: string(query.RecordType), Valid: query.RecordType != ""}
+	if query.Window == 0 {
+		rows, err := r.q.ListTopRatedAllTime(ctx, dbGen.ListTopRatedAllTimeParams{
+			MinVotes:   query.MinVotes,
+			RecordType: recordType,
+			MaxRecords: int32(query.Limit),
+		})
+		if err != nil {
+			return nil, err
+		}
+		res := make([]model.LeaderboardEntry, 0, len(rows))
+		for _, row := range rows {
+			res = append(res, leaderboardEntry(row.Rank, row.RecordID, row.RecordType, row.RatingsCount, row.Mean))
+		}
+
+		return res, nil
+	}
+
+	rows, err := r.q.ListTopRatedSince(ctx, dbGen.ListTopRatedSinceParams{
+		Since:      pgtype.Timestamptz{Time: now.Add(-query.Window), Valid: true},
+		MinVotes:   query.MinVotes,
+		RecordType: recordType,
+		MaxRecords: int32(query.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.LeaderboardEntry, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, leaderboardEntry(row.Rank, row.RecordID, row.RecordType, row.RatingsCount, row.Mean))
+	}
+
+	return res, nil
+}
+
+// ListTrending ranks records by their number of ratings in the time window with a window query.
+func (r *repo) ListTrending(ctx context.Context, query model.LeaderboardQuery, now time.Time) ([]model.LeaderboardEntry, error) {
+	rows, err := r.q.ListTrending(ctx, dbGen.ListTrendingParams{
+		Since:      pgtype.Timestamptz{Time: now.Add(-query.Window), Valid: true},
+		MinVotes:   query.MinVotes,
+		RecordType: pgtype.Text{String: string(query.RecordType), Valid: query.RecordType != ""},
+		MaxRecords: int32(query.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.LeaderboardEntry, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, leaderboardEntry(row.Rank, row.RecordID, row.RecordType, row.RatingsCount, row.Mean))
+	}
+
+	return res, nil
+}
+
+func leaderboardEntry(rank int64, recordID, recordType string, count int64, mean float64) model.LeaderboardEntry {
+	return model.LeaderboardEntry{
+		Rank:       rank,
+		RecordID:   model.RecordID(recordID),
+		RecordType: model.RecordType(recordType),
+		Count:      count,
+		Mean:       mean,
+	}
+}
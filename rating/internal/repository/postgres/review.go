@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	dbGen "movieexample.com/gen/db"
+	"movieexample.com/rating/internal/repository"
+	"movieexample.com/rating/pkg/model"
+)
+
+// PutReview sets the review on the rating the user gave the record, in the moderation state of the
+// review, and sets its time, or returns repository.ErrNotFound if the user has not rated the record.
+func (r *repo) PutReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, review *model.Review) error {
+	reviewedAt, err := r.q.PutReview(ctx, dbGen.PutReviewParams{
+		RecordID:         string(recordID),
+		RecordType:       string(recordType),
+		UserID:           string(userID),
+		ReviewTitle:      pgtype.Text{String: review.Title, Valid: review.Title != ""},
+		ReviewText:       pgtype.Text{String: review.Text, Valid: true},
+		ReviewSpoiler:    review.Spoiler,
+		ReviewState:      pgtype.Text{String: string(review.State), Valid: true},
+		ModerationReason: pgtype.Text{String: review.ModerationReason, Valid: review.ModerationReason != ""},
+	})
+	if err != nil && errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	review.ReviewedAt = reviewedAt.Time
+
+	return nil
+}
+
+// ListReviews returns a page of the ratings of the record that have a review in the given state
+// after the cursor, in the given order, counting the votes on each review. It pages with a keyset
+// condition rather than an offset.
+func (r *repo) ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, state model.ReviewState, sort model.ReviewSort, after *model.ReviewsCursor, limit int) ([]model.Rating, error) {
+	var rows []dbGen.ListReviewsNewestRow
+	var err error
+	if sort == model.ReviewSortHelpful {
+		params := dbGen.ListReviewsHelpfulParams{
+			RecordID:    string(recordID),
+			RecordType:  string(recordType),
+			ReviewState: pgtype.Text{String: string(state), Valid: true},
+			PageLimit:   int32(limit),
+		}
+		if after != nil {
+			params.AfterReviewedAt = pgtype.Timestamptz{Time: after.ReviewedAt, Valid: true}
+			params.AfterHelpfulVotes = after.HelpfulVotes
+			params.AfterUnhelpfulVotes = after.UnhelpfulVotes
+			params.AfterUserID = string(after.UserID)
+		}
+		var helpful []dbGen.ListReviewsHelpfulRow
+		helpful, err = r.q.ListReviewsHelpful(ctx, params)
+		for _, row := range helpful {
+			rows = append(rows, dbGen.ListReviewsNewestRow(row))
+		}
+	} else {
+		params := dbGen.ListReviewsNewestParams{
+			RecordID:    string(recordID),
+			RecordType:  string(recordType),
+			ReviewState: pgtype.Text{String: string(state), Valid: true},
+			PageLimit:   int32(limit),
+		}
+		if after != nil {
+			params.AfterReviewedAt = pgtype.Timestamptz{Time: after.ReviewedAt, Valid: true}
+			params.AfterUserID = string(after.UserID)
+		}
+		rows, err = r.q.ListReviewsNewest(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Rating, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, model.Rating{
+			UserID:    model.UserID(row.UserID),
+			MovieID:   recordID,
+			Value:     model.RatingValue(row.Value.Int32),
+			CreatedAt: row.CreatedAt.Time,
+			UpdatedAt: row.UpdatedAt.Time,
+			Review: &model.Review{
+				Title:            row.ReviewTitle.String,
+				Text:             row.ReviewText.String,
+				Spoiler:          row.ReviewSpoiler,
+				State:            model.ReviewState(row.ReviewState.String),
+				ModerationReason: row.ModerationReason.String,
+				ReviewedAt:       row.ReviewedAt.Time,
+				HelpfulVotes:     row.HelpfulVotes,
+				UnhelpfulVotes:   row.UnhelpfulVotes,
+			},
+		})
+	}
+
+	return res, nil
+}
+
+// VoteReview records whether the voter found the review the author wrote of the record helpful,
+// replacing their previous vote, or returns repository.ErrNotFound if there is no such approved review.
+func (r *repo) VoteReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, authorID, voterID model.UserID, helpful bool) error {
+	n, err := r.q.UpsertReviewVote(ctx, dbGen.UpsertReviewVoteParams{
+		VoterID:    string(voterID),
+		Helpful:    helpful,
+		RecordID:   string(recordID),
+		RecordType: string(recordType),
+		AuthorID:   string(authorID),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// ModerateReview sets the moderation state of the review the user wrote of the record, or returns
+// repository.ErrNotFound if there is no such review.
+func (r *repo) ModerateReview(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, state model.ReviewState, reason string) error {
+	n, err := r.q.ModerateReview(ctx, dbGen.ModerateReviewParams{
+		RecordID:         string(recordID),
+		RecordType:       string(recordType),
+		UserID:           string(userID),
+		ReviewState:      pgtype.Text{String: string(state), Valid: true},
+		ModerationReason: pgtype.Text{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
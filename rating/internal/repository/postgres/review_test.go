@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbGen "movieexample.com/gen/db"
+
+	"movieexample.com/rating/pkg/model"
+)
+
+// testRepo returns a repository on a fresh schema of the database in RATING_TEST_POSTGRES_DSN
+// with the migrations applied, and skips the test if it is not set.
+func testRepo(t *testing.T) *repo {
+	dsn := os.Getenv("RATING_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("RATING_TEST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	schema := fmt.Sprintf("rating_test_%d", time.Now().UnixNano())
+	admin, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(admin.Close)
+	_, err = admin.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE") //nolint:errcheck // best effort
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	db, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	migrations, err := filepath.Glob("../../../../schema/*.sql")
+	require.NoError(t, err)
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		b, err := os.ReadFile(migration)
+		require.NoError(t, err)
+		up, _, _ := strings.Cut(string(b), "-- +goose Down")
+		up = strings.NewReplacer("-- +goose StatementBegin", "", "-- +goose StatementEnd", "").Replace(up)
+		_, err = db.Exec(ctx, up)
+		require.NoError(t, err, migration)
+	}
+
+	return &repo{db: db, q: *dbGen.New(db)}
+}
+
+func TestListReviews(t *testing.T) {
+	r := testRepo(t)
+	ctx := context.Background()
+	base := time.Date(2025, 4, 25, 0, 0, 0, 0, time.UTC)
+	// user1 and user2 reviewed at the same time so that paging relies on the author tie-break
+	reviewedAt := map[model.UserID]time.Time{
+		"user0": base,
+		"user1": base.Add(time.Hour),
+		"user2": base.Add(time.Hour),
+		"user3": base.Add(2 * time.Hour),
+	}
+	for userID, at := range reviewedAt {
+		_, err := r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: userID, Value: 4})
+		require.NoError(t, err)
+		require.NoError(t, r.PutReview(ctx, "1", model.RecordTypeMovie, userID, &model.Review{Text: "Review by " + string(userID), State: model.ReviewApproved}))
+		_, err = r.db.Exec(ctx, "UPDATE ratings SET reviewed_at = $1 WHERE record_id = '1' AND user_id = $2", at, userID)
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user1", true))
+	require.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user0", "user2", true))
+	require.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user1", "user0", true))
+	require.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user1", "user2", false))
+	require.NoError(t, r.VoteReview(ctx, "1", model.RecordTypeMovie, "user2", "user0", true))
+
+	// pages of one review each must list every review once, in order
+	list := func(sort model.ReviewSort) []model.UserID {
+		var res []model.UserID
+		var after *model.ReviewsCursor
+		for {
+			page, err := r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, sort, after, 1)
+			require.NoError(t, err)
+			if len(page) == 0 {
+				return res
+			}
+			last := page[0]
+			res = append(res, last.UserID)
+			after = &model.ReviewsCursor{
+				HelpfulVotes:   last.Review.HelpfulVotes,
+				UnhelpfulVotes: last.Review.UnhelpfulVotes,
+				ReviewedAt:     last.Review.ReviewedAt,
+				UserID:         last.UserID,
+			}
+		}
+	}
+	assert.Equal(t, []model.UserID{"user3", "user2", "user1", "user0"}, list(model.ReviewSortNewest))
+	assert.Equal(t, []model.UserID{"user0", "user2", "user1", "user3"}, list(model.ReviewSortHelpful))
+
+	helpful, err := r.ListReviews(ctx, "1", model.RecordTypeMovie, model.ReviewApproved, model.ReviewSortHelpful, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), helpful[0].Review.HelpfulVotes)
+	assert.Equal(t, int64(1), helpful[2].Review.HelpfulVotes)
+	assert.Equal(t, int64(1), helpful[2].Review.UnhelpfulVotes)
+}
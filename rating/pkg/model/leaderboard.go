@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Leaderboard is a way of ranking records by their ratings.
+type Leaderboard string
+
+const (
+	// LeaderboardTopRated ranks records by mean rating, then by number of ratings.
+	LeaderboardTopRated Leaderboard = "top_rated"
+	// LeaderboardTrending ranks records by number of ratings, then by mean rating.
+	LeaderboardTrending Leaderboard = "trending"
+)
+
+// LeaderboardQuery selects the records ranked on a leaderboard.
+type LeaderboardQuery struct {
+	// RecordType restricts the leaderboard to one type of records, or allows all if empty.
+	RecordType RecordType
+	// MinVotes leaves out records with fewer ratings in the window.
+	MinVotes int64
+	// Window only counts the ratings given or changed in this time before now, or all ratings if zero.
+	Window time.Duration
+	// Limit is the number of records returned at most.
+	Limit int
+}
+
+// LeaderboardEntry is a record on a leaderboard with the ratings that placed it there. Records
+// that tie share a rank and the rank after them is skipped.
+type LeaderboardEntry struct {
+	Rank       int64      `json:"rank"`
+	RecordID   RecordID   `json:"record_id"`
+	RecordType RecordType `json:"record_type"`
+	Count      int64      `json:"count"`
+	Mean       float64    `json:"mean"`
+}
@@ -1 +1,79 @@
 package model
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"movieexample.com/gen"
+)
+
+// AggregateToProto converts a RatingAggregate to its proto representation.
+func AggregateToProto(a *RatingAggregate) *gen.GetAggregatedRatingResponse {
+	res := &gen.GetAggregatedRatingResponse{
+		RatingValue: a.Mean,
+		Count:       a.Count,
+		Median:      a.Median,
+		Stddev:      a.StdDev,
+		Histogram:   make(map[int32]int64, len(a.Histogram)),
+		Score:       a.Score,
+	}
+	for v, n := range a.Histogram {
+		res.Histogram[int32(v)] = n
+	}
+	return res
+}
+
+// LeaderboardToProto converts leaderboard entries to their proto representation.
+func LeaderboardToProto(entries []LeaderboardEntry) []*gen.LeaderboardEntry {
+	res := make([]*gen.LeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, &gen.LeaderboardEntry{
+			Rank:       e.Rank,
+			RecordId:   string(e.RecordID),
+			RecordType: string(e.RecordType),
+			Count:      e.Count,
+			Mean:       e.Mean,
+		})
+	}
+	return res
+}
+
+// LeaderboardFromProto converts proto leaderboard entries to their model representation.
+func LeaderboardFromProto(entries []*gen.LeaderboardEntry) []LeaderboardEntry {
+	res := make([]LeaderboardEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, LeaderboardEntry{
+			Rank:       e.Rank,
+			RecordID:   RecordID(e.RecordId),
+			RecordType: RecordType(e.RecordType),
+			Count:      e.Count,
+			Mean:       e.Mean,
+		})
+	}
+	return res
+}
+
+// UserRatingToProto converts a rating listed for its user to its proto representation.
+func UserRatingToProto(r *Rating) *gen.UserRating {
+	return &gen.UserRating{
+		RecordId:    string(r.MovieID),
+		RecordType:  string(r.RecordType),
+		RatingValue: int32(r.Value),
+		CreatedAt:   timestamppb.New(r.CreatedAt),
+		UpdatedAt:   timestamppb.New(r.UpdatedAt),
+	}
+}
+
+// ReviewToProto converts a rating listed with its review to the proto representation of the review.
+func ReviewToProto(r *Rating) *gen.Review {
+	return &gen.Review{
+		UserId:           string(r.UserID),
+		RatingValue:      int32(r.Value),
+		Title:            r.Review.Title,
+		Text:             r.Review.Text,
+		Spoiler:          r.Review.Spoiler,
+		HelpfulVotes:     r.Review.HelpfulVotes,
+		UnhelpfulVotes:   r.Review.UnhelpfulVotes,
+		ReviewedAt:       timestamppb.New(r.Review.ReviewedAt),
+		State:            string(r.Review.State),
+		ModerationReason: r.Review.ModerationReason,
+	}
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // RecordTypeMovie is a constant representing the "movie" record type.
 const (
 	RecordTypeMovie = RecordType("movie")
@@ -23,8 +25,57 @@ type Rating struct {
 	UserID  UserID      `json:"user_id"`
 	MovieID RecordID    `json:"movie_id"`
 	Value   RatingValue `json:"rating"`
+	// RecordType is the type of the record MovieID identifies. It is set when listing the ratings of a user.
+	RecordType RecordType `json:"record_type,omitempty"`
+	// CreatedAt is when the user first rated the record and UpdatedAt when they last did.
+	// Both are set by the repository.
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Review is the review the user wrote along with the rating, if any. It is set when listing reviews.
+	Review *Review `json:"review,omitempty"`
+}
+
+// UserRatingsCursor is the position of a rating in the ratings of a user, which are listed by
+// UpdatedAt, RecordType and RecordID, all descending. A page listed after a cursor starts with the
+// rating right after it, so ratings changed in between neither shift nor repeat the next page.
+type UserRatingsCursor struct {
+	UpdatedAt  time.Time  `json:"updated_at"`
+	RecordType RecordType `json:"record_type"`
+	RecordID   RecordID   `json:"record_id"`
+}
+
+// RatingAggregate summarizes the ratings of a record.
+type RatingAggregate struct {
+	// Count is the number of ratings.
+	Count int64 `json:"count"`
+	// Mean is the average rating.
+	Mean float64 `json:"mean"`
+	// Median is the middle rating, or the mean of the two middle ones for an even count.
+	Median float64 `json:"median"`
+	// StdDev is the population standard deviation of the ratings.
+	StdDev float64 `json:"stddev"`
+	// Histogram counts the ratings of each value given at least once.
+	Histogram map[RatingValue]int64 `json:"histogram"`
+	// Score is the ranking score of the record under Ranking, when one was asked for.
+	Score   *float64       `json:"score,omitempty"`
+	Ranking RankingFormula `json:"ranking,omitempty"`
 }
 
+// RankingFormula is a way of turning the ratings of a record into a score to rank records by,
+// which unlike the mean accounts for how many ratings there are or how recent they are.
+type RankingFormula string
+
+const (
+	// RankingBayesian is the mean shrunk towards the mean of all records of the type, as if the
+	// record had a fixed number of extra ratings at that global mean.
+	RankingBayesian RankingFormula = "bayesian"
+	// RankingWilson is the lower bound of the Wilson score interval of the share of the rating
+	// scale the ratings reach, mapped back onto the scale.
+	RankingWilson RankingFormula = "wilson"
+	// RankingTimeDecay is the mean with every rating weighed down by half each half-life of age.
+	RankingTimeDecay RankingFormula = "time_decay"
+)
+
 type RatingEventType string
 
 const (
@@ -32,9 +83,12 @@ const (
 	RatingEventDelete RatingEventType = "delete"
 )
 
+// RatingEvent is a change to the rating of a user for a record. Events without an event type
+// are puts, as produced before deletes were supported; delete events carry no value.
 type RatingEvent struct {
-	UserID     UserID      `json:"userId"`
-	RecordID   RecordID    `json:"recordId"`
-	RecordType RecordType  `json:"recordType"`
-	Value      RatingValue `json:"value"`
+	UserID     UserID          `json:"userId"`
+	RecordID   RecordID        `json:"recordId"`
+	RecordType RecordType      `json:"recordType"`
+	Value      RatingValue     `json:"value"`
+	EventType  RatingEventType `json:"eventType,omitempty"`
 }
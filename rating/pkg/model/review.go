@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Review is the text a user wrote about a record they rated.
+type Review struct {
+	Title string `json:"title,omitempty"`
+	Text  string `json:"text"`
+	// Spoiler marks a review that gives away the plot, for clients to hide by default.
+	Spoiler bool `json:"spoiler"`
+	// State is where the review stands in moderation; only approved reviews are shown to everyone.
+	State ReviewState `json:"state"`
+	// ModerationReason explains why the review is pending or rejected.
+	ModerationReason string `json:"moderation_reason,omitempty"`
+	// ReviewedAt is when the review was last written. It is set by the repository.
+	ReviewedAt time.Time `json:"reviewed_at"`
+	// HelpfulVotes and UnhelpfulVotes count the votes other users cast on the review.
+	// They are set when listing reviews.
+	HelpfulVotes   int64 `json:"helpful_votes"`
+	UnhelpfulVotes int64 `json:"unhelpful_votes"`
+}
+
+// ReviewState is where a review stands in moderation. Reviews are checked as they are written
+// and either approved, rejected, or left pending for a moderator to decide.
+type ReviewState string
+
+const (
+	ReviewPending  ReviewState = "pending"
+	ReviewApproved ReviewState = "approved"
+	ReviewRejected ReviewState = "rejected"
+)
+
+// ReviewSort is an order of the reviews of a record.
+type ReviewSort string
+
+const (
+	// ReviewSortNewest lists the most recently written reviews first.
+	ReviewSortNewest ReviewSort = "newest"
+	// ReviewSortHelpful lists the reviews with the most helpful votes first, then the ones with
+	// the fewest unhelpful votes, then the newest.
+	ReviewSortHelpful ReviewSort = "helpful"
+)
+
+// ReviewsCursor is the position of a review in the reviews of a record, which are listed by
+// ReviewedAt then author, both descending, and with ReviewSortHelpful by helpful votes, descending,
+// and unhelpful votes, ascending, first. A page listed after a cursor starts with the review right
+// after it. Votes cast while paging with ReviewSortHelpful may still move a review across pages.
+type ReviewsCursor struct {
+	HelpfulVotes   int64     `json:"helpful_votes,omitempty"`
+	UnhelpfulVotes int64     `json:"unhelpful_votes,omitempty"`
+	ReviewedAt     time.Time `json:"reviewed_at"`
+	UserID         UserID    `json:"user_id"`
+}
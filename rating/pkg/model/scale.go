@@ -0,0 +1,17 @@
+package model
+
+// RatingScale is the range of rating values accepted for a type of records, for clients to
+// render the matching widget.
+type RatingScale struct {
+	// Min and Max are the lowest and highest rating values.
+	Min RatingValue `json:"min"`
+	Max RatingValue `json:"max"`
+	// HalfStars marks a scale whose values count half stars, so that a rating of 7 on a 1-10
+	// scale is shown as 3.5 out of 5 stars.
+	HalfStars bool `json:"half_stars"`
+}
+
+// Contains reports whether v is a rating value of the scale.
+func (s RatingScale) Contains(v RatingValue) bool {
+	return v >= s.Min && v <= s.Max
+}
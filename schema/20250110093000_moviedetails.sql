@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Movie
+    ADD COLUMN IF NOT EXISTS runtime_minutes INT,
+    ADD COLUMN IF NOT EXISTS release_date DATE,
+    ADD COLUMN IF NOT EXISTS original_language VARCHAR(35),
+    ADD COLUMN IF NOT EXISTS country VARCHAR(64);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE Movie
+    DROP COLUMN IF EXISTS runtime_minutes,
+    DROP COLUMN IF EXISTS release_date,
+    DROP COLUMN IF EXISTS original_language,
+    DROP COLUMN IF EXISTS country;
+
+-- +goose StatementEnd
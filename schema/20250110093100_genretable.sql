@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE
+    IF NOT EXISTS genres (
+        id SERIAL PRIMARY KEY,
+        name VARCHAR(64) NOT NULL UNIQUE
+    );
+
+CREATE TABLE
+    IF NOT EXISTS movie_genres (
+        movie_id VARCHAR(255) NOT NULL REFERENCES Movie (ID) ON DELETE CASCADE,
+        genre_id INT NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+        PRIMARY KEY (movie_id, genre_id)
+    );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop TABLE IF EXISTS movie_genres;
+
+drop TABLE IF EXISTS genres;
+
+-- +goose StatementEnd
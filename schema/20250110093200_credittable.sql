@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE
+    IF NOT EXISTS movie_credits (
+        movie_id VARCHAR(255) NOT NULL REFERENCES Movie (ID) ON DELETE CASCADE,
+        person_name VARCHAR(255) NOT NULL,
+        role VARCHAR(64) NOT NULL,
+        character_name VARCHAR(255),
+        billing_order INT NOT NULL DEFAULT 0,
+        PRIMARY KEY (movie_id, person_name, role)
+    );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop TABLE IF EXISTS movie_credits;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Movie
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE Movie
+    DROP COLUMN IF EXISTS deleted_at;
+
+-- +goose StatementEnd
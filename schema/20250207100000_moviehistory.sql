@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS movie_revisions (
+    movie_id VARCHAR(255) NOT NULL REFERENCES Movie (ID) ON DELETE CASCADE,
+    version BIGINT NOT NULL,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    changes JSONB NOT NULL,
+    snapshot JSONB NOT NULL,
+    PRIMARY KEY (movie_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS movie_revisions_created_at_idx ON movie_revisions (movie_id, created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_revisions;
+
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS movie_external_ids (
+    movie_id VARCHAR(255) NOT NULL REFERENCES Movie (ID) ON DELETE CASCADE,
+    provider VARCHAR(16) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    PRIMARY KEY (movie_id, provider),
+    UNIQUE (provider, external_id)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_external_ids;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS movie_relations (
+    movie_id VARCHAR(255) NOT NULL REFERENCES Movie (ID) ON DELETE CASCADE,
+    relation_type VARCHAR(32) NOT NULL,
+    related_id VARCHAR(255) NOT NULL REFERENCES Movie (ID) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (movie_id, relation_type, related_id),
+    CHECK (movie_id <> related_id)
+);
+
+CREATE INDEX IF NOT EXISTS movie_relations_related_idx ON movie_relations (related_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movie_relations;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- keep one of the duplicate ratings a user gave a record before ratings were unique; ratings have
+-- no timestamp to tell the latest apart, so which one survives is arbitrary
+DELETE FROM ratings a
+USING ratings b
+WHERE a.record_id = b.record_id
+  AND a.record_type = b.record_type
+  AND a.user_id = b.user_id
+  AND a.ctid < b.ctid;
+
+DELETE FROM ratings
+WHERE record_id IS NULL
+   OR record_type IS NULL
+   OR user_id IS NULL;
+
+ALTER TABLE ratings
+    ALTER COLUMN record_id SET NOT NULL,
+    ALTER COLUMN record_type SET NOT NULL,
+    ALTER COLUMN user_id SET NOT NULL,
+    ADD CONSTRAINT ratings_record_user_key UNIQUE (record_id, record_type, user_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ratings
+    DROP CONSTRAINT IF EXISTS ratings_record_user_key,
+    ALTER COLUMN record_id DROP NOT NULL,
+    ALTER COLUMN record_type DROP NOT NULL,
+    ALTER COLUMN user_id DROP NOT NULL;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE
+    IF NOT EXISTS rating_aggregates (
+        record_id VARCHAR(255) NOT NULL,
+        record_type VARCHAR(255) NOT NULL,
+        ratings_count BIGINT NOT NULL DEFAULT 0,
+        ratings_sum BIGINT NOT NULL DEFAULT 0,
+        PRIMARY KEY (record_id, record_type)
+    );
+
+CREATE TABLE
+    IF NOT EXISTS rating_histograms (
+        record_id VARCHAR(255) NOT NULL,
+        record_type VARCHAR(255) NOT NULL,
+        value INT NOT NULL,
+        ratings BIGINT NOT NULL DEFAULT 0,
+        PRIMARY KEY (record_id, record_type, value),
+        FOREIGN KEY (record_id, record_type) REFERENCES rating_aggregates (record_id, record_type) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS rating_histograms_record_type_idx ON rating_histograms (record_type);
+
+INSERT INTO rating_aggregates (record_id, record_type, ratings_count, ratings_sum)
+SELECT record_id, record_type, count(*), sum(value)
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type;
+
+INSERT INTO rating_histograms (record_id, record_type, value, ratings)
+SELECT record_id, record_type, value, count(*)
+FROM ratings
+WHERE value IS NOT NULL
+GROUP BY record_id, record_type, value;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rating_histograms;
+
+DROP TABLE IF EXISTS rating_aggregates;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE ratings
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- ratings given before the column existed were last changed at updated_at, the best known bound
+UPDATE ratings
+SET created_at = updated_at;
+
+CREATE INDEX IF NOT EXISTS ratings_user_id_idx ON ratings (user_id, updated_at DESC);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS ratings_user_id_idx;
+
+ALTER TABLE ratings
+    DROP COLUMN IF EXISTS created_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE ratings
+    ADD COLUMN IF NOT EXISTS review_title TEXT,
+    ADD COLUMN IF NOT EXISTS review_text TEXT,
+    ADD COLUMN IF NOT EXISTS review_spoiler BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS ratings_reviewed_idx ON ratings (record_id, record_type, reviewed_at DESC)
+WHERE review_text IS NOT NULL;
+
+CREATE TABLE
+    IF NOT EXISTS review_votes (
+        record_id VARCHAR(255) NOT NULL,
+        record_type VARCHAR(255) NOT NULL,
+        author_id VARCHAR(255) NOT NULL,
+        voter_id VARCHAR(255) NOT NULL,
+        helpful BOOLEAN NOT NULL,
+        PRIMARY KEY (record_id, record_type, author_id, voter_id),
+        FOREIGN KEY (record_id, record_type, author_id) REFERENCES ratings (record_id, record_type, user_id) ON DELETE CASCADE,
+        CHECK (author_id <> voter_id)
+    );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_votes;
+
+DROP INDEX IF EXISTS ratings_reviewed_idx;
+
+ALTER TABLE ratings
+    DROP COLUMN IF EXISTS review_title,
+    DROP COLUMN IF EXISTS review_text,
+    DROP COLUMN IF EXISTS review_spoiler,
+    DROP COLUMN IF EXISTS reviewed_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE ratings
+    ADD COLUMN IF NOT EXISTS review_state VARCHAR(16) CHECK (review_state IN ('pending', 'approved', 'rejected')),
+    ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+
+-- reviews written before moderation were already public
+UPDATE ratings
+SET review_state = 'approved'
+WHERE review_text IS NOT NULL;
+
+DROP INDEX IF EXISTS ratings_reviewed_idx;
+
+CREATE INDEX IF NOT EXISTS ratings_review_state_idx ON ratings (record_id, record_type, review_state, reviewed_at DESC)
+WHERE review_text IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS ratings_review_state_idx;
+
+CREATE INDEX IF NOT EXISTS ratings_reviewed_idx ON ratings (record_id, record_type, reviewed_at DESC)
+WHERE review_text IS NOT NULL;
+
+ALTER TABLE ratings
+    DROP COLUMN IF EXISTS review_state,
+    DROP COLUMN IF EXISTS moderation_reason;
+
+-- +goose StatementEnd
@@ -8,7 +8,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"movieexample.com/gen"
 	metadatatest "movieexample.com/metadata/pkg/testutil"
 	movietest "movieexample.com/movie/pkg/testutil"
@@ -65,10 +67,13 @@ func main() {
 		Description: "A great movie",
 		Director:    "John Doe",
 	}
-	_, err = metadataClient.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: m})
+	putMetadataRes, err := metadataClient.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: m})
 	if err != nil {
 		log.Fatalf("Failed to save metadata: %v", err)
 	}
+	m.Version = putMetadataRes.Version
+	// the slug is derived from the title by the metadata service
+	m.Slug = "the-movie"
 
 	log.Println("Retrieving metadata via metadata service")
 
@@ -87,14 +92,18 @@ func main() {
 	const userID = "user0"
 	const recordTypeMovie = "movie"
 	firstRating := int32(5)
-	if _, err := ratingClient.PutRating(ctx, &gen.PutRatingRequest{
+	putRatingRes, err := ratingClient.PutRating(ctx, &gen.PutRatingRequest{
 		UserId:      userID,
 		RecordType:  recordTypeMovie,
 		RecordId:    m.Id,
 		RatingValue: firstRating,
-	}); err != nil {
+	})
+	if err != nil {
 		log.Fatalf("Failed to save rating: %v", err)
 	}
+	if !putRatingRes.Created {
+		log.Fatalf("First rating of %s was not reported as created", userID)
+	}
 
 	log.Println("Retrieving aggregated rating via rating service")
 	getAggregatedRatingResponse, err := ratingClient.GetAggregatedRating(ctx, &gen.GetAggregatedRatingRequest{
@@ -108,7 +117,6 @@ func main() {
 	if got, want := getAggregatedRatingResponse.RatingValue, float64(firstRating); got != want {
 		log.Fatalf("Aggregated rating mismatch (-want +got):\n%s", cmp.Diff(want, got))
 	}
-	log.Println("Saving second rating via rating service")
 
 	getMovieDetailsRes, err := movieClient.GetMovieDetails(ctx, &gen.GetMovieDetailsRequest{
 		MovieId: m.Id,
@@ -125,16 +133,102 @@ func main() {
 		log.Fatalf("Movie details mismatch (-want +got):\n%s", diff)
 	}
 
+	log.Println("Replacing the rating via rating service")
 	secondRating := int32(1)
-	if _, err := ratingClient.PutRating(ctx, &gen.PutRatingRequest{
+	putRatingRes, err = ratingClient.PutRating(ctx, &gen.PutRatingRequest{
 		UserId:      userID,
 		RecordType:  recordTypeMovie,
 		RecordId:    m.Id,
 		RatingValue: secondRating,
-	},
-	); err != nil {
+	})
+	if err != nil {
 		log.Fatalf("Failed to save rating: %v", err)
 	}
+	if putRatingRes.Created {
+		log.Fatalf("Second rating of %s was not reported as replacing the first", userID)
+	}
+
+	getUserRatingRes, err := ratingClient.GetUserRating(ctx, &gen.GetUserRatingRequest{
+		UserId:     userID,
+		RecordType: recordTypeMovie,
+		RecordId:   m.Id,
+	})
+	if err != nil {
+		log.Fatalf("Failed to retrieve user rating: %v", err)
+	}
+	if got, want := getUserRatingRes.RatingValue, secondRating; got != want {
+		log.Fatalf("User rating mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	log.Println("Checking the rating scale via rating service")
+	scaleRes, err := ratingClient.GetRatingScale(ctx, &gen.GetRatingScaleRequest{RecordType: recordTypeMovie})
+	if err != nil {
+		log.Fatalf("Failed to get rating scale: %v", err)
+	}
+	if scaleRes.Min != 1 || scaleRes.Max != 5 {
+		log.Fatalf("Rating scale %d-%d, want 1-5", scaleRes.Min, scaleRes.Max)
+	}
+	if _, err := ratingClient.PutRating(ctx, &gen.PutRatingRequest{
+		UserId:      userID,
+		RecordId:    m.Id,
+		RecordType:  recordTypeMovie,
+		RatingValue: scaleRes.Max + 1,
+	}); status.Code(err) != codes.InvalidArgument {
+		log.Fatalf("Put rating off the scale: got %v, want InvalidArgument", err)
+	}
+
+	log.Println("Listing the ratings of the user via rating service")
+	listUserRatingsRes, err := ratingClient.ListUserRatings(ctx, &gen.ListUserRatingsRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		log.Fatalf("Failed to list user ratings: %v", err)
+	}
+	if n := len(listUserRatingsRes.Ratings); n != 1 {
+		log.Fatalf("Listed %d ratings of %s, want 1", n, userID)
+	}
+	userRating := listUserRatingsRes.Ratings[0]
+	if userRating.RecordId != m.Id || userRating.RatingValue != secondRating || userRating.UpdatedAt.AsTime().Before(userRating.CreatedAt.AsTime()) {
+		log.Fatalf("Listed rating mismatch: %v", userRating)
+	}
+
+	log.Println("Reviewing the movie via rating service")
+	putReviewRes, err := ratingClient.PutReview(ctx, &gen.PutReviewRequest{
+		UserId:     userID,
+		RecordId:   m.Id,
+		RecordType: recordTypeMovie,
+		Title:      "Worth it",
+		Text:       "A fine test movie with a fitting end.",
+	})
+	if err != nil {
+		log.Fatalf("Failed to put review: %v", err)
+	}
+	if putReviewRes.State != "approved" {
+		log.Fatalf("Review state %q, want approved: %s", putReviewRes.State, putReviewRes.ModerationReason)
+	}
+	if _, err := ratingClient.VoteReview(ctx, &gen.VoteReviewRequest{
+		UserId:     "user1",
+		AuthorId:   userID,
+		RecordId:   m.Id,
+		RecordType: recordTypeMovie,
+		Helpful:    true,
+	}); err != nil {
+		log.Fatalf("Failed to vote on review: %v", err)
+	}
+	listReviewsRes, err := ratingClient.ListReviews(ctx, &gen.ListReviewsRequest{
+		RecordId:   m.Id,
+		RecordType: recordTypeMovie,
+		Sort:       "helpful",
+	})
+	if err != nil {
+		log.Fatalf("Failed to list reviews: %v", err)
+	}
+	if n := len(listReviewsRes.Reviews); n != 1 {
+		log.Fatalf("Listed %d reviews, want 1", n)
+	}
+	if review := listReviewsRes.Reviews[0]; review.UserId != userID || review.Title != "Worth it" || review.RatingValue != secondRating || review.HelpfulVotes != 1 {
+		log.Fatalf("Listed review mismatch: %v", review)
+	}
 	log.Println("Retrieving second aggregated rating via rating service")
 
 	getAggregatedRatingResponse, err = ratingClient.GetAggregatedRating(ctx, &gen.GetAggregatedRatingRequest{
@@ -144,7 +238,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to retrieve aggregated rating: %v", err)
 	}
-	if got, want := getAggregatedRatingResponse.RatingValue, float64((firstRating+secondRating)/2); got != want {
+	if got, want := getAggregatedRatingResponse.RatingValue, float64(secondRating); got != want {
 		log.Fatalf("Aggregated rating mismatch (-want +got):\n%s", cmp.Diff(want, got))
 	}
 
@@ -164,6 +258,24 @@ func main() {
 		log.Fatalf("Movie details mismatch (-want +got):\n%s", diff)
 	}
 
+	log.Println("Retrieving the top rated movies via movie service")
+	wantLeaderboard := []*gen.LeaderboardMovie{{
+		Rank:     1,
+		Metadata: m,
+		Rating:   getAggregatedRatingResponse.RatingValue,
+		Count:    1,
+	}}
+
+	getMovieLeaderboardRes, err := movieClient.GetMovieLeaderboard(ctx, &gen.GetMovieLeaderboardRequest{
+		Leaderboard: "top_rated",
+	})
+	if err != nil {
+		log.Fatalf("Failed to retrieve the top rated movies: %v", err)
+	}
+	if diff := cmp.Diff(getMovieLeaderboardRes.Movies, wantLeaderboard, cmpopts.IgnoreUnexported(gen.LeaderboardMovie{}, gen.Metadata{})); diff != "" {
+		log.Fatalf("Top rated movies mismatch (-want +got):\n%s", diff)
+	}
+
 	log.Println("Integration tests passed!")
 }
 